@@ -0,0 +1,62 @@
+// Copyright 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"knative.dev/kn-plugin-operator/pkg"
+)
+
+// newInterruptibleContext returns the root context of the operations, which is canceled on SIGINT or SIGTERM.
+// Another signal after the cancellation terminates the plugin immediately.
+func newInterruptibleContext() context.Context {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx
+}
+
+// reportInterruption wraps the commands, so that the step being run is reported when a command fails
+// because the operation is interrupted.
+func reportInterruption(cmd *cobra.Command, p *pkg.OperatorParams) {
+	if cmd.RunE != nil {
+		runE := cmd.RunE
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			err := runE(cmd, args)
+			if err != nil && p.Context().Err() != nil {
+				return interruptedError(p.Step())
+			}
+			return err
+		}
+	}
+	for _, child := range cmd.Commands() {
+		reportInterruption(child, p)
+	}
+}
+
+func interruptedError(step string) error {
+	if step == "" {
+		return fmt.Errorf("The operation was interrupted.")
+	}
+	return fmt.Errorf("The operation was interrupted during the step: %s", step)
+}
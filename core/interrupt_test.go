@@ -0,0 +1,73 @@
+// Copyright 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestReportInterruption(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		cancel        bool
+		step          string
+		expectedError string
+	}{{
+		name:          "Not interrupted",
+		cancel:        false,
+		step:          "Installing Knative Serving, Version 1.8...",
+		expectedError: "failed to install",
+	}, {
+		name:          "Interrupted during a step",
+		cancel:        true,
+		step:          "Installing Knative Serving, Version 1.8...",
+		expectedError: "The operation was interrupted during the step: Installing Knative Serving, Version 1.8...",
+	}, {
+		name:          "Interrupted without any step",
+		cancel:        true,
+		expectedError: "The operation was interrupted.",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			p := &pkg.OperatorParams{Ctx: ctx}
+
+			rootCmd := &cobra.Command{Use: "root"}
+			rootCmd.AddCommand(&cobra.Command{
+				Use: "install",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					p.SetStep(tt.step)
+					if tt.cancel {
+						cancel()
+					}
+					return fmt.Errorf("failed to install")
+				},
+			})
+			reportInterruption(rootCmd, p)
+
+			rootCmd.SetArgs([]string{"install"})
+			rootCmd.SilenceUsage = true
+			rootCmd.SilenceErrors = true
+			err := rootCmd.Execute()
+			testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+		})
+	}
+}
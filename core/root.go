@@ -15,10 +15,16 @@
 package core
 
 import (
+	"os"
+
 	"github.com/spf13/cobra"
 	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/backup"
+	"knative.dev/kn-plugin-operator/pkg/command/completion"
 	"knative.dev/kn-plugin-operator/pkg/command/configure"
 	"knative.dev/kn-plugin-operator/pkg/command/enable"
+	"knative.dev/kn-plugin-operator/pkg/command/features"
+	"knative.dev/kn-plugin-operator/pkg/command/history"
 	"knative.dev/kn-plugin-operator/pkg/command/install"
 	"knative.dev/kn-plugin-operator/pkg/command/remove"
 	"knative.dev/kn-plugin-operator/pkg/command/uninstall"
@@ -28,7 +34,10 @@ var cfgFile string
 
 // operationCmd represents the base command when called without any subcommands
 func NewOperationCommand() *cobra.Command {
-	p := &pkg.OperatorParams{}
+	p := &pkg.OperatorParams{
+		Ctx:    newInterruptibleContext(),
+		ErrOut: os.Stderr,
+	}
 	p.Initialize()
 	rootCmd := &cobra.Command{
 		Use:   "kn operator",
@@ -41,10 +50,38 @@ kn operator install -c eventing
 `,
 	}
 
+	addKubeConfigFlags(rootCmd, p)
+	rootCmd.PersistentFlags().BoolVar(&p.ForceConflicts, "force-conflicts", false,
+		"Take over the fields managed by other field managers, when the resources are applied")
+
 	rootCmd.AddCommand(install.NewInstallCommand(p))
+	rootCmd.AddCommand(install.NewRollbackCommand(p))
 	rootCmd.AddCommand(uninstall.NewUninstallCommand(p))
 	rootCmd.AddCommand(enable.NewEnableCommand(p))
 	rootCmd.AddCommand(configure.NewConfigureCommand(p))
 	rootCmd.AddCommand(remove.NewRemoveCommand(p))
+	rootCmd.AddCommand(features.NewFeaturesCommand(p))
+	rootCmd.AddCommand(history.NewHistoryCommand(p))
+	rootCmd.AddCommand(history.NewUndoCommand(p))
+	rootCmd.AddCommand(backup.NewBackupCommand(p))
+	rootCmd.AddCommand(backup.NewRestoreCommand(p))
+	rootCmd.AddCommand(completion.NewCompletionCommand())
+	// The completion command above replaces the default one generated by cobra
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	completion.Register(rootCmd, p)
+	reportInterruption(rootCmd, p)
 	return rootCmd
 }
+
+// addKubeConfigFlags adds the flags to choose the kubeconfig, and to override the context, the cluster,
+// the user and the impersonation loaded from it
+func addKubeConfigFlags(rootCmd *cobra.Command, p *pkg.OperatorParams) {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&p.KubeCfgPath, "kubeconfig", "", "The kubeconfig of the Knative resources (default is KUBECONFIG from environment variable)")
+	flags.StringVar(&p.ConfigOverrides.CurrentContext, "context", "", "The name of the kubeconfig context to use")
+	flags.StringVar(&p.ConfigOverrides.Context.Cluster, "cluster", "", "The name of the kubeconfig cluster to use")
+	flags.StringVar(&p.ConfigOverrides.Context.AuthInfo, "user", "", "The name of the kubeconfig user to use")
+	flags.StringVar(&p.ConfigOverrides.AuthInfo.Impersonate, "as", "", "The username to impersonate for the operation")
+	flags.StringArrayVar(&p.ConfigOverrides.AuthInfo.ImpersonateGroups, "as-group", []string{},
+		"The group to impersonate for the operation, this flag can be repeated to specify multiple groups")
+}
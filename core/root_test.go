@@ -0,0 +1,46 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestKubeConfigFlags(t *testing.T) {
+	p := &pkg.OperatorParams{}
+	rootCmd := &cobra.Command{Use: "kn operator"}
+	addKubeConfigFlags(rootCmd, p)
+	rootCmd.AddCommand(&cobra.Command{
+		Use: "install",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	})
+
+	rootCmd.SetArgs([]string{"install", "--kubeconfig", "/tmp/config", "--context", "staging", "--cluster", "east",
+		"--user", "admin", "--as", "jane", "--as-group", "devs", "--as-group", "ops"})
+	testingUtil.AssertEqual(t, rootCmd.Execute(), nil)
+
+	testingUtil.AssertEqual(t, p.KubeCfgPath, "/tmp/config")
+	testingUtil.AssertEqual(t, p.ConfigOverrides.CurrentContext, "staging")
+	testingUtil.AssertEqual(t, p.ConfigOverrides.Context.Cluster, "east")
+	testingUtil.AssertEqual(t, p.ConfigOverrides.Context.AuthInfo, "admin")
+	testingUtil.AssertEqual(t, p.ConfigOverrides.AuthInfo.Impersonate, "jane")
+	testingUtil.AssertDeepEqual(t, p.ConfigOverrides.AuthInfo.ImpersonateGroups, []string{"devs", "ops"})
+}
@@ -7,7 +7,9 @@ require (
 	github.com/ghodss/yaml v1.0.0
 	github.com/k14s/ytt v0.39.0
 	github.com/manifestival/client-go-client v0.6.0
+	github.com/manifestival/manifestival v0.7.2
 	github.com/spf13/cobra v1.8.1
+	github.com/spf13/pflag v1.0.10
 	golang.org/x/mod v0.29.0
 	k8s.io/api v0.33.5
 	k8s.io/apimachinery v0.33.5
@@ -41,14 +43,12 @@ require (
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/k14s/starlark-go v0.0.0-20200720175618-3a5c849cc368 // indirect
 	github.com/mailru/easyjson v0.9.0 // indirect
-	github.com/manifestival/manifestival v0.7.2 // indirect
 	github.com/mattn/go-colorable v0.1.12 // indirect
 	github.com/mattn/go-isatty v0.0.14 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/spf13/pflag v1.0.10 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.63.0 // indirect
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client provides the API to install and configure the Knative Operator and the Knative components
+// programmatically. The commands of the plugin are thin wrappers over the same functions.
+package client
+
+import (
+	"context"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/configure"
+	"knative.dev/kn-plugin-operator/pkg/command/enable"
+	"knative.dev/kn-plugin-operator/pkg/command/install"
+)
+
+// Client installs and configures the Knative Operator and the Knative components in a Kubernetes cluster.
+// The functions do not share any state, so that a client can be used for multiple operations.
+type Client struct {
+	params *pkg.OperatorParams
+}
+
+// New returns a client accessing the Kubernetes cluster with the params. The kube client and the operator
+// client injected in the params are used, otherwise they are created from the kubeconfig.
+func New(p *pkg.OperatorParams) *Client {
+	p.Initialize()
+	return &Client{params: p}
+}
+
+// Install installs the Knative Operator, or the Knative component set in the options. Set Wait in the options
+// to return only after the Knative component is ready.
+func (c *Client) Install(ctx context.Context, options InstallOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return install.Install(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureResources configures the resource requests and limits for a container of a Knative deployment
+func (c *Client) ConfigureResources(ctx context.Context, options ResourceOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureResources(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureHA configures the number of the replicas for a Knative component or a Knative deployment
+func (c *Client) ConfigureHA(ctx context.Context, options HAOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureHA(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureImages configures the images for a Knative component or a Knative deployment
+func (c *Client) ConfigureImages(ctx context.Context, options ImageOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureImages(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureEnvVars configures the environment variable for a container of a Knative deployment
+func (c *Client) ConfigureEnvVars(ctx context.Context, options EnvVarOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureEnvVars(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureTolerations configures the toleration for a Knative deployment
+func (c *Client) ConfigureTolerations(ctx context.Context, options TolerationOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureTolerations(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureManifests configures the custom manifests for a Knative component
+func (c *Client) ConfigureManifests(ctx context.Context, options ManifestsOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureManifests(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureConfigMaps configures the data of a ConfigMap for a Knative component
+func (c *Client) ConfigureConfigMaps(ctx context.Context, options ConfigMapOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureConfigMaps(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureLabels configures the label for a Knative deployment or service
+func (c *Client) ConfigureLabels(ctx context.Context, options KeyValueOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureLabels(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureAnnotations configures the annotation for a Knative deployment or service
+func (c *Client) ConfigureAnnotations(ctx context.Context, options KeyValueOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureAnnotations(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureNodeSelectors configures the node selector for a Knative deployment
+func (c *Client) ConfigureNodeSelectors(ctx context.Context, options KeyValueOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureNodeSelectors(options.flags(), c.params.WithContext(ctx))
+}
+
+// ConfigureSelectors configures the selector for a Knative service
+func (c *Client) ConfigureSelectors(ctx context.Context, options KeyValueOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return configure.ConfigureSelectors(options.flags(), c.params.WithContext(ctx))
+}
+
+// EnableIngress enables an ingress for Knative Serving
+func (c *Client) EnableIngress(ctx context.Context, options IngressOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return enable.EnableIngress(options.flags(), c.params.WithContext(ctx))
+}
+
+// EnableEventingSources enables the eventing sources for Knative Eventing
+func (c *Client) EnableEventingSources(ctx context.Context, options EventingSourceOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return enable.EnableEventingSources(options.flags(), c.params.WithContext(ctx))
+}
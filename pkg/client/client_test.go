@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := New(&pkg.OperatorParams{})
+	err := client.Install(ctx, InstallOptions{Component: "serving"})
+	testingUtil.AssertEqual(t, err, context.Canceled)
+	err = client.ConfigureResources(ctx, ResourceOptions{})
+	testingUtil.AssertEqual(t, err, context.Canceled)
+}
+
+func TestConfigureResourcesValidation(t *testing.T) {
+	client := New(&pkg.OperatorParams{})
+	for _, tt := range []struct {
+		name          string
+		options       ResourceOptions
+		expectedError string
+	}{{
+		name:          "No resource",
+		options:       ResourceOptions{Component: "serving", Namespace: "knative-serving"},
+		expectedError: "You need to specify at least one resource parameter: limitCPU, limitMemory, requestCPU or requestMemory.",
+	}, {
+		name:          "No container",
+		options:       ResourceOptions{RequestCPU: "200m", Component: "serving", Namespace: "knative-serving"},
+		expectedError: "You need to specify the container name.",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ConfigureResources(context.Background(), tt.options)
+			testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestInstallIngressValidation(t *testing.T) {
+	client := New(&pkg.OperatorParams{})
+	err := client.Install(context.Background(), InstallOptions{Component: "eventing", Kourier: true})
+	testingUtil.AssertEqual(t, err.Error(), "You can only specify the ingress for Knative Serving.")
+}
+
+func TestOptionsFlags(t *testing.T) {
+	installFlags := InstallOptions{Component: "serving", Version: "1.8", Kourier: true, Wait: true}.flags()
+	testingUtil.AssertEqual(t, installFlags.Component, "serving")
+	testingUtil.AssertEqual(t, installFlags.Version, "1.8")
+	testingUtil.AssertEqual(t, installFlags.Kourier, true)
+	testingUtil.AssertEqual(t, installFlags.Wait, true)
+
+	imageFlags := ImageOptions{Component: "serving", Deployment: "activator", Key: "activator", Image: "example.com/activator"}.flags()
+	testingUtil.AssertEqual(t, imageFlags.DeployName, "activator")
+	testingUtil.AssertEqual(t, imageFlags.ImageKey, "activator")
+	testingUtil.AssertEqual(t, imageFlags.ImageUrl, "example.com/activator")
+
+	cmFlags := ConfigMapOptions{Name: "network", KeyValueSources: KeyValueSources{Set: []string{"ingress-class=kourier"}}}.flags()
+	testingUtil.AssertEqual(t, cmFlags.CMName, "network")
+	testingUtil.AssertDeepEqual(t, cmFlags.Set, []string{"ingress-class=kourier"})
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"time"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/configure"
+	"knative.dev/kn-plugin-operator/pkg/command/enable"
+	"knative.dev/kn-plugin-operator/pkg/command/install"
+)
+
+// InstallOptions are the options to install the Knative Operator or the Knative components
+type InstallOptions struct {
+	// Component is the Knative component to install: serving, eventing or both separated by a comma.
+	// The Knative Operator is installed, if neither Component nor All is set.
+	Component string
+	// All installs both Knative Serving and Knative Eventing
+	All bool
+	// Namespace is the namespace of the Knative Operator or the Knative component
+	Namespace string
+	// Version is the version of the Knative Operator or the Knative component. The latest version is used by default.
+	Version string
+	// Istio, Kourier and Contour select the ingress of Knative Serving
+	Istio   bool
+	Kourier bool
+	Contour bool
+	// IstioNamespace is the namespace of Istio
+	IstioNamespace string
+	// OperatorNamespace and OperatorVersion are used to install the Knative Operator, if it is missing for the
+	// Knative components
+	OperatorNamespace string
+	OperatorVersion   string
+	// Wait waits until the Knative component is ready
+	Wait bool
+	// Timeout is the maximum time to wait for the Knative component to be ready
+	Timeout time.Duration
+}
+
+func (o InstallOptions) flags() *install.InstallFlags {
+	return &install.InstallFlags{
+		Component:         o.Component,
+		All:               o.All,
+		Namespace:         o.Namespace,
+		Version:           o.Version,
+		Istio:             o.Istio,
+		Kourier:           o.Kourier,
+		Contour:           o.Contour,
+		IstioNamespace:    o.IstioNamespace,
+		OperatorNamespace: o.OperatorNamespace,
+		OperatorVersion:   o.OperatorVersion,
+		Wait:              o.Wait,
+		Timeout:           o.Timeout,
+	}
+}
+
+// ResourceOptions are the options to configure the resource requests and limits of a container
+type ResourceOptions struct {
+	Component  string
+	Namespace  string
+	Deployment string
+	Container  string
+	// LimitCPU, LimitMemory, RequestCPU and RequestMemory are the quantities of the resources, e.g. 200m or 1Gi.
+	// The empty quantities are not changed.
+	LimitCPU      string
+	LimitMemory   string
+	RequestCPU    string
+	RequestMemory string
+}
+
+func (o ResourceOptions) flags() configure.ResourcesFlags {
+	return configure.ResourcesFlags{
+		Component:     o.Component,
+		Namespace:     o.Namespace,
+		DeployName:    o.Deployment,
+		Container:     o.Container,
+		LimitCPU:      o.LimitCPU,
+		LimitMemory:   o.LimitMemory,
+		RequestCPU:    o.RequestCPU,
+		RequestMemory: o.RequestMemory,
+	}
+}
+
+// HAOptions are the options to configure the number of the replicas
+type HAOptions struct {
+	Component string
+	Namespace string
+	// Deployment is the Knative deployment to configure. The whole Knative component is configured if it is empty.
+	Deployment string
+	Replicas   string
+}
+
+func (o HAOptions) flags() configure.HAFlags {
+	return configure.HAFlags{
+		Component:  o.Component,
+		Namespace:  o.Namespace,
+		DeployName: o.Deployment,
+		Replicas:   o.Replicas,
+	}
+}
+
+// ImageOptions are the options to configure the images
+type ImageOptions struct {
+	Component string
+	Namespace string
+	// Deployment is the Knative deployment to configure. The whole Knative component is configured if it is empty.
+	Deployment string
+	// Key is the key of the image, e.g. the name of the container
+	Key string
+	// Image is the URL of the image
+	Image string
+}
+
+func (o ImageOptions) flags() configure.ImageFlags {
+	return configure.ImageFlags{
+		Component:  o.Component,
+		Namespace:  o.Namespace,
+		DeployName: o.Deployment,
+		ImageKey:   o.Key,
+		ImageUrl:   o.Image,
+	}
+}
+
+// KeyValueSources are the sources of several key-value pairs
+type KeyValueSources struct {
+	// Set are the pairs given as key=value
+	Set []string
+	// FromFile are the files given as [key=]path, each setting the key to the content of the file
+	FromFile []string
+	// FromEnvFile are the files with a key=value pair on each line
+	FromEnvFile []string
+}
+
+func (o KeyValueSources) sources() common.KeyValueSources {
+	return common.KeyValueSources{
+		Set:         o.Set,
+		FromFile:    o.FromFile,
+		FromEnvFile: o.FromEnvFile,
+	}
+}
+
+// EnvVarOptions are the options to configure the environment variables of a container
+type EnvVarOptions struct {
+	Component  string
+	Namespace  string
+	Deployment string
+	Container  string
+	Name       string
+	Value      string
+	KeyValueSources
+}
+
+func (o EnvVarOptions) flags() configure.EnvVarFlags {
+	return configure.EnvVarFlags{
+		Component:       o.Component,
+		Namespace:       o.Namespace,
+		DeployName:      o.Deployment,
+		ContainerName:   o.Container,
+		EnvName:         o.Name,
+		EnvValue:        o.Value,
+		KeyValueSources: o.sources(),
+	}
+}
+
+// TolerationOptions are the options to configure the tolerations of a deployment
+type TolerationOptions struct {
+	Component  string
+	Namespace  string
+	Deployment string
+	Key        string
+	Operator   string
+	Value      string
+	Effect     string
+}
+
+func (o TolerationOptions) flags() configure.TolerationsFlags {
+	return configure.TolerationsFlags{
+		Component:  o.Component,
+		Namespace:  o.Namespace,
+		DeployName: o.Deployment,
+		Key:        o.Key,
+		Operator:   o.Operator,
+		Value:      o.Value,
+		Effect:     o.Effect,
+	}
+}
+
+// ManifestsOptions are the options to configure the custom manifests
+type ManifestsOptions struct {
+	Component string
+	Namespace string
+	// OperatorNamespace is the namespace of the Knative Operator
+	OperatorNamespace string
+	// File is the path to the local file with the custom manifests
+	File string
+	// Overwrite replaces the existing custom manifests
+	Overwrite bool
+	// Accessible means that the custom manifests are already accessible to the Knative Operator
+	Accessible bool
+}
+
+func (o ManifestsOptions) flags() configure.ManifestsFlags {
+	return configure.ManifestsFlags{
+		Component:         o.Component,
+		Namespace:         o.Namespace,
+		OperatorNamespace: o.OperatorNamespace,
+		File:              o.File,
+		Overwrite:         o.Overwrite,
+		Accessible:        o.Accessible,
+	}
+}
+
+// ConfigMapOptions are the options to configure the data of a ConfigMap
+type ConfigMapOptions struct {
+	Component string
+	Namespace string
+	// Name is the name of the ConfigMap, with or without the prefix config-
+	Name  string
+	Key   string
+	Value string
+	// AllowUnknown reports the keys and the values not matching the schema as warnings instead of errors
+	AllowUnknown bool
+	KeyValueSources
+}
+
+func (o ConfigMapOptions) flags() common.CMsFlags {
+	return common.CMsFlags{
+		Component:       o.Component,
+		Namespace:       o.Namespace,
+		CMName:          o.Name,
+		Key:             o.Key,
+		Value:           o.Value,
+		AllowUnknown:    o.AllowUnknown,
+		KeyValueSources: o.sources(),
+	}
+}
+
+// KeyValueOptions are the options to configure the labels, annotations, node selectors or selectors
+type KeyValueOptions struct {
+	Component string
+	Namespace string
+	// Deployment or Service is the Knative deployment or service to configure
+	Deployment string
+	Service    string
+	Key        string
+	Value      string
+	KeyValueSources
+}
+
+func (o KeyValueOptions) flags() common.KeyValueFlags {
+	return common.KeyValueFlags{
+		Component:       o.Component,
+		Namespace:       o.Namespace,
+		DeployName:      o.Deployment,
+		ServiceName:     o.Service,
+		Key:             o.Key,
+		Value:           o.Value,
+		KeyValueSources: o.sources(),
+	}
+}
+
+// IngressOptions are the options to enable an ingress for Knative Serving
+type IngressOptions struct {
+	Namespace string
+	Istio     bool
+	Kourier   bool
+	Contour   bool
+}
+
+func (o IngressOptions) flags() *enable.IngressFlags {
+	return &enable.IngressFlags{
+		Namespace: o.Namespace,
+		Istio:     o.Istio,
+		Kourier:   o.Kourier,
+		Contour:   o.Contour,
+	}
+}
+
+// EventingSourceOptions are the options to enable the eventing sources for Knative Eventing
+type EventingSourceOptions struct {
+	Namespace string
+	Ceph      bool
+	Github    bool
+	Gitlab    bool
+	Kafka     bool
+	Rabbitmq  bool
+	Redis     bool
+}
+
+func (o EventingSourceOptions) flags() *enable.EventingSourceFlags {
+	return &enable.EventingSourceFlags{
+		Namespace: o.Namespace,
+		Ceph:      o.Ceph,
+		Github:    o.Github,
+		Gitlab:    o.Gitlab,
+		Kafka:     o.Kafka,
+		Rabbitmq:  o.Rabbitmq,
+		Redis:     o.Redis,
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+const (
+	operatorFile        = "operator.yaml"
+	servingFilePrefix   = "knativeserving-"
+	eventingFilePrefix  = "knativeeventing-"
+	configMapFilePrefix = "config-manifest-"
+	yamlFileSuffix      = ".yaml"
+
+	// deploymentRevisionAnnotation is set by the deployment controller, and not restored
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+)
+
+// operatorBackup saves the installation of the Knative Operator
+type operatorBackup struct {
+	Namespace string `json:"namespace"`
+	Version   string `json:"version,omitempty"`
+	// CustomManifests is only set by the earlier backups, which do not save the deployment of the operator
+	CustomManifests bool `json:"customManifests,omitempty"`
+	// Deployment saves the overrides of the operator deployment, e.g. the volume of the custom manifests,
+	// the resources or the environment variables
+	Deployment *operatorDeployment `json:"deployment,omitempty"`
+}
+
+// operatorDeployment keeps the fields of the operator deployment, which can be changed after the installation
+type operatorDeployment struct {
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+	Replicas    *int32                 `json:"replicas,omitempty"`
+	Template    corev1.PodTemplateSpec `json:"template"`
+}
+
+// backupData contains all the resources saved in the backup directory
+type backupData struct {
+	Operator   operatorBackup
+	Servings   []v1beta1.KnativeServing
+	Eventings  []v1beta1.KnativeEventing
+	ConfigMaps []corev1.ConfigMap
+}
+
+type backupFlags struct {
+	Output string
+}
+
+// NewBackupCommand represents the backup commands to save the configuration of the Knative Operator
+func NewBackupCommand(p *pkg.OperatorParams) *cobra.Command {
+	var backupCMDFlags backupFlags
+	var backupCmd = &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the configuration of the Knative Operator, Serving and Eventing",
+		Example: `
+  # Back up the configuration of the Knative Operator, Serving and Eventing into the directory backup
+  kn operator backup -o backup/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateBackupFlags(backupCMDFlags); err != nil {
+				return err
+			}
+
+			data, err := collectBackup(p)
+			if err != nil {
+				return err
+			}
+
+			if err = writeBackup(backupCMDFlags.Output, data); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The configuration of the Knative Operator was backed up in the directory '%s'.\n",
+				backupCMDFlags.Output)
+			return nil
+		},
+	}
+
+	backupCmd.Flags().StringVarP(&backupCMDFlags.Output, "output", "o", "", "The directory to save the backup")
+
+	return backupCmd
+}
+
+func validateBackupFlags(backupCMDFlags backupFlags) error {
+	if backupCMDFlags.Output == "" {
+		return fmt.Errorf("You need to specify the directory to save the backup.")
+	}
+	return nil
+}
+
+func collectBackup(p *pkg.OperatorParams) (*backupData, error) {
+	ctx := p.Context()
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	operatorClient, err := p.NewOperatorClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	deploy := common.Deployment{
+		Client: kubeClient,
+	}
+	exists, operatorNamespace, version, err := deploy.CheckIfOperatorInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("The Knative Operator is not installed.")
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments(operatorNamespace).Get(ctx, common.KnativeOperatorName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &backupData{
+		Operator: operatorBackup{
+			Namespace:  operatorNamespace,
+			Version:    version,
+			Deployment: backupOperatorDeployment(deployment),
+		},
+	}
+
+	ksList, err := operatorClient.OperatorV1beta1().KnativeServings("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ks := range ksList.Items {
+		data.Servings = append(data.Servings, v1beta1.KnativeServing{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KnativeServing",
+				APIVersion: "operator.knative.dev/v1beta1",
+			},
+			ObjectMeta: cleanObjectMeta(ks.ObjectMeta),
+			Spec:       ks.Spec,
+		})
+	}
+
+	keList, err := operatorClient.OperatorV1beta1().KnativeEventings("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ke := range keList.Items {
+		data.Eventings = append(data.Eventings, v1beta1.KnativeEventing{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KnativeEventing",
+				APIVersion: "operator.knative.dev/v1beta1",
+			},
+			ObjectMeta: cleanObjectMeta(ke.ObjectMeta),
+			Spec:       ke.Spec,
+		})
+	}
+
+	// The custom manifests are saved in the ConfigMap under the namespace of each Knative component
+	kubeResource := common.KubeResource{
+		KubeClient: kubeClient,
+	}
+	for _, namespace := range componentNamespaces(data) {
+		cm, err := kubeResource.GetConfigMap(ctx, common.ConfigMapName, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if cm == nil {
+			continue
+		}
+		data.ConfigMaps = append(data.ConfigMaps, corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+			ObjectMeta: cleanObjectMeta(cm.ObjectMeta),
+			Data:       cm.Data,
+		})
+	}
+
+	return data, nil
+}
+
+// componentNamespaces returns the sorted namespaces of all the Knative components in the backup
+func componentNamespaces(data *backupData) []string {
+	set := map[string]struct{}{}
+	for _, ks := range data.Servings {
+		set[ks.Namespace] = struct{}{}
+	}
+	for _, ke := range data.Eventings {
+		set[ke.Namespace] = struct{}{}
+	}
+
+	namespaces := make([]string, 0, len(set))
+	for namespace := range set {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// backupOperatorDeployment keeps the fields of the operator deployment, which can be overridden after the installation
+func backupOperatorDeployment(deployment *appsv1.Deployment) *operatorDeployment {
+	meta := cleanObjectMeta(deployment.ObjectMeta)
+	delete(meta.Annotations, deploymentRevisionAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	return &operatorDeployment{
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+		Replicas:    deployment.Spec.Replicas,
+		Template:    *deployment.Spec.Template.DeepCopy(),
+	}
+}
+
+// cleanObjectMeta keeps only the metadata needed to recreate the resource
+func cleanObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	var annotations map[string]string
+	for key, value := range meta.Annotations {
+		if key == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = value
+	}
+
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: annotations,
+	}
+}
+
+// writeBackup saves the resources as yaml files in the directory
+func writeBackup(dir string, data *backupData) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	if err := writeYamlFile(filepath.Join(dir, operatorFile), data.Operator); err != nil {
+		return err
+	}
+	for _, ks := range data.Servings {
+		if err := writeYamlFile(filepath.Join(dir, servingFilePrefix+ks.Namespace+yamlFileSuffix), ks); err != nil {
+			return err
+		}
+	}
+	for _, ke := range data.Eventings {
+		if err := writeYamlFile(filepath.Join(dir, eventingFilePrefix+ke.Namespace+yamlFileSuffix), ke); err != nil {
+			return err
+		}
+	}
+	for _, cm := range data.ConfigMaps {
+		if err := writeYamlFile(filepath.Join(dir, configMapFilePrefix+cm.Namespace+yamlFileSuffix), cm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBackup loads the resources from the yaml files in the directory
+func readBackup(dir string) (*backupData, error) {
+	data := &backupData{}
+	if err := readYamlFile(filepath.Join(dir, operatorFile), &data.Operator); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, yamlFileSuffix) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if strings.HasPrefix(name, servingFilePrefix) {
+			var ks v1beta1.KnativeServing
+			if err := readYamlFile(path, &ks); err != nil {
+				return nil, err
+			}
+			data.Servings = append(data.Servings, ks)
+		} else if strings.HasPrefix(name, eventingFilePrefix) {
+			var ke v1beta1.KnativeEventing
+			if err := readYamlFile(path, &ke); err != nil {
+				return nil, err
+			}
+			data.Eventings = append(data.Eventings, ke)
+		} else if strings.HasPrefix(name, configMapFilePrefix) {
+			var cm corev1.ConfigMap
+			if err := readYamlFile(path, &cm); err != nil {
+				return nil, err
+			}
+			data.ConfigMaps = append(data.ConfigMaps, cm)
+		}
+	}
+	return data, nil
+}
+
+func writeYamlFile(path string, obj interface{}) error {
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	// The backup may contain sensitive configuration, so it is only readable by the owner
+	return os.WriteFile(path, content, 0600)
+}
+
+func readYamlFile(path string, obj interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(content, obj); err != nil {
+		return fmt.Errorf("failed to read the file %s: %w", path, err)
+	}
+	return nil
+}
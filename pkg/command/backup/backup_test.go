@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestValidateBackupFlags(t *testing.T) {
+	testingUtil.AssertEqual(t, validateBackupFlags(backupFlags{Output: "backup"}), nil)
+	testingUtil.AssertEqual(t, validateBackupFlags(backupFlags{}).Error(),
+		fmt.Errorf("You need to specify the directory to save the backup.").Error())
+}
+
+func TestCleanObjectMeta(t *testing.T) {
+	result := cleanObjectMeta(metav1.ObjectMeta{
+		Name:            "knative-serving",
+		Namespace:       "test-serving",
+		ResourceVersion: "100",
+		UID:             "test-uid",
+		Labels:          map[string]string{"test-label": "test-value"},
+		Annotations: map[string]string{
+			corev1.LastAppliedConfigAnnotation: "{}",
+			"test-annotation":                  "test-value",
+		},
+	})
+	testingUtil.AssertDeepEqual(t, result, metav1.ObjectMeta{
+		Name:        "knative-serving",
+		Namespace:   "test-serving",
+		Labels:      map[string]string{"test-label": "test-value"},
+		Annotations: map[string]string{"test-annotation": "test-value"},
+	})
+
+	result = cleanObjectMeta(metav1.ObjectMeta{
+		Name:        "knative-eventing",
+		Namespace:   "test-eventing",
+		Annotations: map[string]string{corev1.LastAppliedConfigAnnotation: "{}"},
+	})
+	testingUtil.AssertDeepEqual(t, result, metav1.ObjectMeta{
+		Name:      "knative-eventing",
+		Namespace: "test-eventing",
+	})
+}
+
+func TestBackupOperatorDeployment(t *testing.T) {
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "knative-operator",
+			Namespace:       "default",
+			ResourceVersion: "1234",
+			Labels:          map[string]string{"app.kubernetes.io/version": "1.8.0"},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision": "3",
+				corev1.LastAppliedConfigAnnotation:  "{}",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "knative-operator",
+						Env:  []corev1.EnvVar{{Name: "KUBERNETES_MIN_VERSION", Value: "v1.23.0"}},
+					}},
+				},
+			},
+		},
+	}
+
+	testingUtil.AssertDeepEqual(t, backupOperatorDeployment(deployment), &operatorDeployment{
+		Labels:   map[string]string{"app.kubernetes.io/version": "1.8.0"},
+		Replicas: &replicas,
+		Template: deployment.Spec.Template,
+	})
+}
+
+func TestWriteAndReadBackup(t *testing.T) {
+	dir := t.TempDir()
+	data := &backupData{
+		Operator: operatorBackup{
+			Namespace: "default",
+			Version:   "1.5.0",
+			Deployment: &operatorDeployment{
+				Labels: map[string]string{"app.kubernetes.io/version": "1.5.0"},
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{Name: common.CustomVolumeName}},
+					},
+				},
+			},
+		},
+		Servings: []v1beta1.KnativeServing{{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KnativeServing",
+				APIVersion: "operator.knative.dev/v1beta1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "knative-serving",
+				Namespace: "test-serving",
+			},
+			Spec: v1beta1.KnativeServingSpec{
+				CommonSpec: base.CommonSpec{
+					Version: "1.5",
+					Config: base.ConfigMapData{
+						"network": map[string]string{"ingress-class": "kourier.ingress.networking.knative.dev"},
+					},
+				},
+			},
+		}},
+		Eventings: []v1beta1.KnativeEventing{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "knative-eventing",
+				Namespace: "test-eventing",
+			},
+		}},
+		ConfigMaps: []corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "config-manifest",
+				Namespace: "test-serving",
+			},
+			Data: map[string]string{"custom-manifests.yaml": "|\ntest"},
+		}},
+	}
+
+	err := writeBackup(dir, data)
+	testingUtil.AssertEqual(t, err, nil)
+
+	result, err := readBackup(dir)
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertDeepEqual(t, result.Operator, data.Operator)
+	testingUtil.AssertEqual(t, len(result.Servings), 1)
+	testingUtil.AssertEqual(t, result.Servings[0].Namespace, "test-serving")
+	testingUtil.AssertDeepEqual(t, result.Servings[0].Spec, data.Servings[0].Spec)
+	testingUtil.AssertEqual(t, len(result.Eventings), 1)
+	testingUtil.AssertEqual(t, result.Eventings[0].Name, "knative-eventing")
+	testingUtil.AssertEqual(t, len(result.ConfigMaps), 1)
+	testingUtil.AssertDeepEqual(t, result.ConfigMaps[0].Data, data.ConfigMaps[0].Data)
+}
+
+func TestReadBackupWithoutOperator(t *testing.T) {
+	_, err := readBackup(t.TempDir())
+	testingUtil.AssertEqual(t, err == nil, false)
+}
+
+func TestComponentNamespaces(t *testing.T) {
+	data := &backupData{
+		Servings: []v1beta1.KnativeServing{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving"},
+		}},
+		Eventings: []v1beta1.KnativeEventing{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-eventing"},
+		}, {
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving"},
+		}},
+	}
+	testingUtil.AssertDeepEqual(t, componentNamespaces(data), []string{"knative-eventing", "knative-serving"})
+}
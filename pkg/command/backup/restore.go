@@ -0,0 +1,282 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+	"k8s.io/client-go/util/retry"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/install"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+// installOperator and waitForOperatorReady install the Knative Operator and wait for it, they are replaced in the tests
+var (
+	installOperator      = install.InstallOperator
+	waitForOperatorReady = install.WaitForOperatorReady
+)
+
+type restoreFlags struct {
+	File string
+}
+
+// NewRestoreCommand represents the restore commands to replay the backup of the Knative Operator
+func NewRestoreCommand(p *pkg.OperatorParams) *cobra.Command {
+	var restoreCMDFlags restoreFlags
+	var restoreCmd = &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the configuration of the Knative Operator, Serving and Eventing from a backup",
+		Example: `
+  # Restore the configuration of the Knative Operator, Serving and Eventing from the directory backup
+  kn operator restore -f backup/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateRestoreFlags(restoreCMDFlags); err != nil {
+				return err
+			}
+
+			data, err := readBackup(restoreCMDFlags.File)
+			if err != nil {
+				return err
+			}
+
+			if err = restoreBackup(data, p); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The configuration of the Knative Operator was restored from the directory '%s'.\n",
+				restoreCMDFlags.File)
+			return nil
+		},
+	}
+
+	restoreCmd.Flags().StringVarP(&restoreCMDFlags.File, "file", "f", "", "The directory of the backup")
+
+	return restoreCmd
+}
+
+func validateRestoreFlags(restoreCMDFlags restoreFlags) error {
+	if restoreCMDFlags.File == "" {
+		return fmt.Errorf("You need to specify the directory of the backup.")
+	}
+	return nil
+}
+
+func restoreBackup(data *backupData, p *pkg.OperatorParams) error {
+	ctx := p.Context()
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	operatorNamespace, operatorVersion, err := ensureOperatorInstalled(data.Operator, kubeClient, p)
+	if err != nil {
+		return err
+	}
+
+	kubeResource := common.KubeResource{
+		KubeClient: kubeClient,
+	}
+	for i := range data.ConfigMaps {
+		if err = createNamespaceIfNecessary(ctx, data.ConfigMaps[i].Namespace, kubeClient); err != nil {
+			return err
+		}
+		if err = kubeResource.ApplyConfigMap(ctx, &data.ConfigMaps[i]); err != nil {
+			return err
+		}
+	}
+
+	if err = restoreOperatorDeployment(ctx, data.Operator, operatorNamespace, operatorVersion, kubeClient, p); err != nil {
+		return err
+	}
+
+	// The Knative custom resources are only accepted, once the CRDs, the operator and its conversion webhook are ready
+	if err = waitForOperatorReady(operatorNamespace, common.DefaultTimeout, p); err != nil {
+		return err
+	}
+
+	ksCR, err := common.GetKnativeOperatorCR(p)
+	if err != nil {
+		return err
+	}
+	for i := range data.Servings {
+		ks := &data.Servings[i]
+		if err = createNamespaceIfNecessary(ctx, ks.Namespace, kubeClient); err != nil {
+			return err
+		}
+		if err = restoreKnativeServing(ksCR, ks, p); err != nil {
+			return err
+		}
+	}
+	for i := range data.Eventings {
+		ke := &data.Eventings[i]
+		if err = createNamespaceIfNecessary(ctx, ke.Namespace, kubeClient); err != nil {
+			return err
+		}
+		if err = restoreKnativeEventing(ksCR, ke, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureOperatorInstalled installs the Knative Operator saved in the backup, if no operator is installed.
+// It returns the namespace and the version of the operator in the cluster.
+func ensureOperatorInstalled(operator operatorBackup, kubeClient kubernetes.Interface, p *pkg.OperatorParams) (string, string, error) {
+	ctx := p.Context()
+	deploy := common.Deployment{
+		Client: kubeClient,
+	}
+	exists, namespace, version, err := deploy.CheckIfOperatorInstalled(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	if exists {
+		return namespace, version, nil
+	}
+
+	version = operator.Version
+	if version == "" {
+		version = common.Latest
+	}
+	namespace = operator.Namespace
+	if namespace == "" {
+		namespace = common.DefaultNamespace
+	}
+	if err = installOperator(namespace, version, p); err != nil {
+		return "", "", err
+	}
+	return namespace, version, nil
+}
+
+// restoreOperatorDeployment applies the saved overrides to the deployment of the operator. The pod template carries
+// the image of the release, so it is only restored on the same version of the operator. Otherwise, only the volume
+// of the custom manifests is added.
+func restoreOperatorDeployment(ctx context.Context, operator operatorBackup, namespace, version string,
+	kubeClient kubernetes.Interface, p *pkg.OperatorParams) error {
+	saved := operator.Deployment
+	if saved == nil || !sameVersion(operator.Version, version) {
+		if saved != nil {
+			p.Warn("The Knative Operator %s in the cluster differs from the version %s in the backup, its deployment is not restored.",
+				version, operator.Version)
+		}
+		if !operator.CustomManifests && (saved == nil || !hasCustomManifestsVolume(saved.Template)) {
+			return nil
+		}
+		kubeResource := common.KubeResource{
+			KubeClient: kubeClient,
+		}
+		return kubeResource.UpdateOperatorDeployment(ctx, common.KnativeOperatorName, namespace)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(ctx, common.KnativeOperatorName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		deployment.Labels = mergeMaps(deployment.Labels, saved.Labels)
+		deployment.Annotations = mergeMaps(deployment.Annotations, saved.Annotations)
+		if saved.Replicas != nil {
+			deployment.Spec.Replicas = saved.Replicas
+		}
+		deployment.Spec.Template = *saved.Template.DeepCopy()
+		_, err = kubeClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// sameVersion checks if both versions of the operator refer to the same release
+func sameVersion(saved, current string) bool {
+	savedVersion := common.SemanticVersion(saved)
+	return savedVersion != "" && savedVersion == common.SemanticVersion(current)
+}
+
+func hasCustomManifestsVolume(template corev1.PodTemplateSpec) bool {
+	for _, volume := range template.Spec.Volumes {
+		if volume.Name == common.CustomVolumeName {
+			return true
+		}
+	}
+	return false
+}
+
+func createNamespaceIfNecessary(ctx context.Context, namespace string, kubeClient kubernetes.Interface) error {
+	ns := common.Namespace{
+		Client:    kubeClient,
+		Component: namespace,
+	}
+	return ns.CreateNamespace(ctx, namespace)
+}
+
+func restoreKnativeServing(ksCR *common.KnativeOperatorCR, ks *v1beta1.KnativeServing, p *pkg.OperatorParams) error {
+	return restoreKnativeCR(ksCR, common.ServingComponent, &ks.ObjectMeta, p, func(spec *common.KnativeSpec) {
+		*spec.Serving = *ks.Spec.DeepCopy()
+	})
+}
+
+func restoreKnativeEventing(ksCR *common.KnativeOperatorCR, ke *v1beta1.KnativeEventing, p *pkg.OperatorParams) error {
+	return restoreKnativeCR(ksCR, common.EventingComponent, &ke.ObjectMeta, p, func(spec *common.KnativeSpec) {
+		*spec.Eventing = *ke.Spec.DeepCopy()
+	})
+}
+
+// restoreKnativeCR applies the saved labels, annotations and spec to the Knative custom resource, which is created
+// if it does not exist. The spec before the change is recorded in the history afterwards.
+func restoreKnativeCR(ksCR *common.KnativeOperatorCR, component string, saved *metav1.ObjectMeta, p *pkg.OperatorParams,
+	restoreSpec func(spec *common.KnativeSpec)) error {
+	var previous interface{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		previous, err = ksCR.MutateSpecByName(p.Context(), component, saved.Name, saved.Namespace, true,
+			func(spec *common.KnativeSpec) error {
+				spec.ObjectMeta.Labels = mergeMaps(spec.ObjectMeta.Labels, saved.Labels)
+				spec.ObjectMeta.Annotations = mergeMaps(spec.ObjectMeta.Annotations, saved.Annotations)
+				restoreSpec(spec)
+				return nil
+			})
+		return err
+	})
+	if err != nil || previous == nil {
+		return err
+	}
+	if err = common.RecordSpec(component, saved.Namespace, previous, p); err != nil {
+		p.Warn("The restore of Knative %s in the namespace '%s' is not recorded in the history: %v", component, saved.Namespace, err)
+	}
+	return nil
+}
+
+// mergeMaps returns the entries of existing, overwritten by the entries of saved
+func mergeMaps(existing, saved map[string]string) map[string]string {
+	if len(saved) == 0 {
+		return existing
+	}
+	result := make(map[string]string, len(existing)+len(saved))
+	for key, value := range existing {
+		result[key] = value
+	}
+	for key, value := range saved {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestValidateRestoreFlags(t *testing.T) {
+	testingUtil.AssertEqual(t, validateRestoreFlags(restoreFlags{File: "backup"}), nil)
+	testingUtil.AssertEqual(t, validateRestoreFlags(restoreFlags{}).Error(),
+		fmt.Errorf("You need to specify the directory of the backup.").Error())
+}
+
+func TestMergeMaps(t *testing.T) {
+	testingUtil.AssertDeepEqual(t, mergeMaps(map[string]string{"key": "value"}, nil), map[string]string{"key": "value"})
+	testingUtil.AssertDeepEqual(t, mergeMaps(nil, map[string]string{"key": "value"}), map[string]string{"key": "value"})
+	testingUtil.AssertDeepEqual(t, mergeMaps(map[string]string{"key": "value", "key1": "value1"},
+		map[string]string{"key": "new-value"}), map[string]string{"key": "new-value", "key1": "value1"})
+}
+
+func TestSameVersion(t *testing.T) {
+	testingUtil.AssertEqual(t, sameVersion("1.8.0", "v1.8.0"), true)
+	testingUtil.AssertEqual(t, sameVersion("1.8.0", "1.8.1"), false)
+	testingUtil.AssertEqual(t, sameVersion("", common.Latest), false)
+}
+
+func TestHasCustomManifestsVolume(t *testing.T) {
+	testingUtil.AssertEqual(t, hasCustomManifestsVolume(corev1.PodTemplateSpec{}), false)
+	testingUtil.AssertEqual(t, hasCustomManifestsVolume(corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: common.CustomVolumeName}}},
+	}), true)
+}
+
+// fakeOperatorInstallation replaces the installation of the Knative Operator with the creation of its deployment
+// by the fake client, and skips the wait for the operator. The installations and the waits are recorded.
+func fakeOperatorInstallation(kubeClient kubernetes.Interface, installed, waited *[]string) func() {
+	originalInstall, originalWait := installOperator, waitForOperatorReady
+	installOperator = func(namespace, version string, p *pkg.OperatorParams) error {
+		*installed = append(*installed, namespace+"/"+version)
+		ctx := context.Background()
+		if _, err := kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		}, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		_, err := kubeClient.AppsV1().Deployments(namespace).Create(ctx, clusterDeployment(namespace, version), metav1.CreateOptions{})
+		return err
+	}
+	waitForOperatorReady = func(namespace string, timeout time.Duration, p *pkg.OperatorParams) error {
+		*waited = append(*waited, namespace)
+		return nil
+	}
+	return func() {
+		installOperator, waitForOperatorReady = originalInstall, originalWait
+	}
+}
+
+func operatorObjects(namespace, version string) []runtime.Object {
+	return []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}},
+		clusterDeployment(namespace, version),
+	}
+}
+
+func clusterDeployment(namespace, version string) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.KnativeOperatorName,
+			Namespace: namespace,
+			Labels:    map[string]string{"app.kubernetes.io/version": version},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  common.KnativeOperatorName,
+				Image: "gcr.io/knative-releases/knative.dev/operator/cmd/operator:v" + version,
+			}}}},
+		},
+	}
+}
+
+// savedDeployment is the deployment of the operator 1.18.0 saved in the backup, with two replicas, an additional
+// environment variable and the volume of the custom manifests
+func savedDeployment() *operatorDeployment {
+	replicas := int32(2)
+	template := clusterDeployment("knative-operator", "1.18.0").Spec.Template
+	template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "KUBERNETES_MIN_VERSION", Value: "1.25.0"}}
+	template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+		Name: common.CustomVolumeName, ReadOnly: true, MountPath: common.MountPath,
+	}}
+	template.Spec.Volumes = []corev1.Volume{{Name: common.CustomVolumeName}}
+	return &operatorDeployment{
+		Labels:   map[string]string{"team": "knative"},
+		Replicas: &replicas,
+		Template: template,
+	}
+}
+
+func TestEnsureOperatorInstalled(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		objects           []runtime.Object
+		operator          operatorBackup
+		expectedNamespace string
+		expectedVersion   string
+		expectedInstalled []string
+	}{{
+		name:              "The operator in the cluster is kept",
+		objects:           operatorObjects("operator-ns", "1.18.0"),
+		operator:          operatorBackup{Namespace: "knative-operator", Version: "1.17.0"},
+		expectedNamespace: "operator-ns",
+		expectedVersion:   "1.18.0",
+	}, {
+		name:              "The operator in the backup is installed",
+		operator:          operatorBackup{Namespace: "test-ns", Version: "1.17.0"},
+		expectedNamespace: "test-ns",
+		expectedVersion:   "1.17.0",
+		expectedInstalled: []string{"test-ns/1.17.0"},
+	}, {
+		name:              "The latest operator is installed in the default namespace",
+		expectedNamespace: common.DefaultNamespace,
+		expectedVersion:   common.Latest,
+		expectedInstalled: []string{common.DefaultNamespace + "/" + common.Latest},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset(tt.objects...)
+			var installed, waited []string
+			defer fakeOperatorInstallation(kubeClient, &installed, &waited)()
+
+			p := testingUtil.NewFakeOperatorParams(kubeClient, testingUtil.NewFakeOperatorClient())
+			namespace, version, err := ensureOperatorInstalled(tt.operator, kubeClient, p)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, namespace, tt.expectedNamespace)
+			testingUtil.AssertEqual(t, version, tt.expectedVersion)
+			testingUtil.AssertDeepEqual(t, installed, tt.expectedInstalled)
+		})
+	}
+}
+
+func TestRestoreOperatorDeployment(t *testing.T) {
+	saved := savedDeployment()
+	clusterTemplate := clusterDeployment("knative-operator", "1.18.0").Spec.Template
+	for _, tt := range []struct {
+		name             string
+		operator         operatorBackup
+		expectedTemplate corev1.PodTemplateSpec
+		expectedReplicas int32
+		expectedLabels   map[string]string
+		expectedWarning  string
+	}{{
+		name:             "The deployment is restored on the same version",
+		operator:         operatorBackup{Version: "v1.18.0", Deployment: saved},
+		expectedTemplate: saved.Template,
+		expectedReplicas: 2,
+		expectedLabels:   map[string]string{"app.kubernetes.io/version": "1.18.0", "team": "knative"},
+	}, {
+		name: "Only the custom manifests are restored on a different version",
+		operator: operatorBackup{Version: "1.17.0", Deployment: &operatorDeployment{
+			Template: saved.Template,
+		}},
+		expectedTemplate: withCustomManifests(clusterTemplate),
+		expectedReplicas: 1,
+		expectedLabels:   map[string]string{"app.kubernetes.io/version": "1.18.0"},
+		expectedWarning: "Warning: The Knative Operator 1.18.0 in the cluster differs from the version 1.17.0 in the backup, " +
+			"its deployment is not restored.\n",
+	}, {
+		name: "Nothing is restored on a different version without custom manifests",
+		operator: operatorBackup{Version: "1.17.0", Deployment: &operatorDeployment{
+			Template: clusterDeployment("knative-operator", "1.17.0").Spec.Template,
+		}},
+		expectedTemplate: clusterTemplate,
+		expectedReplicas: 1,
+		expectedLabels:   map[string]string{"app.kubernetes.io/version": "1.18.0"},
+		expectedWarning: "Warning: The Knative Operator 1.18.0 in the cluster differs from the version 1.17.0 in the backup, " +
+			"its deployment is not restored.\n",
+	}, {
+		name:             "The custom manifests of an earlier backup are restored",
+		operator:         operatorBackup{CustomManifests: true},
+		expectedTemplate: withCustomManifests(clusterTemplate),
+		expectedReplicas: 1,
+		expectedLabels:   map[string]string{"app.kubernetes.io/version": "1.18.0"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset(operatorObjects("knative-operator", "1.18.0")...)
+			p := testingUtil.NewFakeOperatorParams(kubeClient, testingUtil.NewFakeOperatorClient())
+			warnings := &bytes.Buffer{}
+			p.ErrOut = warnings
+
+			ctx := context.Background()
+			err := restoreOperatorDeployment(ctx, tt.operator, "knative-operator", "1.18.0", kubeClient, p)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, warnings.String(), tt.expectedWarning)
+
+			deployment, err := kubeClient.AppsV1().Deployments("knative-operator").Get(ctx, common.KnativeOperatorName, metav1.GetOptions{})
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, deployment.Spec.Template, tt.expectedTemplate)
+			testingUtil.AssertEqual(t, *deployment.Spec.Replicas, tt.expectedReplicas)
+			testingUtil.AssertDeepEqual(t, deployment.Labels, tt.expectedLabels)
+		})
+	}
+}
+
+func withCustomManifests(template corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	result := *template.DeepCopy()
+	result.Spec.Volumes = []corev1.Volume{{
+		Name: common.CustomVolumeName,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: common.ConfigMapName},
+		}},
+	}}
+	result.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{
+		Name: common.CustomVolumeName, ReadOnly: true, MountPath: common.MountPath,
+	}}
+	return result
+}
+
+func TestRestoreBackup(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		objects           []runtime.Object
+		backupVersion     string
+		expectedInstalled []string
+		expectedImage     string
+		expectedReplicas  int32
+		expectedWarning   string
+	}{{
+		name:              "The operator of the backup is installed and its deployment is restored",
+		backupVersion:     "1.18.0",
+		expectedInstalled: []string{"knative-operator/1.18.0"},
+		expectedImage:     "gcr.io/knative-releases/knative.dev/operator/cmd/operator:v1.18.0",
+		expectedReplicas:  2,
+	}, {
+		name:             "The deployment of the operator of the same version is restored",
+		objects:          operatorObjects("knative-operator", "1.18.0"),
+		backupVersion:    "1.18.0",
+		expectedImage:    "gcr.io/knative-releases/knative.dev/operator/cmd/operator:v1.18.0",
+		expectedReplicas: 2,
+	}, {
+		name:             "The deployment of the operator of a different version is kept",
+		objects:          operatorObjects("knative-operator", "1.19.0"),
+		backupVersion:    "1.18.0",
+		expectedImage:    "gcr.io/knative-releases/knative.dev/operator/cmd/operator:v1.19.0",
+		expectedReplicas: 1,
+		expectedWarning: "Warning: The Knative Operator 1.19.0 in the cluster differs from the version 1.18.0 in the backup, " +
+			"its deployment is not restored.\n",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewClientset(tt.objects...)
+			operatorClient := testingUtil.NewFakeOperatorClient(&v1beta1.KnativeServing{
+				ObjectMeta: metav1.ObjectMeta{Name: "knative-serving", Namespace: "knative-serving"},
+			})
+			var installed, waited []string
+			defer fakeOperatorInstallation(kubeClient, &installed, &waited)()
+			p := testingUtil.NewFakeOperatorParams(kubeClient, operatorClient)
+			warnings := &bytes.Buffer{}
+			p.ErrOut = warnings
+
+			data := &backupData{
+				Operator: operatorBackup{Namespace: "knative-operator", Version: tt.backupVersion, Deployment: savedDeployment()},
+				Servings: []v1beta1.KnativeServing{{
+					ObjectMeta: metav1.ObjectMeta{Name: "knative-serving", Namespace: "knative-serving",
+						Labels: map[string]string{"team": "knative"}},
+					Spec: v1beta1.KnativeServingSpec{CommonSpec: base.CommonSpec{
+						Config: base.ConfigMapData{"network": {"ingress-class": "kourier.ingress.networking.knative.dev"}},
+					}},
+				}},
+				ConfigMaps: []corev1.ConfigMap{{
+					ObjectMeta: metav1.ObjectMeta{Name: common.ConfigMapName, Namespace: "knative-operator"},
+					Data:       map[string]string{"manifest.yaml": "test"},
+				}},
+			}
+			err := restoreBackup(data, p)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, installed, tt.expectedInstalled)
+			testingUtil.AssertDeepEqual(t, waited, []string{"knative-operator"})
+			testingUtil.AssertEqual(t, warnings.String(), tt.expectedWarning)
+
+			ctx := context.Background()
+			deployment, err := kubeClient.AppsV1().Deployments("knative-operator").Get(ctx, common.KnativeOperatorName, metav1.GetOptions{})
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, deployment.Spec.Template.Spec.Containers[0].Image, tt.expectedImage)
+			testingUtil.AssertEqual(t, *deployment.Spec.Replicas, tt.expectedReplicas)
+			testingUtil.AssertEqual(t, hasCustomManifestsVolume(deployment.Spec.Template), true)
+
+			cm, err := kubeClient.CoreV1().ConfigMaps("knative-operator").Get(ctx, common.ConfigMapName, metav1.GetOptions{})
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, cm.Data, map[string]string{"manifest.yaml": "test"})
+
+			ks, err := operatorClient.OperatorV1beta1().KnativeServings("knative-serving").Get(ctx, "knative-serving", metav1.GetOptions{})
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, ks.Labels["team"], "knative")
+			testingUtil.AssertDeepEqual(t, ks.Spec.Config, data.Servings[0].Spec.Config)
+			_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "knative-serving", metav1.GetOptions{})
+			testingUtil.AssertEqual(t, err, nil)
+		})
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+// FieldManager is the name of the field manager, under which the plugin applies the resources with
+// the server-side apply
+const FieldManager = "kn-operator"
+
+// sameSpec checks if two specs are serialized into the same content
+func sameSpec(expected, actual interface{}) bool {
+	expectedData, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	actualData, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedData) == string(actualData)
+}
+
+// applyConflictError explains the field ownership conflicts of a failed server-side apply. Any other error
+// is returned as it is.
+func applyConflictError(kind, name, namespace string, err error) error {
+	if !apierrs.IsConflict(err) {
+		return err
+	}
+	var status apierrs.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return err
+	}
+
+	conflicts := []string{}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			conflicts = append(conflicts, fmt.Sprintf("  %s: %s", cause.Field, cause.Message))
+		}
+	}
+	if len(conflicts) == 0 {
+		return err
+	}
+	return fmt.Errorf("Failed to apply %s '%s' in the namespace '%s', because the following fields are "+
+		"managed by other field managers:\n%s\nUse --force-conflicts to take over the ownership of these fields.",
+		kind, name, namespace, strings.Join(conflicts, LineWrapper))
+}
+
+// knativeCRPatcher patches the Knative custom resource with the type of patch
+type knativeCRPatcher func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error)
+
+// applyKnativeCR saves the change from the live to the mutated Knative custom resource with the server-side apply.
+// The apply configuration only contains the fields the plugin already manages and the fields changed by the mutators,
+// so that the plugin does not claim the ownership of any other field. The fields removed by the mutators are left out,
+// and the server removes them, as long as no other field manager owns them. The conflicts of the removed fields are
+// reported before anything is saved, unless --force-conflicts is set, in which case they are removed with an update
+// and the apply is forced. It returns false if the mutators change nothing.
+func (ko *KnativeOperatorCR) applyKnativeCR(ctx context.Context, kind string, exists bool, live, mutated runtime.Object,
+	patch knativeCRPatcher) (bool, error) {
+	liveContent, err := applyContent(live)
+	if err != nil {
+		return false, err
+	}
+	mutatedContent, err := applyContent(mutated)
+	if err != nil {
+		return false, err
+	}
+	if exists && reflect.DeepEqual(liveContent, mutatedContent) {
+		return false, nil
+	}
+
+	liveMeta, err := meta.Accessor(live)
+	if err != nil {
+		return false, err
+	}
+	name, namespace, resourceVersion := liveMeta.GetName(), liveMeta.GetNamespace(), liveMeta.GetResourceVersion()
+	managedFields := liveMeta.GetManagedFields()
+	body := extractOwnedFields(ownFieldSet(managedFields), liveContent)
+	removed := [][]string{}
+	mergeChanges(body, liveContent, mutatedContent, nil, &removed)
+
+	if paths, managers := conflictingRemovals(managedFields, removed); len(paths) > 0 {
+		if !ko.ForceConflicts {
+			return false, removalConflictError(kind, name, namespace, paths, managers)
+		}
+		// The server-side apply does not remove the fields owned by other field managers, so they are removed with
+		// an update, before the forced apply takes over the ownership of the other fields
+		data, err := json.Marshal(removalPatch(resourceVersion, paths))
+		if err != nil {
+			return false, err
+		}
+		result, err := patch(ctx, types.MergePatchType, data, metav1.PatchOptions{FieldManager: FieldManager})
+		if err != nil {
+			return false, err
+		}
+		resourceVersion = result.GetResourceVersion()
+	}
+
+	metadata, _ := body["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["name"], metadata["namespace"] = name, namespace
+	if resourceVersion != "" {
+		// The apply fails with a conflict, if the custom resource is changed after it was read
+		metadata["resourceVersion"] = resourceVersion
+	}
+	body["metadata"] = metadata
+	body["apiVersion"], body["kind"] = v1beta1.SchemeGroupVersion.String(), kind
+	data, err := json.Marshal(body)
+	if err != nil {
+		return false, err
+	}
+	if _, err = patch(ctx, types.ApplyPatchType, data, ko.patchOptions()); err != nil {
+		return false, applyConflictError(kind, name, namespace, err)
+	}
+	return true, nil
+}
+
+// applyContent returns the part of the Knative custom resource, which the plugin changes: the labels, the annotations
+// and the spec
+func applyContent(obj runtime.Object) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	metadata := map[string]interface{}{}
+	for _, field := range []string{"labels", "annotations"} {
+		if value, found, _ := unstructured.NestedFieldNoCopy(content, "metadata", field); found {
+			metadata[field] = value
+		}
+	}
+	if len(metadata) > 0 {
+		result["metadata"] = metadata
+	}
+	if spec, found := content["spec"]; found {
+		result["spec"] = spec
+	}
+	return result, nil
+}
+
+// mergeChanges sets the fields changed from live to mutated into the apply configuration, and deletes the removed
+// fields from it. The paths of the removed fields are appended to removed. Lists are changed as a whole.
+func mergeChanges(configuration, live, mutated map[string]interface{}, path []string, removed *[][]string) {
+	for name, value := range mutated {
+		current, found := live[name]
+		if found && reflect.DeepEqual(current, value) {
+			continue
+		}
+		currentMap, currentIsMap := current.(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		if !found || !currentIsMap || !valueIsMap {
+			configuration[name] = value
+			continue
+		}
+
+		child, owned := configuration[name].(map[string]interface{})
+		if !owned {
+			child = map[string]interface{}{}
+		}
+		mergeChanges(child, currentMap, valueMap, fieldPath(path, name), removed)
+		if owned || len(child) > 0 {
+			configuration[name] = child
+		}
+	}
+	for name := range live {
+		if _, found := mutated[name]; !found {
+			delete(configuration, name)
+			*removed = append(*removed, fieldPath(path, name))
+		}
+	}
+}
+
+func fieldPath(path []string, name string) []string {
+	result := make([]string, 0, len(path)+1)
+	return append(append(result, path...), name)
+}
+
+// conflictingRemovals returns the paths of the removed fields, which the server-side apply of the plugin does not
+// remove, because the plugin does not own them or other field managers own them as well. The other field managers
+// are returned as well.
+func conflictingRemovals(managedFields []metav1.ManagedFieldsEntry, removed [][]string) ([][]string, []string) {
+	own := ownFieldSet(managedFields)
+	paths := [][]string{}
+	found := map[string]bool{}
+	managers := []string{}
+	for _, path := range removed {
+		conflicting := !own.covers(path)
+		for _, entry := range managedFields {
+			if isOwnApplyEntry(entry) || !parseFieldSet(entry).covers(path) {
+				continue
+			}
+			conflicting = true
+			if !found[entry.Manager] {
+				found[entry.Manager] = true
+				managers = append(managers, entry.Manager)
+			}
+		}
+		if conflicting {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(managers)
+	return paths, managers
+}
+
+// removalPatch returns the JSON merge patch, which removes the fields at the paths from the custom resource at
+// the resource version
+func removalPatch(resourceVersion string, paths [][]string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, path := range paths {
+		parent := result
+		for _, name := range path[:len(path)-1] {
+			child, ok := parent[name].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[name] = child
+			}
+			parent = child
+		}
+		parent[path[len(path)-1]] = nil
+	}
+	metadata, ok := result["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		result["metadata"] = metadata
+	}
+	metadata["resourceVersion"] = resourceVersion
+	return result
+}
+
+// removalConflictError reports the removed fields, which the server-side apply of the plugin cannot remove
+func removalConflictError(kind, name, namespace string, paths [][]string, managers []string) error {
+	fields := make([]string, 0, len(paths))
+	for _, path := range paths {
+		fields = append(fields, "  ."+strings.Join(path, "."))
+	}
+	reason := "they are not managed by " + FieldManager
+	if len(managers) > 0 {
+		reason = "they are also managed by: " + strings.Join(managers, ", ")
+	}
+	return fmt.Errorf("The following fields cannot be removed from %s '%s' in the namespace '%s', because %s.\n%s\n"+
+		"Use --force-conflicts to take over the ownership of these fields and remove them.",
+		kind, name, namespace, reason, strings.Join(fields, LineWrapper))
+}
+
+// fieldSet is a set of fields in the format FieldsV1 of the managed fields, e.g. {"f:spec":{"f:version":{}}}
+type fieldSet map[string]interface{}
+
+func isOwnApplyEntry(entry metav1.ManagedFieldsEntry) bool {
+	return entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply && entry.Subresource == ""
+}
+
+// ownFieldSet returns the fields owned by the server-side apply of the plugin
+func ownFieldSet(managedFields []metav1.ManagedFieldsEntry) fieldSet {
+	for _, entry := range managedFields {
+		if isOwnApplyEntry(entry) {
+			return parseFieldSet(entry)
+		}
+	}
+	return fieldSet{}
+}
+
+// parseFieldSet returns the fields owned by the entry of the managed fields. The fields that cannot be read are empty.
+func parseFieldSet(entry metav1.ManagedFieldsEntry) fieldSet {
+	set := fieldSet{}
+	if entry.Subresource != "" || entry.FieldsV1 == nil {
+		return set
+	}
+	if err := json.Unmarshal(entry.FieldsV1.Raw, &set); err != nil {
+		return fieldSet{}
+	}
+	return set
+}
+
+// child returns the fields under the field with the name, and whether the field is in the set. A field without
+// any fields under it is owned as a whole.
+func (set fieldSet) child(name string) (fieldSet, bool) {
+	value, found := set["f:"+name]
+	if !found {
+		return nil, false
+	}
+	child, _ := value.(map[string]interface{})
+	return child, true
+}
+
+// covers checks if the set contains the field at the path, or any field under it
+func (set fieldSet) covers(path []string) bool {
+	for _, name := range path {
+		child, found := set.child(name)
+		if !found {
+			return false
+		}
+		if len(child) == 0 {
+			return true
+		}
+		set = child
+	}
+	return true
+}
+
+// extractOwnedFields returns the part of the content, which is owned according to the set of fields
+func extractOwnedFields(set fieldSet, content map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for name, value := range content {
+		child, found := set.child(name)
+		if !found {
+			continue
+		}
+		valueMap, isMap := value.(map[string]interface{})
+		if !isMap || len(child) == 0 {
+			result[name] = value
+			continue
+		}
+		result[name] = extractOwnedFields(child, valueMap)
+	}
+	return result
+}
+
+// serverSideApplier applies the resources of any kind with the server-side apply
+type serverSideApplier struct {
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
+	mapper          meta.RESTMapper
+	force           bool
+}
+
+// apply applies the resource. The mapping of the kinds is reloaded once, if the kind is not found, because
+// the CustomResourceDefinition of the resource may have been applied just before.
+func (applier *serverSideApplier) apply(ctx context.Context, resource *unstructured.Unstructured) error {
+	gvk := resource.GroupVersionKind()
+	mapping, err := applier.restMapping(false, gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		mapping, err = applier.restMapping(true, gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	var client dynamic.ResourceInterface = applier.dynamicClient.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		client = applier.dynamicClient.Resource(mapping.Resource).Namespace(resource.GetNamespace())
+	}
+	_, err = client.Apply(ctx, resource.GetName(), resource, metav1.ApplyOptions{
+		FieldManager: FieldManager,
+		Force:        applier.force,
+	})
+	if err != nil {
+		return applyConflictError(resource.GetKind(), resource.GetName(), resource.GetNamespace(), err)
+	}
+	return nil
+}
+
+func (applier *serverSideApplier) restMapping(reload bool, gk schema.GroupKind, version string) (*meta.RESTMapping, error) {
+	if applier.mapper == nil || reload {
+		groupResources, err := restmapper.GetAPIGroupResources(applier.discoveryClient)
+		if err != nil {
+			return nil, err
+		}
+		applier.mapper = restmapper.NewDiscoveryRESTMapper(groupResources)
+	}
+	return applier.mapper.RESTMapping(gk, version)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestApplyConfiguration(t *testing.T) {
+	live := &v1beta1.KnativeServing{}
+	live.Name, live.Namespace = "knative-serving", "test-ns"
+	live.Annotations = map[string]string{"owner": "platform"}
+	live.Spec.Version = "1.8"
+	live.Spec.Config = base.ConfigMapData{
+		"network": {"ingress-class": "kourier.ingress.networking.knative.dev"},
+		"domain":  {"example.com": ""},
+	}
+	live.Spec.HighAvailability = &base.HighAvailability{Replicas: ptrInt32(2)}
+	mutated := live.DeepCopy()
+	mutated.Spec.Config["network"]["domain-template"] = "{{.Name}}"
+	delete(mutated.Spec.Config, "domain")
+
+	liveContent, err := applyContent(live)
+	testingUtil.AssertEqual(t, err, nil)
+	mutatedContent, err := applyContent(mutated)
+	testingUtil.AssertEqual(t, err, nil)
+
+	// The plugin only manages the version and the domain, so the other fields are not sent
+	owned := fieldSet{"f:spec": map[string]interface{}{
+		"f:version": map[string]interface{}{},
+		"f:config":  map[string]interface{}{"f:domain": map[string]interface{}{}},
+	}}
+	configuration := extractOwnedFields(owned, liveContent)
+	removed := [][]string{}
+	mergeChanges(configuration, liveContent, mutatedContent, nil, &removed)
+
+	data, err := json.Marshal(configuration)
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, string(data), `{"spec":{"config":{"network":{"domain-template":"{{.Name}}"}},"version":"1.8"}}`)
+	testingUtil.AssertDeepEqual(t, removed, [][]string{{"spec", "config", "domain"}})
+}
+
+func ptrInt32(value int32) *int32 {
+	return &value
+}
+
+func TestSameSpec(t *testing.T) {
+	expected := v1beta1.KnativeServingSpec{}
+	expected.Config = base.ConfigMapData{"network": {"ingress-class": "kourier.ingress.networking.knative.dev"}}
+
+	actual := expected.DeepCopy()
+	testingUtil.AssertEqual(t, sameSpec(&expected, actual), true)
+
+	actual.Config["network"]["domain-template"] = "{{.Name}}"
+	testingUtil.AssertEqual(t, sameSpec(&expected, actual), false)
+}
+
+func TestApplyConflictError(t *testing.T) {
+	conflictErr := apierrs.NewApplyConflict([]metav1.StatusCause{{
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "argocd-controller"`,
+		Field:   ".spec.config.network.ingress-class",
+	}, {
+		Type:    metav1.CauseTypeFieldManagerConflict,
+		Message: `conflict with "flux"`,
+		Field:   ".spec.high-availability.replicas",
+	}}, "Apply failed with 2 conflicts")
+	notFoundErr := apierrs.NewNotFound(schema.GroupResource{Group: "operator.knative.dev", Resource: "knativeservings"},
+		"knative-serving")
+	otherConflictErr := apierrs.NewConflict(schema.GroupResource{Group: "operator.knative.dev", Resource: "knativeservings"},
+		"knative-serving", fmt.Errorf("the object has been modified"))
+
+	for _, tt := range []struct {
+		name          string
+		err           error
+		expectedError string
+	}{{
+		name: "Field manager conflicts",
+		err:  conflictErr,
+		expectedError: "Failed to apply KnativeServing 'knative-serving' in the namespace 'knative-serving', " +
+			"because the following fields are managed by other field managers:\n" +
+			"  .spec.config.network.ingress-class: conflict with \"argocd-controller\"\n" +
+			"  .spec.high-availability.replicas: conflict with \"flux\"\n" +
+			"Use --force-conflicts to take over the ownership of these fields.",
+	}, {
+		name:          "Other errors",
+		err:           notFoundErr,
+		expectedError: notFoundErr.Error(),
+	}, {
+		name:          "Conflict without field managers",
+		err:           otherConflictErr,
+		expectedError: otherConflictErr.Error(),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := applyConflictError("KnativeServing", "knative-serving", "knative-serving", tt.err)
+			testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestConflictingRemovals(t *testing.T) {
+	managedFields := []metav1.ManagedFieldsEntry{
+		managedFieldsEntry("kn-operator", metav1.ManagedFieldsOperationApply,
+			`{"f:spec":{"f:config":{"f:domain":{},"f:network":{"f:ingress-class":{}}},"f:version":{}}}`),
+		managedFieldsEntry("argocd-controller", metav1.ManagedFieldsOperationApply,
+			`{"f:spec":{"f:config":{"f:network":{"f:ingress-class":{}}}}}`),
+		managedFieldsEntry("kn-operator", metav1.ManagedFieldsOperationUpdate,
+			`{"f:spec":{"f:registry":{"f:default":{}}}}`),
+	}
+
+	paths, managers := conflictingRemovals(managedFields, [][]string{
+		{"spec", "config", "domain"},
+		{"spec", "config", "network"},
+		{"spec", "registry"},
+		{"spec", "high-availability"},
+	})
+	testingUtil.AssertDeepEqual(t, paths, [][]string{
+		{"spec", "config", "network"},
+		{"spec", "registry"},
+		{"spec", "high-availability"},
+	})
+	testingUtil.AssertDeepEqual(t, managers, []string{"argocd-controller", "kn-operator"})
+
+	err := removalConflictError("KnativeServing", "knative-serving", "test-ns", paths[:2], managers[:1])
+	testingUtil.AssertEqual(t, err.Error(), "The following fields cannot be removed from KnativeServing "+
+		"'knative-serving' in the namespace 'test-ns', because they are also managed by: argocd-controller.\n"+
+		"  .spec.config.network\n  .spec.registry\n"+
+		"Use --force-conflicts to take over the ownership of these fields and remove them.")
+}
+
+func TestRemovalPatch(t *testing.T) {
+	data, err := json.Marshal(removalPatch("42", [][]string{
+		{"spec", "config", "network"},
+		{"spec", "registry"},
+		{"metadata", "labels", "team"},
+	}))
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, string(data), `{"metadata":{"labels":{"team":null},"resourceVersion":"42"},`+
+		`"spec":{"config":{"network":null},"registry":null}}`)
+}
+
+func managedFieldsEntry(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  operation,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"golang.org/x/mod/semver"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"knative.dev/kn-plugin-operator/pkg"
+)
+
+//go:embed schema/configmaps.yaml
+var configSchemaContent []byte
+
+var (
+	configSchema     ConfigSchema
+	configSchemaErr  error
+	configSchemaOnce sync.Once
+)
+
+// KeySchema describes the type and the allowed values of a key in a Knative ConfigMap
+type KeySchema struct {
+	Type string `json:"type"`
+	// Min and Max are the allowed range of an int, a float or a duration
+	Min string `json:"min,omitempty"`
+	Max string `json:"max,omitempty"`
+	// Values are the allowed values of an enum, or the literal values accepted in addition to the type
+	Values []string `json:"values,omitempty"`
+	// Default is the default value used by Knative
+	Default string `json:"default,omitempty"`
+	// Maturity is the maturity of a feature flag, which is alpha, beta or stable
+	Maturity string `json:"maturity,omitempty"`
+}
+
+// ConfigMapSchema maps the keys of a Knative ConfigMap to their schemas
+type ConfigMapSchema map[string]KeySchema
+
+// VersionedSchema maps the Knative ConfigMaps to their schemas for the minor versions from MinVersion to MaxVersion,
+// both inclusive
+type VersionedSchema struct {
+	MinVersion string                     `json:"minVersion"`
+	MaxVersion string                     `json:"maxVersion"`
+	ConfigMaps map[string]ConfigMapSchema `json:"configMaps"`
+}
+
+// ConfigSchema maps the Knative components to the schemas of their ConfigMaps for the ranges of the versions
+type ConfigSchema map[string][]VersionedSchema
+
+// ConfigSchemaError is the error of a key or a value not matching the schema, which can be bypassed
+// with --allow-unknown
+type ConfigSchemaError struct {
+	Message string
+}
+
+func (e *ConfigSchemaError) Error() string {
+	return e.Message
+}
+
+// LoadConfigSchema returns the schema of the Knative ConfigMaps embedded in the plugin
+func LoadConfigSchema() (ConfigSchema, error) {
+	configSchemaOnce.Do(func() {
+		configSchemaErr = yaml.Unmarshal(configSchemaContent, &configSchema)
+	})
+	return configSchema, configSchemaErr
+}
+
+// FullConfigMapName returns the full name of the ConfigMap, which can be set in the Knative custom resource
+// without the prefix config-
+func FullConfigMapName(name string) string {
+	if strings.HasPrefix(name, "config-") || strings.HasPrefix(name, "default-") {
+		return name
+	}
+	return "config-" + name
+}
+
+// ConfigMaps returns the schemas of the ConfigMaps of the component in the range of the Knative version. The newest
+// range is returned for the version latest, nightly or empty. It returns false, if no range covers the version.
+func (schema ConfigSchema) ConfigMaps(component, version string) (map[string]ConfigMapSchema, bool) {
+	ranges := schema[strings.ToLower(component)]
+	v := minorVersion(version)
+	var newest *VersionedSchema
+	for i := range ranges {
+		if v == "" {
+			if newest == nil || semver.Compare(minorVersion(ranges[i].MaxVersion), minorVersion(newest.MaxVersion)) > 0 {
+				newest = &ranges[i]
+			}
+		} else if semver.Compare(v, minorVersion(ranges[i].MinVersion)) >= 0 &&
+			semver.Compare(v, minorVersion(ranges[i].MaxVersion)) <= 0 {
+			return ranges[i].ConfigMaps, true
+		}
+	}
+	if newest == nil {
+		return nil, false
+	}
+	return newest.ConfigMaps, true
+}
+
+// minorVersion returns the semantic version with only the major and the minor numbers, which is empty for the
+// version latest or nightly
+func minorVersion(version string) string {
+	return semver.MajorMinor(SemanticVersion(version))
+}
+
+// ValidateConfigMapData checks the key and the value against the schema of the ConfigMap for the Knative version.
+// The keys of a version outside all the ranges of the schema are not validated.
+func (schema ConfigSchema) ValidateConfigMapData(component, version, cmName, key, value string) error {
+	cmSchemas, ok := schema.ConfigMaps(component, version)
+	if !ok {
+		return nil
+	}
+	inVersion := ""
+	if SemanticVersion(version) != "" {
+		inVersion = fmt.Sprintf(" of the version %s", version)
+	}
+	name := FullConfigMapName(cmName)
+	cmSchema, ok := cmSchemas[name]
+	if !ok {
+		return unknownSchemaError("ConfigMap", cmName, fmt.Sprintf("Knative %s%s", component, inVersion), sortedKeys(cmSchemas))
+	}
+	if key == "_example" {
+		return nil
+	}
+
+	keySchema, ok := cmSchema.Lookup(key)
+	if !ok {
+		return unknownSchemaError("key", key, fmt.Sprintf("the ConfigMap '%s'%s", name, inVersion), cmSchema.Keys())
+	}
+	if err := keySchema.Validate(value); err != nil {
+		return &ConfigSchemaError{Message: fmt.Sprintf("Invalid value '%s' of the key '%s' in the ConfigMap '%s': %v.",
+			value, key, name, err)}
+	}
+	return nil
+}
+
+// Lookup finds the schema of the key, matching the keys ending with * by their prefixes
+func (cmSchema ConfigMapSchema) Lookup(key string) (KeySchema, bool) {
+	if keySchema, ok := cmSchema[key]; ok {
+		return keySchema, true
+	}
+	for pattern, keySchema := range cmSchema {
+		if strings.HasSuffix(pattern, "*") && strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+			return keySchema, true
+		}
+	}
+	return KeySchema{}, false
+}
+
+// Keys returns the sorted keys
+func (cmSchema ConfigMapSchema) Keys() []string {
+	keys := make([]string, 0, len(cmSchema))
+	for key := range cmSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the value against the type and the range of the key
+func (keySchema KeySchema) Validate(value string) error {
+	if keySchema.Type != "enum" && Contains(keySchema.Values, value) {
+		return nil
+	}
+
+	switch keySchema.Type {
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("expected a boolean")
+		}
+	case "int":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected an integer")
+		}
+		return keySchema.checkRange(float64(number), func(limit string) (float64, error) {
+			return strconv.ParseFloat(limit, 64)
+		})
+	case "float":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		return keySchema.checkRange(number, func(limit string) (float64, error) {
+			return strconv.ParseFloat(limit, 64)
+		})
+	case "duration":
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("expected a duration like 30s or 1m")
+		}
+		return keySchema.checkRange(float64(duration), func(limit string) (float64, error) {
+			d, err := time.ParseDuration(limit)
+			return float64(d), err
+		})
+	case "quantity":
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("expected a quantity like 100m or 128Mi")
+		}
+	case "url":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("expected an absolute URL")
+		}
+	case "enum":
+		for _, allowed := range keySchema.Values {
+			if strings.EqualFold(allowed, value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %s", strings.Join(keySchema.Values, ", "))
+	}
+	return nil
+}
+
+func (keySchema KeySchema) checkRange(number float64, parse func(limit string) (float64, error)) error {
+	if keySchema.Min != "" {
+		if lower, err := parse(keySchema.Min); err == nil && number < lower {
+			return fmt.Errorf("expected at least %s", keySchema.Min)
+		}
+	}
+	if keySchema.Max != "" {
+		if upper, err := parse(keySchema.Max); err == nil && number > upper {
+			return fmt.Errorf("expected at most %s", keySchema.Max)
+		}
+	}
+	return nil
+}
+
+func unknownSchemaError(kind, name, scope string, candidates []string) error {
+	message := fmt.Sprintf("The %s '%s' is not known in %s.", kind, name, scope)
+	if suggestions := Suggestions(name, candidates); len(suggestions) > 0 {
+		message += fmt.Sprintf(" Did you mean: %s?", strings.Join(suggestions, ", "))
+	}
+	return &ConfigSchemaError{Message: message}
+}
+
+func sortedKeys(cmSchemas map[string]ConfigMapSchema) []string {
+	keys := make([]string, 0, len(cmSchemas))
+	for key := range cmSchemas {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CheckConfigMapData validates the key-value pairs of the ConfigMap against the schema for the version in the spec of
+// the Knative custom resource under the namespace. The newest schema is used, if the version is not set or cannot be
+// found. The errors of the schema are reported as warnings instead, if allowUnknown is set.
+func CheckConfigMapData(component, namespace, cmName string, pairs []KeyValuePair, allowUnknown bool, p *pkg.OperatorParams) error {
+	schema, err := LoadConfigSchema()
+	if err != nil {
+		return err
+	}
+
+	version := ""
+	if ko, err := GetKnativeOperatorCR(p); err == nil {
+		version, _ = ko.GetSpecVersion(p.Context(), component, namespace)
+	}
+	if _, ok := schema.ConfigMaps(component, version); !ok {
+		p.Warn("The ConfigMaps of Knative %s %s are not known to this plugin, the keys are not validated.", component, version)
+		return nil
+	}
+
+	for _, pair := range pairs {
+		err := schema.ValidateConfigMapData(component, version, cmName, pair.Key, pair.Value)
+		var schemaErr *ConfigSchemaError
+		if errors.As(err, &schemaErr) && allowUnknown {
+			p.Warn("%s", schemaErr.Message)
+		} else if err != nil {
+			return fmt.Errorf("%w\nUse --allow-unknown to apply it anyway.", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestFullConfigMapName(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		expected string
+	}{{
+		name:     "autoscaler",
+		expected: "config-autoscaler",
+	}, {
+		name:     "config-autoscaler",
+		expected: "config-autoscaler",
+	}, {
+		name:     "default-ch-webhook",
+		expected: "default-ch-webhook",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			testingUtil.AssertEqual(t, FullConfigMapName(tt.name), tt.expected)
+		})
+	}
+}
+
+func TestValidateConfigMapData(t *testing.T) {
+	schema, err := LoadConfigSchema()
+	testingUtil.AssertEqual(t, err, nil)
+
+	for _, tt := range []struct {
+		name          string
+		component     string
+		version       string
+		cmName        string
+		key           string
+		value         string
+		expectedError string
+	}{{
+		name:      "Valid float",
+		component: "serving",
+		cmName:    "config-autoscaler",
+		key:       "container-concurrency-target-default",
+		value:     "50",
+	}, {
+		name:      "Valid key in the ConfigMap without prefix",
+		component: "serving",
+		cmName:    "autoscaler",
+		key:       "enable-scale-to-zero",
+		value:     "false",
+	}, {
+		name:      "Example is always allowed",
+		component: "serving",
+		cmName:    "config-autoscaler",
+		key:       "_example",
+		value:     "anything",
+	}, {
+		name:      "Unknown key with a suggestion",
+		component: "serving",
+		cmName:    "config-autoscaler",
+		key:       "container-concurency-target-default",
+		value:     "50",
+		expectedError: "The key 'container-concurency-target-default' is not known in the ConfigMap 'config-autoscaler'. " +
+			"Did you mean: container-concurrency-target-default",
+	}, {
+		name:          "Unknown ConfigMap",
+		component:     "serving",
+		cmName:        "config-unknown-map",
+		key:           "key",
+		value:         "value",
+		expectedError: "The ConfigMap 'config-unknown-map' is not known in Knative serving.",
+	}, {
+		name:          "Invalid bool",
+		component:     "serving",
+		cmName:        "config-autoscaler",
+		key:           "enable-scale-to-zero",
+		value:         "maybe",
+		expectedError: "Invalid value 'maybe' of the key 'enable-scale-to-zero' in the ConfigMap 'config-autoscaler': expected a boolean.",
+	}, {
+		name:          "Int out of range",
+		component:     "serving",
+		cmName:        "config-autoscaler",
+		key:           "max-scale",
+		value:         "-1",
+		expectedError: "Invalid value '-1' of the key 'max-scale' in the ConfigMap 'config-autoscaler': expected at least 0.",
+	}, {
+		name:          "Duration out of range",
+		component:     "serving",
+		cmName:        "config-autoscaler",
+		key:           "scale-to-zero-grace-period",
+		value:         "-5s",
+		expectedError: "Invalid value '-5s' of the key 'scale-to-zero-grace-period' in the ConfigMap 'config-autoscaler': expected at least 0s.",
+	}, {
+		name:          "Invalid quantity",
+		component:     "serving",
+		cmName:        "config-defaults",
+		key:           "revision-cpu-limit",
+		value:         "one-cpu",
+		expectedError: "Invalid value 'one-cpu' of the key 'revision-cpu-limit' in the ConfigMap 'config-defaults': expected a quantity like 100m or 128Mi.",
+	}, {
+		name:          "Invalid URL",
+		component:     "serving",
+		cmName:        "config-tracing",
+		key:           "zipkin-endpoint",
+		value:         "zipkin:9411",
+		expectedError: "Invalid value 'zipkin:9411' of the key 'zipkin-endpoint' in the ConfigMap 'config-tracing': expected an absolute URL.",
+	}, {
+		name:      "Enum matched by pattern",
+		component: "eventing",
+		cmName:    "config-logging",
+		key:       "loglevel.controller",
+		value:     "debug",
+	}, {
+		name:          "Invalid enum matched by pattern",
+		component:     "serving",
+		cmName:        "config-logging",
+		key:           "loglevel.webhook",
+		value:         "verbose",
+		expectedError: "Invalid value 'verbose' of the key 'loglevel.webhook' in the ConfigMap 'config-logging': expected one of debug, info, warn, error, dpanic, panic, fatal.",
+	}, {
+		name:      "Key known in the latest version",
+		component: "serving",
+		version:   "latest",
+		cmName:    "config-network",
+		key:       "external-domain-tls",
+		value:     "Enabled",
+	}, {
+		name:      "Key known in the range of the version",
+		component: "serving",
+		version:   "1.12.0",
+		cmName:    "config-network",
+		key:       "external-domain-tls",
+		value:     "Enabled",
+	}, {
+		name:          "Key not known in an older version",
+		component:     "serving",
+		version:       "1.11",
+		cmName:        "config-network",
+		key:           "external-domain-tls",
+		value:         "Enabled",
+		expectedError: "The key 'external-domain-tls' is not known in the ConfigMap 'config-network' of the version 1.11.",
+	}, {
+		name:      "Key not validated in a version out of the ranges",
+		component: "serving",
+		version:   "1.30.0",
+		cmName:    "config-network",
+		key:       "new-network-key",
+		value:     "value",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateConfigMapData(tt.component, tt.version, tt.cmName, tt.key, tt.value)
+			if tt.expectedError == "" {
+				testingUtil.AssertEqual(t, err, nil)
+			} else {
+				testingUtil.AssertEqual(t, err.Error()[:min(len(err.Error()), len(tt.expectedError))], tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestConfigMaps(t *testing.T) {
+	schema := ConfigSchema{
+		"serving": {{
+			MinVersion: "1.12",
+			MaxVersion: "1.17",
+			ConfigMaps: map[string]ConfigMapSchema{"config-network": {"external-domain-tls": {Type: "string"}}},
+		}, {
+			MinVersion: "1.8",
+			MaxVersion: "1.11",
+			ConfigMaps: map[string]ConfigMapSchema{"config-network": {"domain-template": {Type: "string"}}},
+		}},
+	}
+	for _, tt := range []struct {
+		name        string
+		component   string
+		version     string
+		expectedKey string
+		expectedOK  bool
+	}{{
+		name:        "Newest range for the latest version",
+		component:   "Serving",
+		version:     "latest",
+		expectedKey: "external-domain-tls",
+		expectedOK:  true,
+	}, {
+		name:        "Newest range for an empty version",
+		component:   "serving",
+		expectedKey: "external-domain-tls",
+		expectedOK:  true,
+	}, {
+		name:        "Lower bound of a range",
+		component:   "serving",
+		version:     "1.8.0",
+		expectedKey: "domain-template",
+		expectedOK:  true,
+	}, {
+		name:        "Upper bound of a range with a patch",
+		component:   "serving",
+		version:     "v1.11.3",
+		expectedKey: "domain-template",
+		expectedOK:  true,
+	}, {
+		name:      "Version newer than all the ranges",
+		component: "serving",
+		version:   "1.18.0",
+	}, {
+		name:      "Version older than all the ranges",
+		component: "serving",
+		version:   "1.7",
+	}, {
+		name:      "Unknown component",
+		component: "eventing",
+		version:   "1.12",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			cmSchemas, ok := schema.ConfigMaps(tt.component, tt.version)
+			testingUtil.AssertEqual(t, ok, tt.expectedOK)
+			if tt.expectedOK {
+				testingUtil.AssertDeepEqual(t, cmSchemas["config-network"].Keys(), []string{tt.expectedKey})
+			}
+		})
+	}
+}
@@ -38,20 +38,20 @@ type Deployment struct {
 }
 
 // CheckIfOperatorInstalled checks if Knative Operator exists
-func (d *Deployment) CheckIfOperatorInstalled() (bool, string, string, error) {
-	return d.CheckIfKeyDeploymentInstalled(KnativeOperatorName)
+func (d *Deployment) CheckIfOperatorInstalled(ctx context.Context) (bool, string, string, error) {
+	return d.CheckIfKeyDeploymentInstalled(ctx, KnativeOperatorName)
 }
 
 // CheckIfOperatorInstalled checks if Knative Component exists
-func (d *Deployment) CheckIfKnativeInstalled(component string) (bool, string, string, error) {
+func (d *Deployment) CheckIfKnativeInstalled(ctx context.Context, component string) (bool, string, string, error) {
 	if strings.EqualFold(component, ServingComponent) {
-		return d.CheckIfKnativeServingInstalled()
+		return d.CheckIfKnativeServingInstalled(ctx)
 	}
-	return d.CheckIfKnativeEventingInstalled()
+	return d.CheckIfKnativeEventingInstalled(ctx)
 }
 
-func (d *Deployment) CheckIfKeyDeploymentInstalled(name string) (bool, string, string, error) {
-	namespaces, err := d.Client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+func (d *Deployment) CheckIfKeyDeploymentInstalled(ctx context.Context, name string) (bool, string, string, error) {
+	namespaces, err := d.Client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return false, "", "", fmt.Errorf("failed to receive a namespace list: %s", err)
 	}
@@ -60,7 +60,7 @@ func (d *Deployment) CheckIfKeyDeploymentInstalled(name string) (bool, string, s
 	namespace := ""
 	version := ""
 	for _, ns := range namespaces.Items {
-		deploy, err := d.Client.AppsV1().Deployments(ns.ObjectMeta.Name).Get(context.TODO(), name, metav1.GetOptions{})
+		deploy, err := d.Client.AppsV1().Deployments(ns.ObjectMeta.Name).Get(ctx, name, metav1.GetOptions{})
 		if apierrors.IsNotFound(err) {
 			continue
 		} else if err != nil {
@@ -77,10 +77,10 @@ func (d *Deployment) CheckIfKeyDeploymentInstalled(name string) (bool, string, s
 	return found, namespace, version, nil
 }
 
-func (d *Deployment) CheckIfKnativeServingInstalled() (bool, string, string, error) {
-	return d.CheckIfKeyDeploymentInstalled(KnativeServingActivator)
+func (d *Deployment) CheckIfKnativeServingInstalled(ctx context.Context) (bool, string, string, error) {
+	return d.CheckIfKeyDeploymentInstalled(ctx, KnativeServingActivator)
 }
 
-func (d *Deployment) CheckIfKnativeEventingInstalled() (bool, string, string, error) {
-	return d.CheckIfKeyDeploymentInstalled(KnativeEventingController)
+func (d *Deployment) CheckIfKnativeEventingInstalled(ctx context.Context) (bool, string, string, error) {
+	return d.CheckIfKeyDeploymentInstalled(ctx, KnativeEventingController)
 }
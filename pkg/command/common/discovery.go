@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/kn-plugin-operator/pkg"
+)
+
+// componentSelectors are the label selectors of the deployments shipped by the release of a Knative component,
+// including the ones of the ingresses and the eventing sources
+var componentSelectors = map[string]string{
+	ServingComponent:  "app.kubernetes.io/name=knative-serving",
+	EventingComponent: "app.kubernetes.io/name=knative-eventing",
+}
+
+// ComponentSelector returns the label selector of the deployments of the Knative component
+func ComponentSelector(component string) string {
+	return componentSelectors[strings.ToLower(component)]
+}
+
+// ComponentDeployments maps the names of the deployments of a Knative component to the names of their containers
+type ComponentDeployments map[string][]string
+
+// Names returns the sorted names of the deployments
+func (deployments ComponentDeployments) Names() []string {
+	names := make([]string, 0, len(deployments))
+	for name := range deployments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiscoverDeployments finds the deployments installed for the Knative component under the namespace, by the labels
+// of the component release.
+func DiscoverDeployments(ctx context.Context, client kubernetes.Interface, component, namespace string) (ComponentDeployments, error) {
+	dpList, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: ComponentSelector(component),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return componentDeployments(dpList.Items), nil
+}
+
+func componentDeployments(items []v1.Deployment) ComponentDeployments {
+	deployments := ComponentDeployments{}
+	for _, deployment := range items {
+		containers := []string{}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			containers = append(containers, container.Name)
+		}
+		deployments[deployment.Name] = containers
+	}
+	return deployments
+}
+
+// Validate checks that the deployment, and the container if it is not empty, exist. The error suggests the names
+// close to the unknown one.
+func (deployments ComponentDeployments) Validate(component, deployName, container string) error {
+	containers, ok := deployments[deployName]
+	if !ok {
+		return unknownNameError("deployment", deployName, fmt.Sprintf("Knative %s", component), deployments.Names())
+	}
+	if container != "" && !Contains(containers, container) {
+		return unknownNameError("container", container, fmt.Sprintf("the deployment '%s'", deployName), containers)
+	}
+	return nil
+}
+
+func unknownNameError(kind, name, scope string, candidates []string) error {
+	message := fmt.Sprintf("The %s '%s' is not found in %s.", kind, name, scope)
+	if suggestions := Suggestions(name, candidates); len(suggestions) > 0 {
+		message += fmt.Sprintf(" Did you mean: %s?", strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("%s\nAvailable %ss: %s", message, kind, strings.Join(candidates, ", "))
+}
+
+// CheckDeployment verifies that the deployment, and the container if it is not empty, exist in the Knative component
+// under the namespace. The check is skipped, if no deployment of the component is found, e.g. when the component is
+// configured before it is installed.
+func CheckDeployment(component, namespace, deployName, container string, p *pkg.OperatorParams) error {
+	if deployName == "" {
+		return nil
+	}
+	client, err := p.NewKubeClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	deployments, err := DiscoverDeployments(p.Context(), client, component, namespace)
+	if err != nil {
+		return err
+	}
+	if len(deployments) == 0 {
+		return nil
+	}
+	return deployments.Validate(component, deployName, container)
+}
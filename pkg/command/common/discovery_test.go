@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func testDeployment(name string, containers ...string) v1.Deployment {
+	deployment := v1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, container := range containers {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers,
+			corev1.Container{Name: container})
+	}
+	return deployment
+}
+
+func TestComponentDeployments(t *testing.T) {
+	items := []v1.Deployment{
+		testDeployment("activator", "activator"),
+		testDeployment("net-kourier-controller", "controller"),
+		testDeployment("webhook"),
+	}
+
+	testingUtil.AssertDeepEqual(t, componentDeployments(items), ComponentDeployments{
+		"activator":              {"activator"},
+		"net-kourier-controller": {"controller"},
+		"webhook":                {},
+	})
+}
+
+func TestComponentSelector(t *testing.T) {
+	testingUtil.AssertEqual(t, ComponentSelector("serving"), "app.kubernetes.io/name=knative-serving")
+	testingUtil.AssertEqual(t, ComponentSelector("Eventing"), "app.kubernetes.io/name=knative-eventing")
+}
+
+func TestValidateDeployment(t *testing.T) {
+	deployments := ComponentDeployments{
+		"activator":      {"activator"},
+		"autoscaler":     {"autoscaler"},
+		"autoscaler-hpa": {"autoscaler-hpa"},
+		"webhook":        {"webhook"},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		deployName    string
+		container     string
+		expectedError string
+	}{{
+		name:       "Known deployment and container",
+		deployName: "activator",
+		container:  "activator",
+	}, {
+		name:       "Known deployment without container",
+		deployName: "webhook",
+	}, {
+		name:       "Typo in the deployment",
+		deployName: "activater",
+		expectedError: "The deployment 'activater' is not found in Knative serving. Did you mean: activator?\n" +
+			"Available deployments: activator, autoscaler, autoscaler-hpa, webhook",
+	}, {
+		name:       "Unknown deployment without suggestions",
+		deployName: "queue-proxy",
+		expectedError: "The deployment 'queue-proxy' is not found in Knative serving.\n" +
+			"Available deployments: activator, autoscaler, autoscaler-hpa, webhook",
+	}, {
+		name:       "Typo in the container",
+		deployName: "activator",
+		container:  "activatr",
+		expectedError: "The container 'activatr' is not found in the deployment 'activator'. Did you mean: activator?\n" +
+			"Available containers: activator",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := deployments.Validate("serving", tt.deployName, tt.container)
+			if tt.expectedError == "" {
+				testingUtil.AssertEqual(t, err, nil)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package common
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -76,3 +77,17 @@ func DownloadFile(url string) (string, error) {
 
 	return "", fmt.Errorf("http status code is %d, not 200", resp.StatusCode)
 }
+
+// URLExists checks if an online file can be downloaded. It only fails, if the request is canceled by the context.
+func URLExists(ctx context.Context, url string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false, nil
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, ctx.Err()
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
@@ -22,6 +22,9 @@ type CMsFlags struct {
 	Component string
 	Namespace string
 	CMName    string
+	// AllowUnknown reports the keys and the values not matching the schema as warnings instead of errors
+	AllowUnknown bool
+	KeyValueSources
 }
 
 type KeyValueFlags struct {
@@ -35,4 +38,5 @@ type KeyValueFlags struct {
 	NodeSelector bool
 	Annotation   bool
 	Label        bool
+	KeyValueSources
 }
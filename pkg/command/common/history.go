@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"knative.dev/kn-plugin-operator/pkg"
+)
+
+const (
+	// HistoryConfigMapPrefix is the prefix of the name of the ConfigMap saving the revisions of a Knative component
+	HistoryConfigMapPrefix = "kn-operator-history-"
+	// MaxRevisions is the maximum number of revisions kept in the history
+	MaxRevisions = 20
+)
+
+// Revision is a snapshot of the spec of the Knative custom resource, taken before the plugin changed it
+type Revision struct {
+	Revision  int             `json:"revision"`
+	Timestamp time.Time       `json:"timestamp"`
+	User      string          `json:"user,omitempty"`
+	Command   string          `json:"command,omitempty"`
+	Spec      json.RawMessage `json:"spec"`
+}
+
+// History is used to access the revisions of the Knative custom resources saved in the Kubernetes cluster.
+type History struct {
+	KubeClient kubernetes.Interface
+}
+
+// Record saves the revision as the newest one in the history of the component under a certain namespace.
+// It returns the number assigned to the revision. The history is read again and the revision is renumbered,
+// if another command changed the history at the same time.
+func (h *History) Record(ctx context.Context, component, namespace string, revision Revision) (int, error) {
+	name := historyConfigMapName(component)
+	number := 0
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := h.KubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			var data map[string]string
+			data, number, err = addRevision(nil, revision, MaxRevisions)
+			if err != nil {
+				return err
+			}
+			configMap := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels:    map[string]string{"app.kubernetes.io/managed-by": "kn-operator"},
+				},
+				Data: data,
+			}
+			_, err = h.KubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+			if apierrs.IsAlreadyExists(err) {
+				// Another command created the history in the meantime, so the revision is added to it
+				return apierrs.NewConflict(v1.Resource("configmaps"), name, err)
+			}
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		cm.Data, number, err = addRevision(cm.Data, revision, MaxRevisions)
+		if err != nil {
+			return err
+		}
+		_, err = h.KubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// List returns the revisions of the component under a certain namespace, from the oldest to the newest
+func (h *History) List(ctx context.Context, component, namespace string) ([]Revision, error) {
+	cm, err := h.KubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, historyConfigMapName(component), metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return parseRevisions(cm.Data)
+}
+
+// Get returns the revision of the component under a certain namespace by its number
+func (h *History) Get(ctx context.Context, component, namespace string, number int) (*Revision, error) {
+	revisions, err := h.List(ctx, component, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if revisions[i].Revision == number {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("The revision %d of Knative %s is not found in the namespace '%s'.", number, component, namespace)
+}
+
+// RecordSpec saves the spec of the Knative custom resource in the history, as the spec before the latest change
+func RecordSpec(component, namespace string, spec interface{}, p *pkg.OperatorParams) error {
+	specData, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	history := History{
+		KubeClient: kubeClient,
+	}
+	_, err = history.Record(p.Context(), component, namespace, Revision{
+		Timestamp: time.Now().UTC(),
+		User:      currentUser(p),
+		Command:   commandLine(os.Args),
+		Spec:      specData,
+	})
+	return err
+}
+
+func historyConfigMapName(component string) string {
+	return HistoryConfigMapPrefix + strings.ToLower(component)
+}
+
+// addRevision adds the revision into the data of the history ConfigMap with the next revision number,
+// and drops the oldest revisions exceeding the maximum number.
+func addRevision(data map[string]string, revision Revision, max int) (map[string]string, int, error) {
+	revisions, err := parseRevisions(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	revision.Revision = 1
+	if len(revisions) > 0 {
+		revision.Revision = revisions[len(revisions)-1].Revision + 1
+	}
+	revisions = append(revisions, revision)
+	if len(revisions) > max {
+		revisions = revisions[len(revisions)-max:]
+	}
+
+	result := make(map[string]string, len(revisions))
+	for _, r := range revisions {
+		content, err := json.Marshal(r)
+		if err != nil {
+			return nil, 0, err
+		}
+		result[strconv.Itoa(r.Revision)] = string(content)
+	}
+	return result, revision.Revision, nil
+}
+
+// parseRevisions reads the revisions from the data of the history ConfigMap, sorted by the revision number
+func parseRevisions(data map[string]string) ([]Revision, error) {
+	revisions := make([]Revision, 0, len(data))
+	for key, content := range data {
+		var revision Revision
+		if err := json.Unmarshal([]byte(content), &revision); err != nil {
+			return nil, fmt.Errorf("failed to read the revision %s: %w", key, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// currentUser returns the user the plugin acts as: the impersonated user, the kubeconfig user of the context selected
+// by the flags, or the user of the operating system
+func currentUser(p *pkg.OperatorParams) string {
+	if restConfig, err := p.RestConfig(); err == nil && restConfig.Impersonate.UserName != "" {
+		return restConfig.Impersonate.UserName
+	}
+	if p.ConfigOverrides.Context.AuthInfo != "" {
+		return p.ConfigOverrides.Context.AuthInfo
+	}
+	if p.ClientConfig != nil {
+		if rawConfig, err := p.ClientConfig.RawConfig(); err == nil {
+			contextName := rawConfig.CurrentContext
+			if p.ConfigOverrides.CurrentContext != "" {
+				contextName = p.ConfigOverrides.CurrentContext
+			}
+			if kubeContext, ok := rawConfig.Contexts[contextName]; ok && kubeContext.AuthInfo != "" {
+				return kubeContext.AuthInfo
+			}
+		}
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// commandLine returns the command line of the plugin invocation
+func commandLine(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	result := []string{filepath.Base(args[0])}
+	return strings.Join(append(result, args[1:]...), " ")
+}
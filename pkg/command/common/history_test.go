@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestAddRevision(t *testing.T) {
+	timestamp := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	data := map[string]string{}
+	for i := 1; i <= 4; i++ {
+		var number int
+		var err error
+		data, number, err = addRevision(data, Revision{
+			Timestamp: timestamp,
+			User:      "test-user",
+			Command:   "kn-operator configure replicas",
+			Spec:      json.RawMessage(`{"version":"1.5"}`),
+		}, 3)
+		testingUtil.AssertEqual(t, err, nil)
+		testingUtil.AssertEqual(t, number, i)
+	}
+
+	revisions, err := parseRevisions(data)
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, len(revisions), 3)
+	testingUtil.AssertEqual(t, revisions[0].Revision, 2)
+	testingUtil.AssertEqual(t, revisions[2].Revision, 4)
+	testingUtil.AssertEqual(t, revisions[2].User, "test-user")
+	testingUtil.AssertEqual(t, revisions[2].Timestamp.Equal(timestamp), true)
+	testingUtil.AssertEqual(t, string(revisions[2].Spec), `{"version":"1.5"}`)
+}
+
+func TestParseRevisionsInvalidContent(t *testing.T) {
+	_, err := parseRevisions(map[string]string{"1": "invalid"})
+	testingUtil.AssertEqual(t, err == nil, false)
+}
+
+func TestHistoryConfigMapName(t *testing.T) {
+	testingUtil.AssertEqual(t, historyConfigMapName("Serving"), "kn-operator-history-serving")
+	testingUtil.AssertEqual(t, historyConfigMapName("eventing"), "kn-operator-history-eventing")
+}
+
+func TestCommandLine(t *testing.T) {
+	testingUtil.AssertEqual(t, commandLine(nil), "")
+	testingUtil.AssertEqual(t, commandLine([]string{"/usr/local/bin/kn-operator", "configure", "replicas"}),
+		"kn-operator configure replicas")
+}
+
+func TestCurrentUser(t *testing.T) {
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Clusters["test-cluster"] = &clientcmdapi.Cluster{Server: "https://127.0.0.1:6443"}
+	kubeConfig.AuthInfos["admin"] = &clientcmdapi.AuthInfo{Token: "admin-token"}
+	kubeConfig.AuthInfos["developer"] = &clientcmdapi.AuthInfo{Token: "developer-token"}
+	kubeConfig.Contexts["admin-context"] = &clientcmdapi.Context{Cluster: "test-cluster", AuthInfo: "admin"}
+	kubeConfig.Contexts["developer-context"] = &clientcmdapi.Context{Cluster: "test-cluster", AuthInfo: "developer"}
+	kubeConfig.CurrentContext = "admin-context"
+
+	for _, tt := range []struct {
+		name      string
+		overrides clientcmd.ConfigOverrides
+		expected  string
+	}{{
+		name:     "Current context",
+		expected: "admin",
+	}, {
+		name:      "Context set by --context",
+		overrides: clientcmd.ConfigOverrides{CurrentContext: "developer-context"},
+		expected:  "developer",
+	}, {
+		name:      "User set by --user",
+		overrides: clientcmd.ConfigOverrides{Context: clientcmdapi.Context{AuthInfo: "developer"}},
+		expected:  "developer",
+	}, {
+		name:      "User impersonated by --as",
+		overrides: clientcmd.ConfigOverrides{AuthInfo: clientcmdapi.AuthInfo{Impersonate: "jane"}},
+		expected:  "jane",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &pkg.OperatorParams{ConfigOverrides: tt.overrides}
+			p.ClientConfig = clientcmd.NewNonInteractiveClientConfig(*kubeConfig, "", &p.ConfigOverrides, nil)
+			testingUtil.AssertEqual(t, currentUser(p), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// KeyValuePair is a key with its value
+type KeyValuePair struct {
+	Key   string
+	Value string
+}
+
+// KeyValueSources are the repeatable sources of the key-value pairs, which are set in a single update
+type KeyValueSources struct {
+	// Set are the pairs given as key=value
+	Set []string
+	// FromFile are the files given as [key=]path, each setting the key to the content of the file.
+	// The key is the name of the file by default. All the files in a directory are used.
+	FromFile []string
+	// FromEnvFile are the files with a key=value pair on each line
+	FromEnvFile []string
+}
+
+// AddKeyValueSourceFlags adds the flags of the key-value sources
+func AddKeyValueSourceFlags(flags *pflag.FlagSet, sources *KeyValueSources) {
+	flags.StringArrayVar(&sources.Set, "set", []string{}, "The key-value pair as key=value, this flag can be repeated")
+	flags.StringArrayVar(&sources.FromFile, "from-file", []string{},
+		"The file as [key=]path, whose content is the value of the key (default key is the file name), this flag can be repeated")
+	flags.StringArrayVar(&sources.FromEnvFile, "from-env-file", []string{},
+		"The file with a key=value pair on each line, this flag can be repeated")
+}
+
+// IsEmpty checks if no key-value pair is given by the sources
+func (sources KeyValueSources) IsEmpty() bool {
+	return len(sources.Set) == 0 && len(sources.FromFile) == 0 && len(sources.FromEnvFile) == 0
+}
+
+// Pairs returns the key-value pairs of the sources, preceded by the key and the value if the key is not empty.
+// The pairs are in the order they are given, so a later pair of the same key overrides the earlier one.
+func (sources KeyValueSources) Pairs(key, value string) ([]KeyValuePair, error) {
+	pairs := []KeyValuePair{}
+	if key != "" {
+		pairs = append(pairs, KeyValuePair{Key: key, Value: value})
+	}
+
+	for _, item := range sources.Set {
+		pair, err := parseKeyValue(item)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' of --set: %w", item, err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	for _, item := range sources.FromFile {
+		filePairs, err := readFromFile(item)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' of --from-file: %w", item, err)
+		}
+		pairs = append(pairs, filePairs...)
+	}
+
+	for _, item := range sources.FromEnvFile {
+		filePairs, err := readFromEnvFile(item)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' of --from-env-file: %w", item, err)
+		}
+		pairs = append(pairs, filePairs...)
+	}
+	return pairs, nil
+}
+
+func parseKeyValue(item string) (KeyValuePair, error) {
+	key, value, found := strings.Cut(item, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return KeyValuePair{}, fmt.Errorf("the pair needs to be in the format key=value")
+	}
+	return KeyValuePair{Key: key, Value: value}, nil
+}
+
+// readFromFile reads the file of [key=]path, or all the regular files in the directory of path
+func readFromFile(item string) ([]KeyValuePair, error) {
+	key, path, found := strings.Cut(item, "=")
+	if !found {
+		key, path = "", item
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		if key == "" {
+			key = filepath.Base(path)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []KeyValuePair{{Key: key, Value: string(content)}}, nil
+	}
+
+	if found {
+		return nil, fmt.Errorf("a key cannot be given for the directory")
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	pairs := []KeyValuePair{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, KeyValuePair{Key: entry.Name(), Value: string(content)})
+	}
+	return pairs, nil
+}
+
+// readFromEnvFile reads the pairs of key=value on each line. The empty lines and the lines starting with #
+// are skipped.
+func readFromEnvFile(path string) ([]KeyValuePair, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []KeyValuePair{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pair, err := parseKeyValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, scanner.Err()
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestKeyValueSourcesPairs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	envFile := writeFile("autoscaler.env", "# The autoscaler settings\nmax-scale=10\n\nmin-scale=1\n")
+	badEnvFile := writeFile("bad.env", "max-scale=10\nmin-scale\n")
+	domainFile := writeFile("domain.yaml", "example.com: |\n")
+	filesDir := filepath.Join(dir, "files")
+	if err := os.Mkdir(filesDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(filesDir, "key1"), []byte("value1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name          string
+		sources       KeyValueSources
+		key           string
+		value         string
+		expected      []KeyValuePair
+		expectedError string
+	}{{
+		name:     "Single key and value",
+		key:      "key",
+		value:    "value",
+		expected: []KeyValuePair{{Key: "key", Value: "value"}},
+	}, {
+		name:    "Repeated set",
+		key:     "key",
+		value:   "value",
+		sources: KeyValueSources{Set: []string{"max-scale=10", "url=http://a?b=c", "empty="}},
+		expected: []KeyValuePair{
+			{Key: "key", Value: "value"},
+			{Key: "max-scale", Value: "10"},
+			{Key: "url", Value: "http://a?b=c"},
+			{Key: "empty", Value: ""},
+		},
+	}, {
+		name:          "Set without value",
+		sources:       KeyValueSources{Set: []string{"max-scale"}},
+		expectedError: "Invalid value 'max-scale' of --set: the pair needs to be in the format key=value",
+	}, {
+		name:    "From files",
+		sources: KeyValueSources{FromFile: []string{domainFile, "template=" + domainFile, filesDir}},
+		expected: []KeyValuePair{
+			{Key: "domain.yaml", Value: "example.com: |\n"},
+			{Key: "template", Value: "example.com: |\n"},
+			{Key: "key1", Value: "value1"},
+		},
+	}, {
+		name:          "From a missing file",
+		sources:       KeyValueSources{FromFile: []string{filepath.Join(dir, "missing")}},
+		expectedError: "Invalid value '" + filepath.Join(dir, "missing") + "' of --from-file: stat " + filepath.Join(dir, "missing") + ": no such file or directory",
+	}, {
+		name:    "From env files",
+		sources: KeyValueSources{FromEnvFile: []string{envFile}},
+		expected: []KeyValuePair{
+			{Key: "max-scale", Value: "10"},
+			{Key: "min-scale", Value: "1"},
+		},
+	}, {
+		name:          "From an invalid env file",
+		sources:       KeyValueSources{FromEnvFile: []string{badEnvFile}},
+		expectedError: "Invalid value '" + badEnvFile + "' of --from-env-file: line 2: the pair needs to be in the format key=value",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := tt.sources.Pairs(tt.key, tt.value)
+			if tt.expectedError == "" {
+				testingUtil.AssertEqual(t, err, nil)
+				testingUtil.AssertDeepEqual(t, pairs, tt.expected)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
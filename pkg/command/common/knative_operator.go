@@ -20,7 +20,6 @@ import (
 
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/util/retry"
 	eventingv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
 	servingv1beta1 "knative.dev/operator/pkg/apis/operator/v1beta1"
 
@@ -46,27 +45,30 @@ func GetKnativeOperatorCR(p *pkg.OperatorParams) (*KnativeOperatorCR, error) {
 	}
 	return &KnativeOperatorCR{
 		KnativeOperatorClient: operatorClient,
+		ForceConflicts:        p.ForceConflicts,
 	}, nil
 }
 
 // KnativeOperatorCR is used to access the knative custom resource in the Kubernetes cluster.
 type KnativeOperatorCR struct {
-	KnativeOperatorClient *versioned.Clientset
+	KnativeOperatorClient versioned.Interface
+	// ForceConflicts takes over the fields managed by other field managers, when the spec is applied
+	ForceConflicts bool
 }
 
 // GetCRInterface gets the Knative custom resource under a certain namespace
-func (ko *KnativeOperatorCR) GetCRInterface(component, namespace string) (interface{}, error) {
+func (ko *KnativeOperatorCR) GetCRInterface(ctx context.Context, component, namespace string) (interface{}, error) {
 	if strings.EqualFold(component, ServingComponent) {
-		return ko.GetKnativeServing(namespace)
+		return ko.GetKnativeServing(ctx, namespace)
 	} else if strings.EqualFold(component, EventingComponent) {
-		return ko.GetKnativeEventing(namespace)
+		return ko.GetKnativeEventing(ctx, namespace)
 	}
 	return nil, fmt.Errorf("unknow component is set in --component or -c\n")
 }
 
 // GetKnativeServing gets the Knative Serving custom resource under a certain namespace
-func (ko *KnativeOperatorCR) GetKnativeServing(namespace string) (interface{}, error) {
-	knativeServing, err := ko.GetKnativeServingInCluster(namespace)
+func (ko *KnativeOperatorCR) GetKnativeServing(ctx context.Context, namespace string) (interface{}, error) {
+	knativeServing, err := ko.GetKnativeServingInCluster(ctx, namespace)
 
 	serving := &servingv1beta1.KnativeServing{
 		TypeMeta: metav1.TypeMeta{
@@ -89,16 +91,16 @@ func (ko *KnativeOperatorCR) GetKnativeServing(namespace string) (interface{}, e
 	return serving, nil
 }
 
-func (ko *KnativeOperatorCR) GetConfigMaps(component, namespace string) (base.ConfigMapData, error) {
+func (ko *KnativeOperatorCR) GetConfigMaps(ctx context.Context, component, namespace string) (base.ConfigMapData, error) {
 	var cmData base.ConfigMapData
 	if strings.EqualFold(component, ServingComponent) {
-		ks, err := ko.GetKnativeServingInCluster(namespace)
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
 		if err != nil {
 			return cmData, err
 		}
 		cmData = ks.Spec.Config
 	} else if strings.EqualFold(component, EventingComponent) {
-		ke, err := ko.GetKnativeEventingInCluster(namespace)
+		ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
 		if err != nil {
 			return cmData, err
 		}
@@ -108,16 +110,16 @@ func (ko *KnativeOperatorCR) GetConfigMaps(component, namespace string) (base.Co
 	return cmData, nil
 }
 
-func (ko *KnativeOperatorCR) GetRegistry(component, namespace string) (base.Registry, error) {
+func (ko *KnativeOperatorCR) GetRegistry(ctx context.Context, component, namespace string) (base.Registry, error) {
 	var registry base.Registry
 	if strings.EqualFold(component, ServingComponent) {
-		ks, err := ko.GetKnativeServingInCluster(namespace)
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
 		if err != nil {
 			return registry, err
 		}
 		registry = ks.Spec.Registry
 	} else if strings.EqualFold(component, EventingComponent) {
-		ke, err := ko.GetKnativeEventingInCluster(namespace)
+		ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
 		if err != nil {
 			return registry, err
 		}
@@ -127,34 +129,34 @@ func (ko *KnativeOperatorCR) GetRegistry(component, namespace string) (base.Regi
 	return registry, nil
 }
 
-func (ko *KnativeOperatorCR) UpdateRegistry(component, namespace string, registry base.Registry) error {
-	commonSpec, err := ko.GetCommonSpec(component, namespace)
+func (ko *KnativeOperatorCR) UpdateRegistry(ctx context.Context, component, namespace string, registry base.Registry) error {
+	commonSpec, err := ko.GetCommonSpec(ctx, component, namespace)
 	if err != nil {
 		return err
 	}
 	commonSpec.Registry = registry
-	return ko.UpdateCommonSpec(component, namespace, commonSpec)
+	return ko.UpdateCommonSpec(ctx, component, namespace, commonSpec)
 }
 
-func (ko *KnativeOperatorCR) UpdateConfigMaps(component, namespace string, cmData base.ConfigMapData) error {
-	commonSpec, err := ko.GetCommonSpec(component, namespace)
+func (ko *KnativeOperatorCR) UpdateConfigMaps(ctx context.Context, component, namespace string, cmData base.ConfigMapData) error {
+	commonSpec, err := ko.GetCommonSpec(ctx, component, namespace)
 	if err != nil {
 		return err
 	}
 	commonSpec.Config = cmData
-	return ko.UpdateCommonSpec(component, namespace, commonSpec)
+	return ko.UpdateCommonSpec(ctx, component, namespace, commonSpec)
 }
 
-func (ko *KnativeOperatorCR) GetDeployments(component, namespace string) ([]base.WorkloadOverride, error) {
+func (ko *KnativeOperatorCR) GetDeployments(ctx context.Context, component, namespace string) ([]base.WorkloadOverride, error) {
 	var workloadOverrides []base.WorkloadOverride
 	if strings.EqualFold(component, ServingComponent) {
-		ks, err := ko.GetKnativeServingInCluster(namespace)
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
 		if err != nil {
 			return workloadOverrides, err
 		}
 		workloadOverrides = ks.Spec.DeploymentOverride
 	} else if strings.EqualFold(component, EventingComponent) {
-		ke, err := ko.GetKnativeEventingInCluster(namespace)
+		ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
 		if err != nil {
 			return workloadOverrides, err
 		}
@@ -164,16 +166,16 @@ func (ko *KnativeOperatorCR) GetDeployments(component, namespace string) ([]base
 	return workloadOverrides, nil
 }
 
-func (ko *KnativeOperatorCR) GetServices(component, namespace string) ([]base.ServiceOverride, error) {
+func (ko *KnativeOperatorCR) GetServices(ctx context.Context, component, namespace string) ([]base.ServiceOverride, error) {
 	var serviceOverrides []base.ServiceOverride
 	if strings.EqualFold(component, ServingComponent) {
-		ks, err := ko.GetKnativeServingInCluster(namespace)
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
 		if err != nil {
 			return serviceOverrides, err
 		}
 		serviceOverrides = ks.Spec.ServiceOverride
 	} else if strings.EqualFold(component, EventingComponent) {
-		ke, err := ko.GetKnativeEventingInCluster(namespace)
+		ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
 		if err != nil {
 			return serviceOverrides, err
 		}
@@ -183,35 +185,35 @@ func (ko *KnativeOperatorCR) GetServices(component, namespace string) ([]base.Se
 	return serviceOverrides, nil
 }
 
-func (ko *KnativeOperatorCR) UpdateDeployments(component, namespace string, workloadOverrides []base.WorkloadOverride) error {
-	commonSpec, err := ko.GetCommonSpec(component, namespace)
+func (ko *KnativeOperatorCR) UpdateDeployments(ctx context.Context, component, namespace string, workloadOverrides []base.WorkloadOverride) error {
+	commonSpec, err := ko.GetCommonSpec(ctx, component, namespace)
 	if err != nil {
 		return err
 	}
 	commonSpec.DeploymentOverride = workloadOverrides
-	return ko.UpdateCommonSpec(component, namespace, commonSpec)
+	return ko.UpdateCommonSpec(ctx, component, namespace, commonSpec)
 }
 
-func (ko *KnativeOperatorCR) UpdateServices(component, namespace string, serviceOverrides []base.ServiceOverride) error {
-	commonSpec, err := ko.GetCommonSpec(component, namespace)
+func (ko *KnativeOperatorCR) UpdateServices(ctx context.Context, component, namespace string, serviceOverrides []base.ServiceOverride) error {
+	commonSpec, err := ko.GetCommonSpec(ctx, component, namespace)
 	if err != nil {
 		return err
 	}
 	commonSpec.ServiceOverride = serviceOverrides
-	return ko.UpdateCommonSpec(component, namespace, commonSpec)
+	return ko.UpdateCommonSpec(ctx, component, namespace, commonSpec)
 }
 
-func (ko *KnativeOperatorCR) GetCommonSpec(component, namespace string) (*base.CommonSpec, error) {
+func (ko *KnativeOperatorCR) GetCommonSpec(ctx context.Context, component, namespace string) (*base.CommonSpec, error) {
 	var commonSpec base.CommonSpec
 	if strings.EqualFold(component, ServingComponent) {
-		ks, err := ko.GetKnativeServingInCluster(namespace)
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
 		if err != nil {
 			return nil, err
 		}
 		commonSpec = ks.Spec.CommonSpec
 
 	} else if strings.EqualFold(component, EventingComponent) {
-		ke, err := ko.GetKnativeEventingInCluster(namespace)
+		ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
 		if err != nil {
 			return nil, err
 		}
@@ -221,60 +223,62 @@ func (ko *KnativeOperatorCR) GetCommonSpec(component, namespace string) (*base.C
 	return &commonSpec, nil
 }
 
-func (ko *KnativeOperatorCR) UpdateCommonSpec(component, namespace string, commonSpec *base.CommonSpec) error {
-	if strings.EqualFold(component, ServingComponent) {
-		ks, err := ko.GetKnativeServingInCluster(namespace)
-		if err != nil {
-			return err
-		}
-		ks.Spec.CommonSpec = *commonSpec
-		_, err = ko.UpdateKnativeServing(ks)
-		if err != nil {
-			return err
-		}
-
-	} else if strings.EqualFold(component, EventingComponent) {
-		ke, err := ko.GetKnativeEventingInCluster(namespace)
-		if err != nil {
-			return err
-		}
-		ke.Spec.CommonSpec = *commonSpec
-		_, err = ko.UpdateKnativeEventing(ke)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+// UpdateCommonSpec replaces the common part of the spec of the Knative custom resource with the server-side apply
+func (ko *KnativeOperatorCR) UpdateCommonSpec(ctx context.Context, component, namespace string, commonSpec *base.CommonSpec) error {
+	_, err := ko.MutateSpec(ctx, component, namespace, false, func(spec *KnativeSpec) error {
+		*spec.CommonSpec = *commonSpec
+		return nil
+	})
+	return err
 }
 
 // GetKnativeServingInCluster gets the Knative Serving custom resource in the cluster under a certain namespace
-func (ko *KnativeOperatorCR) GetKnativeServingInCluster(namespace string) (*servingv1beta1.KnativeServing, error) {
-	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeServings(namespace).Get(context.TODO(),
+func (ko *KnativeOperatorCR) GetKnativeServingInCluster(ctx context.Context, namespace string) (*servingv1beta1.KnativeServing, error) {
+	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeServings(namespace).Get(ctx,
 		KnativeServingName, metav1.GetOptions{})
 }
 
-// UpdateKnativeServing updates the Knative Serving custom resource in the cluster based on the provided Knative Serving
-func (ko *KnativeOperatorCR) UpdateKnativeServing(ks *servingv1beta1.KnativeServing) (*servingv1beta1.KnativeServing, error) {
-	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeServings(ks.Namespace).Update(context.TODO(), ks,
-		metav1.UpdateOptions{})
+// UpdateKnativeServing updates the labels, the annotations and the spec of the Knative Serving custom resource in
+// the cluster based on the provided Knative Serving, with the server-side apply
+func (ko *KnativeOperatorCR) UpdateKnativeServing(ctx context.Context, ks *servingv1beta1.KnativeServing) (*servingv1beta1.KnativeServing, error) {
+	_, err := ko.mutateKnativeServing(ctx, ks.Name, ks.Namespace, false, []SpecMutator{
+		ExpectResourceVersion(ks.ResourceVersion), func(spec *KnativeSpec) error {
+			spec.ObjectMeta.Labels, spec.ObjectMeta.Annotations = ks.Labels, ks.Annotations
+			*spec.Serving = *ks.Spec.DeepCopy()
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeServings(ks.Namespace).Get(ctx, ks.Name, metav1.GetOptions{})
 }
 
 // GetKnativeEventingInCluster gets the Knative Eventing custom resource in the cluster under a certain namespace
-func (ko *KnativeOperatorCR) GetKnativeEventingInCluster(namespace string) (*eventingv1beta1.KnativeEventing, error) {
-	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeEventings(namespace).Get(context.TODO(),
+func (ko *KnativeOperatorCR) GetKnativeEventingInCluster(ctx context.Context, namespace string) (*eventingv1beta1.KnativeEventing, error) {
+	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeEventings(namespace).Get(ctx,
 		KnativeEventingName, metav1.GetOptions{})
 }
 
-// UpdateKnativeEventing updates the Knative Eventing custom resource in the cluster based on the provided Knative Eventing
-func (ko *KnativeOperatorCR) UpdateKnativeEventing(ks *eventingv1beta1.KnativeEventing) (*eventingv1beta1.KnativeEventing, error) {
-	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeEventings(ks.Namespace).Update(context.TODO(), ks,
-		metav1.UpdateOptions{})
+// UpdateKnativeEventing updates the labels, the annotations and the spec of the Knative Eventing custom resource in
+// the cluster based on the provided Knative Eventing, with the server-side apply
+func (ko *KnativeOperatorCR) UpdateKnativeEventing(ctx context.Context, ke *eventingv1beta1.KnativeEventing) (*eventingv1beta1.KnativeEventing, error) {
+	_, err := ko.mutateKnativeEventing(ctx, ke.Name, ke.Namespace, false, []SpecMutator{
+		ExpectResourceVersion(ke.ResourceVersion), func(spec *KnativeSpec) error {
+			spec.ObjectMeta.Labels, spec.ObjectMeta.Annotations = ke.Labels, ke.Annotations
+			*spec.Eventing = *ke.Spec.DeepCopy()
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ko.KnativeOperatorClient.OperatorV1beta1().KnativeEventings(ke.Namespace).Get(ctx, ke.Name, metav1.GetOptions{})
 }
 
 // GetKnativeEventing gets the Knative Eventing custom resource under a certain namespace
-func (ko *KnativeOperatorCR) GetKnativeEventing(namespace string) (interface{}, error) {
-	knativeEventing, err := ko.GetKnativeEventingInCluster(namespace)
+func (ko *KnativeOperatorCR) GetKnativeEventing(ctx context.Context, namespace string) (interface{}, error) {
+	knativeEventing, err := ko.GetKnativeEventingInCluster(ctx, namespace)
 
 	eventing := &eventingv1beta1.KnativeEventing{
 		TypeMeta: metav1.TypeMeta{
@@ -298,13 +302,14 @@ func (ko *KnativeOperatorCR) GetKnativeEventing(namespace string) (interface{},
 }
 
 func GenerateOperatorCRString(component, namespace string, p *pkg.OperatorParams) (string, error) {
+	ctx := p.Context()
 	output := ""
 	ksCR, err := GetKnativeOperatorCR(p)
 	if err != nil {
 		return output, err
 	}
 
-	kCR, err := ksCR.GetCRInterface(component, namespace)
+	kCR, err := ksCR.GetCRInterface(ctx, component, namespace)
 	if err != nil {
 		return output, err
 	}
@@ -318,6 +323,7 @@ func GenerateOperatorCRString(component, namespace string, p *pkg.OperatorParams
 }
 
 func ApplyManifests(yamlTemplateString, overlayContent, yamlValuesContent string, p *pkg.OperatorParams) error {
+	ctx := p.Context()
 	restConfig, err := p.RestConfig()
 	if err != nil {
 		return err
@@ -330,17 +336,70 @@ func ApplyManifests(yamlTemplateString, overlayContent, yamlValuesContent string
 	}
 
 	manifest := Manifest{
-		YttPro:     &yttp,
-		RestConfig: restConfig,
+		YttPro:         &yttp,
+		RestConfig:     restConfig,
+		ForceConflicts: p.ForceConflicts,
 	}
 
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return manifest.Apply()
-	})
+	return manifest.Apply(ctx)
+}
 
+// DeleteManifests deletes the resources generated by the yaml template, the overlay and the values from
+// the Kubernetes cluster
+func DeleteManifests(yamlTemplateString, overlayContent, yamlValuesContent string, p *pkg.OperatorParams) error {
+	restConfig, err := p.RestConfig()
 	if err != nil {
 		return err
 	}
 
-	return nil
+	manifest := Manifest{
+		YttPro: &YttProcessor{
+			BaseData:    []byte(yamlTemplateString),
+			OverlayData: []byte(overlayContent),
+			ValuesData:  []byte(yamlValuesContent),
+		},
+		RestConfig: restConfig,
+	}
+
+	return manifest.Delete()
+}
+
+// GetVersion gets the version of the Knative component under a certain namespace, which is the version in the
+// status if it is reconciled, or the version in the spec otherwise
+func (ko *KnativeOperatorCR) GetVersion(ctx context.Context, component, namespace string) (string, error) {
+	if strings.EqualFold(component, ServingComponent) {
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
+		if err != nil {
+			return "", err
+		}
+		if ks.Status.Version != "" {
+			return ks.Status.Version, nil
+		}
+		return ks.Spec.Version, nil
+	}
+	ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
+	if err != nil {
+		return "", err
+	}
+	if ke.Status.Version != "" {
+		return ke.Status.Version, nil
+	}
+	return ke.Spec.Version, nil
+}
+
+// GetSpecVersion returns the version in the spec of the Knative custom resource under a certain namespace, which is
+// empty for the default version of the Knative Operator
+func (ko *KnativeOperatorCR) GetSpecVersion(ctx context.Context, component, namespace string) (string, error) {
+	if strings.EqualFold(component, ServingComponent) {
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
+		if err != nil {
+			return "", err
+		}
+		return ks.Spec.Version, nil
+	}
+	ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
+	if err != nil {
+		return "", err
+	}
+	return ke.Spec.Version, nil
 }
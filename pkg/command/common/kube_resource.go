@@ -44,8 +44,8 @@ type KubeResource struct {
 }
 
 // CreateOrUpdateConfigMap creates or updates the ConfigMap with the data under a certain namespace
-func (kr *KubeResource) CreateOrUpdateConfigMap(name, namespace, data string, overwrite bool) error {
-	cm, err := kr.getConfigMap(name, namespace)
+func (kr *KubeResource) CreateOrUpdateConfigMap(ctx context.Context, name, namespace, data string, overwrite bool) error {
+	cm, err := kr.getConfigMap(ctx, name, namespace)
 	if err != nil {
 		return err
 	}
@@ -69,7 +69,7 @@ func (kr *KubeResource) CreateOrUpdateConfigMap(name, namespace, data string, ov
 			Data: map[string]string{CustomDataKey: cmData},
 		}
 
-		if _, err := kr.KubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(),
+		if _, err := kr.KubeClient.CoreV1().ConfigMaps(namespace).Create(ctx,
 			configMap, metav1.CreateOptions{}); err != nil {
 			return err
 		}
@@ -80,7 +80,7 @@ func (kr *KubeResource) CreateOrUpdateConfigMap(name, namespace, data string, ov
 			cmData = appendCMData(existingData, data)
 		}
 		cm.Data = map[string]string{CustomDataKey: cmData}
-		if _, err := kr.KubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(),
+		if _, err := kr.KubeClient.CoreV1().ConfigMaps(namespace).Update(ctx,
 			cm, metav1.UpdateOptions{}); err != nil {
 			return err
 		}
@@ -89,8 +89,8 @@ func (kr *KubeResource) CreateOrUpdateConfigMap(name, namespace, data string, ov
 }
 
 // getConfigMap gets the ConfigMap under a certain namespace
-func (kr *KubeResource) getConfigMap(name, namespace string) (*v1.ConfigMap, error) {
-	cm, err := kr.KubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(),
+func (kr *KubeResource) getConfigMap(ctx context.Context, name, namespace string) (*v1.ConfigMap, error) {
+	cm, err := kr.KubeClient.CoreV1().ConfigMaps(namespace).Get(ctx,
 		name, metav1.GetOptions{})
 
 	if apierrs.IsNotFound(err) {
@@ -102,9 +102,33 @@ func (kr *KubeResource) getConfigMap(name, namespace string) (*v1.ConfigMap, err
 	return cm, nil
 }
 
+// ApplyConfigMap creates the ConfigMap, or replaces the data of the existing ConfigMap with the same name
+func (kr *KubeResource) ApplyConfigMap(ctx context.Context, configMap *v1.ConfigMap) error {
+	cm, err := kr.getConfigMap(ctx, configMap.Name, configMap.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if cm == nil {
+		_, err = kr.KubeClient.CoreV1().ConfigMaps(configMap.Namespace).Create(ctx,
+			configMap, metav1.CreateOptions{})
+		return err
+	}
+
+	cm.Data = configMap.Data
+	_, err = kr.KubeClient.CoreV1().ConfigMaps(configMap.Namespace).Update(ctx,
+		cm, metav1.UpdateOptions{})
+	return err
+}
+
+// GetConfigMap gets the ConfigMap under a certain namespace. It returns nil if the ConfigMap does not exist.
+func (kr *KubeResource) GetConfigMap(ctx context.Context, name, namespace string) (*v1.ConfigMap, error) {
+	return kr.getConfigMap(ctx, name, namespace)
+}
+
 // UpdateOperatorDeployment updates the deployment of the operator
-func (kr *KubeResource) UpdateOperatorDeployment(name, namespace string) error {
-	deploy, err := kr.getDeployment(name, namespace)
+func (kr *KubeResource) UpdateOperatorDeployment(ctx context.Context, name, namespace string) error {
+	deploy, err := kr.getDeployment(ctx, name, namespace)
 	if err != nil {
 		return err
 	}
@@ -114,7 +138,7 @@ func (kr *KubeResource) UpdateOperatorDeployment(name, namespace string) error {
 
 	deploy.Spec.Template.Spec.Volumes = updateVolumes(deploy.Spec.Template.Spec.Volumes)
 	deploy.Spec.Template.Spec.Containers = updateContainers(deploy.Spec.Template.Spec.Containers)
-	if _, err := kr.KubeClient.AppsV1().Deployments(namespace).Update(context.TODO(),
+	if _, err := kr.KubeClient.AppsV1().Deployments(namespace).Update(ctx,
 		deploy, metav1.UpdateOptions{}); err != nil {
 		return err
 	}
@@ -123,8 +147,8 @@ func (kr *KubeResource) UpdateOperatorDeployment(name, namespace string) error {
 }
 
 // getDeployment gets the deployment under a certain namespace
-func (kr *KubeResource) getDeployment(name, namespace string) (*appsv1.Deployment, error) {
-	deploy, err := kr.KubeClient.AppsV1().Deployments(namespace).Get(context.TODO(),
+func (kr *KubeResource) getDeployment(ctx context.Context, name, namespace string) (*appsv1.Deployment, error) {
+	deploy, err := kr.KubeClient.AppsV1().Deployments(namespace).Get(ctx,
 		name, metav1.GetOptions{})
 	if apierrs.IsNotFound(err) {
 		return nil, nil
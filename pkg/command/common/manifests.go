@@ -17,38 +17,118 @@ limitations under the License.
 package common
 
 import (
+	"context"
+	"sort"
+	"strings"
+
 	mfc "github.com/manifestival/client-go-client"
+	mf "github.com/manifestival/manifestival"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 )
 
+// installationOrder lists the kinds of the resources in the order, in which they need to be installed.
+// The kinds not in the list are installed after the bindings and before the workloads.
+var installationOrder = map[string]int{
+	"CustomResourceDefinition":       0,
+	"ServiceAccount":                 1,
+	"ClusterRole":                    2,
+	"Role":                           2,
+	"ClusterRoleBinding":             3,
+	"RoleBinding":                    3,
+	"Deployment":                     5,
+	"Job":                            5,
+	"MutatingWebhookConfiguration":   6,
+	"ValidatingWebhookConfiguration": 6,
+}
+
 // Manifest applies the content of the yaml file against a Kubernetes cluster
 type Manifest struct {
 	// YttPro is an instance of the YttProcessor to generate the output yaml
 	YttPro *YttProcessor
 	// RestConfig is the rest configuration to access the Kubernetes cluster
 	RestConfig *rest.Config
+	// ForceConflicts takes over the fields managed by other field managers, when the resources are applied
+	ForceConflicts bool
 }
 
-// Apply applies the content of the yaml file against the Kubernetes cluster
-func (man *Manifest) Apply() error {
-	content, err := man.YttPro.GenerateOutput()
+// Apply applies the content of the yaml file against the Kubernetes cluster with the server-side apply,
+// in the order of the installation
+func (man *Manifest) Apply(ctx context.Context) error {
+	manifest, err := man.parse()
 	if err != nil {
 		return err
 	}
-	path := "tempFile.yaml"
-	defer DeleteFile(path)
 
-	if err = WriteFile(path, content); err != nil {
+	dynamicClient, err := dynamic.NewForConfig(man.RestConfig)
+	if err != nil {
 		return err
 	}
-	manifest, err := mfc.NewManifest(path, man.RestConfig)
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(man.RestConfig)
 	if err != nil {
 		return err
 	}
 
-	if err = manifest.Apply(); err != nil {
+	applier := serverSideApplier{
+		dynamicClient:   dynamicClient,
+		discoveryClient: discoveryClient,
+		force:           man.ForceConflicts,
+	}
+	for _, resource := range sortByInstallationOrder(manifest.Resources()) {
+		if err := applier.apply(ctx, &resource); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse parses the generated content of the yaml file in memory
+func (man *Manifest) parse() (mf.Manifest, error) {
+	content, err := man.YttPro.GenerateOutput()
+	if err != nil {
+		return mf.Manifest{}, err
+	}
+
+	client, err := mfc.NewClient(man.RestConfig)
+	if err != nil {
+		return mf.Manifest{}, err
+	}
+	return mf.ManifestFrom(mf.Reader(strings.NewReader(content)), mf.UseClient(client))
+}
+
+// Delete deletes the resources in the content of the yaml file from the Kubernetes cluster, in the reverse
+// order of the installation. Namespaces are kept.
+func (man *Manifest) Delete() error {
+	manifest, err := man.parse()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	resources := manifest.Filter(mf.Not(mf.ByKind("Namespace"))).Resources()
+	manifest, err = mf.ManifestFrom(mf.Slice(sortByInstallationOrder(resources)), mf.UseClient(manifest.Client))
+	if err != nil {
+		return err
+	}
+
+	// Manifestival deletes the resources in the reverse order of the manifest
+	return manifest.Delete()
+}
+
+// sortByInstallationOrder sorts the resources by the order of their kinds to be installed
+func sortByInstallationOrder(resources []unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, len(resources))
+	copy(result, resources)
+	sort.SliceStable(result, func(i, j int) bool {
+		return installationPriority(result[i].GetKind()) < installationPriority(result[j].GetKind())
+	})
+	return result
+}
+
+func installationPriority(kind string) int {
+	if priority, ok := installationOrder[kind]; ok {
+		return priority
+	}
+	return 4
 }
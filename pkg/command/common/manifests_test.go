@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestSortByInstallationOrder(t *testing.T) {
+	resources := []unstructured.Unstructured{
+		newResource("ValidatingWebhookConfiguration", "validation.webhook.operator.knative.dev"),
+		newResource("Deployment", "knative-operator"),
+		newResource("ClusterRoleBinding", "knative-serving-operator"),
+		newResource("ConfigMap", "config-logging"),
+		newResource("ClusterRole", "knative-serving-operator"),
+		newResource("ServiceAccount", "knative-operator"),
+		newResource("CustomResourceDefinition", "knativeservings.operator.knative.dev"),
+		newResource("Deployment", "operator-webhook"),
+		newResource("CustomResourceDefinition", "knativeeventings.operator.knative.dev"),
+	}
+
+	result := sortByInstallationOrder(resources)
+	var names []string
+	for _, resource := range result {
+		names = append(names, resource.GetKind()+"/"+resource.GetName())
+	}
+	testingUtil.AssertDeepEqual(t, names, []string{
+		"CustomResourceDefinition/knativeservings.operator.knative.dev",
+		"CustomResourceDefinition/knativeeventings.operator.knative.dev",
+		"ServiceAccount/knative-operator",
+		"ClusterRole/knative-serving-operator",
+		"ClusterRoleBinding/knative-serving-operator",
+		"ConfigMap/config-logging",
+		"Deployment/knative-operator",
+		"Deployment/operator-webhook",
+		"ValidatingWebhookConfiguration/validation.webhook.operator.knative.dev",
+	})
+	// The input is not changed
+	testingUtil.AssertEqual(t, resources[0].GetKind(), "ValidatingWebhookConfiguration")
+}
+
+func TestManifestParse(t *testing.T) {
+	manifest := Manifest{
+		YttPro: &YttProcessor{
+			BaseData: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-logging
+  namespace: knative-operator
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: knative-operator
+  namespace: knative-operator`),
+		},
+		RestConfig: &rest.Config{Host: "https://localhost:6443"},
+	}
+
+	result, err := manifest.parse()
+	testingUtil.AssertEqual(t, err, nil)
+	var names []string
+	for _, resource := range result.Resources() {
+		names = append(names, resource.GetKind()+"/"+resource.GetName())
+	}
+	testingUtil.AssertDeepEqual(t, names, []string{"ConfigMap/config-logging", "ServiceAccount/knative-operator"})
+
+	// Nothing is written into the working directory
+	_, err = os.Stat("tempFile.yaml")
+	testingUtil.AssertEqual(t, os.IsNotExist(err), true)
+}
+
+func newResource(kind, name string) unstructured.Unstructured {
+	resource := unstructured.Unstructured{}
+	resource.SetKind(kind)
+	resource.SetName(name)
+	return resource
+}
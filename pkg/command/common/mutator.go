@@ -0,0 +1,359 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/pkg/kmp"
+)
+
+// KnativeSpec is the spec of a Knative custom resource to be changed by the mutators. The embedded CommonSpec
+// points to the common part of the spec. Only one of Serving and Eventing is set, depending on the component.
+// ObjectMeta points to the metadata of the custom resource, of which only the labels and the annotations are saved.
+// It is nil, if the spec is not read from the cluster. StatusVersion is the version reported in the status.
+type KnativeSpec struct {
+	*base.CommonSpec
+	Serving       *v1beta1.KnativeServingSpec
+	Eventing      *v1beta1.KnativeEventingSpec
+	ObjectMeta    *metav1.ObjectMeta
+	StatusVersion string
+}
+
+// NewKnativeServingSpec returns the KnativeSpec to change the spec of Knative Serving
+func NewKnativeServingSpec(spec *v1beta1.KnativeServingSpec) *KnativeSpec {
+	return &KnativeSpec{
+		CommonSpec: &spec.CommonSpec,
+		Serving:    spec,
+	}
+}
+
+// NewKnativeEventingSpec returns the KnativeSpec to change the spec of Knative Eventing
+func NewKnativeEventingSpec(spec *v1beta1.KnativeEventingSpec) *KnativeSpec {
+	return &KnativeSpec{
+		CommonSpec: &spec.CommonSpec,
+		Eventing:   spec,
+	}
+}
+
+// SpecMutator changes the spec of a Knative custom resource
+type SpecMutator func(spec *KnativeSpec) error
+
+// content returns the spec of Knative Serving or Knative Eventing
+func (spec *KnativeSpec) content() interface{} {
+	if spec.Eventing != nil {
+		return spec.Eventing
+	}
+	return spec.Serving
+}
+
+// Mutate applies the mutators to the spec in order, and stops at the first error
+func (spec *KnativeSpec) Mutate(mutators ...SpecMutator) error {
+	for _, mutator := range mutators {
+		if err := mutator(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MutateSpec applies the mutators to the spec of the Knative custom resource under a certain namespace, and
+// saves the change with the server-side apply under the field manager of the plugin. If the custom resource does not
+// exist, it is created when create is true, otherwise the NotFound error is returned. It returns the spec before
+// the change, or nil if the custom resource is created or not changed.
+func (ko *KnativeOperatorCR) MutateSpec(ctx context.Context, component, namespace string, create bool, mutators ...SpecMutator) (interface{}, error) {
+	if strings.EqualFold(component, ServingComponent) {
+		return ko.MutateSpecByName(ctx, component, KnativeServingName, namespace, create, mutators...)
+	} else if strings.EqualFold(component, EventingComponent) {
+		return ko.MutateSpecByName(ctx, component, KnativeEventingName, namespace, create, mutators...)
+	}
+	return nil, fmt.Errorf("unknow component is set in --component or -c\n")
+}
+
+// MutateSpecByName works as MutateSpec, for the Knative custom resource with a certain name
+func (ko *KnativeOperatorCR) MutateSpecByName(ctx context.Context, component, name, namespace string, create bool, mutators ...SpecMutator) (interface{}, error) {
+	if strings.EqualFold(component, ServingComponent) {
+		return ko.mutateKnativeServing(ctx, name, namespace, create, mutators)
+	} else if strings.EqualFold(component, EventingComponent) {
+		return ko.mutateKnativeEventing(ctx, name, namespace, create, mutators)
+	}
+	return nil, fmt.Errorf("unknow component is set in --component or -c\n")
+}
+
+func (ko *KnativeOperatorCR) mutateKnativeServing(ctx context.Context, name, namespace string, create bool, mutators []SpecMutator) (interface{}, error) {
+	client := ko.KnativeOperatorClient.OperatorV1beta1().KnativeServings(namespace)
+	ks, err := client.Get(ctx, name, metav1.GetOptions{})
+	exists := err == nil
+	if apierrs.IsNotFound(err) && create {
+		ks = &v1beta1.KnativeServing{}
+		ks.Name, ks.Namespace = name, namespace
+	} else if err != nil {
+		return nil, err
+	}
+
+	mutated := ks.DeepCopy()
+	spec := NewKnativeServingSpec(&mutated.Spec)
+	spec.ObjectMeta, spec.StatusVersion = &mutated.ObjectMeta, ks.Status.Version
+	if err = spec.Mutate(mutators...); err != nil {
+		return nil, err
+	}
+	changed, err := ko.applyKnativeCR(ctx, "KnativeServing", exists, ks, mutated,
+		func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return client.Patch(ctx, name, pt, data, opts)
+		})
+	if err != nil || !changed || !exists {
+		return nil, err
+	}
+	return &ks.Spec, nil
+}
+
+func (ko *KnativeOperatorCR) mutateKnativeEventing(ctx context.Context, name, namespace string, create bool, mutators []SpecMutator) (interface{}, error) {
+	client := ko.KnativeOperatorClient.OperatorV1beta1().KnativeEventings(namespace)
+	ke, err := client.Get(ctx, name, metav1.GetOptions{})
+	exists := err == nil
+	if apierrs.IsNotFound(err) && create {
+		ke = &v1beta1.KnativeEventing{}
+		ke.Name, ke.Namespace = name, namespace
+	} else if err != nil {
+		return nil, err
+	}
+
+	mutated := ke.DeepCopy()
+	spec := NewKnativeEventingSpec(&mutated.Spec)
+	spec.ObjectMeta, spec.StatusVersion = &mutated.ObjectMeta, ke.Status.Version
+	if err = spec.Mutate(mutators...); err != nil {
+		return nil, err
+	}
+	changed, err := ko.applyKnativeCR(ctx, "KnativeEventing", exists, ke, mutated,
+		func(ctx context.Context, pt types.PatchType, data []byte, opts metav1.PatchOptions) (metav1.Object, error) {
+			return client.Patch(ctx, name, pt, data, opts)
+		})
+	if err != nil || !changed || !exists {
+		return nil, err
+	}
+	return &ke.Spec, nil
+}
+
+// patchOptions returns the options of the server-side apply requests
+func (ko *KnativeOperatorCR) patchOptions() metav1.PatchOptions {
+	force := ko.ForceConflicts
+	return metav1.PatchOptions{
+		FieldManager: FieldManager,
+		Force:        &force,
+	}
+}
+
+// DiffSpec returns the difference made by the mutators to the spec of the Knative custom resource under a certain
+// namespace, without saving it, together with the resource version the difference is made on. The spec of a missing
+// custom resource is empty, and its resource version is empty. The difference is empty, if the mutators change nothing.
+func (ko *KnativeOperatorCR) DiffSpec(ctx context.Context, component, namespace string, mutators ...SpecMutator) (string, string, error) {
+	if strings.EqualFold(component, ServingComponent) {
+		current, resourceVersion := &v1beta1.KnativeServingSpec{}, ""
+		if ks, err := ko.GetKnativeServingInCluster(ctx, namespace); err == nil {
+			current, resourceVersion = &ks.Spec, ks.ResourceVersion
+		} else if !apierrs.IsNotFound(err) {
+			return "", "", err
+		}
+		spec := current.DeepCopy()
+		if err := NewKnativeServingSpec(spec).Mutate(mutators...); err != nil {
+			return "", "", err
+		}
+		diff, err := diffSpecs(current, spec)
+		return diff, resourceVersion, err
+	} else if strings.EqualFold(component, EventingComponent) {
+		current, resourceVersion := &v1beta1.KnativeEventingSpec{}, ""
+		if ke, err := ko.GetKnativeEventingInCluster(ctx, namespace); err == nil {
+			current, resourceVersion = &ke.Spec, ke.ResourceVersion
+		} else if !apierrs.IsNotFound(err) {
+			return "", "", err
+		}
+		spec := current.DeepCopy()
+		if err := NewKnativeEventingSpec(spec).Mutate(mutators...); err != nil {
+			return "", "", err
+		}
+		diff, err := diffSpecs(current, spec)
+		return diff, resourceVersion, err
+	}
+	return "", "", fmt.Errorf("unknow component is set in --component or -c\n")
+}
+
+func diffSpecs(current, spec interface{}) (string, error) {
+	if sameSpec(current, spec) {
+		return "", nil
+	}
+	return kmp.SafeDiff(current, spec)
+}
+
+// DiffKnativeCR returns the difference made by the mutators to the Knative custom resource, without saving it,
+// together with the resource version the difference is made on
+func DiffKnativeCR(component, namespace string, p *pkg.OperatorParams, mutators ...SpecMutator) (string, string, error) {
+	ksCR, err := GetKnativeOperatorCR(p)
+	if err != nil {
+		return "", "", err
+	}
+	return ksCR.DiffSpec(p.Context(), component, namespace, mutators...)
+}
+
+// ApplyKnativeCR applies the mutators to the Knative custom resource, then records the spec before the change
+// as a revision. The version and the spec before the change are kept in the annotations for the rollback as well.
+// The custom resource is created if it does not exist.
+func ApplyKnativeCR(component, namespace string, p *pkg.OperatorParams, mutators ...SpecMutator) error {
+	return mutateKnativeCR(component, namespace, true, p, mutators)
+}
+
+// UpdateKnativeCR applies the mutators to the Knative custom resource, then records the spec before the change
+// as a revision. The custom resource needs to exist.
+func UpdateKnativeCR(component, namespace string, p *pkg.OperatorParams, mutators ...SpecMutator) error {
+	return mutateKnativeCR(component, namespace, false, p, mutators)
+}
+
+func mutateKnativeCR(component, namespace string, create bool, p *pkg.OperatorParams, mutators []SpecMutator) error {
+	p.SetStep(fmt.Sprintf("Applying the custom resource of Knative %s in the namespace '%s'...", component, namespace))
+	ksCR, err := GetKnativeOperatorCR(p)
+	if err != nil {
+		return err
+	}
+
+	// The mutators are applied again to the custom resource read again, if it was changed in the meantime
+	var previous interface{}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		previous, err = ksCR.MutateSpec(p.Context(), component, namespace, create, recordPreviousState(mutators))
+		return err
+	})
+	if err != nil || previous == nil {
+		return err
+	}
+
+	// The revision is only recorded once the change is saved, so that a failed change cannot be undone
+	if err = RecordSpec(component, namespace, previous, p); err != nil {
+		p.Warn("The change of Knative %s in the namespace '%s' is not recorded in the history: %v", component, namespace, err)
+	}
+	return nil
+}
+
+// ReplaceSpec replaces the spec of the Knative custom resource with the spec in JSON. The version is kept unchanged.
+func ReplaceSpec(data []byte) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		version := spec.Version
+		if spec.Serving != nil {
+			*spec.Serving = v1beta1.KnativeServingSpec{}
+			if err := json.Unmarshal(data, spec.Serving); err != nil {
+				return err
+			}
+		} else if spec.Eventing != nil {
+			*spec.Eventing = v1beta1.KnativeEventingSpec{}
+			if err := json.Unmarshal(data, spec.Eventing); err != nil {
+				return err
+			}
+		}
+		spec.Version = version
+		return nil
+	}
+}
+
+// SetVersion sets the version of the Knative component
+func SetVersion(version string) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		spec.Version = version
+		return nil
+	}
+}
+
+// ErrResourceVersionChanged is returned, when the custom resource is not at the expected resource version any more.
+// It is not retried like a conflict, because the mutators only apply a change computed for the expected version.
+var ErrResourceVersionChanged = errors.New("the object has been modified since it was read")
+
+// ExpectResourceVersion fails with ErrResourceVersionChanged, if the custom resource is not at the resource version
+// any more. Nothing is checked for an empty resource version.
+func ExpectResourceVersion(resourceVersion string) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		if resourceVersion == "" || spec.ObjectMeta == nil || spec.ObjectMeta.ResourceVersion == resourceVersion {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is at the resource version %s instead of %s", ErrResourceVersionChanged,
+			spec.ObjectMeta.Name, spec.ObjectMeta.ResourceVersion, resourceVersion)
+	}
+}
+
+// SetConfigMapData sets the value of the key in the ConfigMap
+func SetConfigMapData(cmName, key, value string) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		if spec.Config == nil {
+			spec.Config = base.ConfigMapData{}
+		}
+		if spec.Config[cmName] == nil {
+			spec.Config[cmName] = map[string]string{}
+		}
+		spec.Config[cmName][key] = value
+		return nil
+	}
+}
+
+// UpdateDeploymentOverride changes the override of the deployment. The override is added if it does not exist.
+func UpdateDeploymentOverride(name string, update func(override *base.WorkloadOverride) error) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		for i := range spec.DeploymentOverride {
+			if spec.DeploymentOverride[i].Name == name {
+				return update(&spec.DeploymentOverride[i])
+			}
+		}
+		override := base.WorkloadOverride{Name: name}
+		if err := update(&override); err != nil {
+			return err
+		}
+		spec.DeploymentOverride = append(spec.DeploymentOverride, override)
+		return nil
+	}
+}
+
+// UpdateServiceOverride changes the override of the service. The override is added if it does not exist.
+func UpdateServiceOverride(name string, update func(override *base.ServiceOverride) error) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		for i := range spec.ServiceOverride {
+			if spec.ServiceOverride[i].Name == name {
+				return update(&spec.ServiceOverride[i])
+			}
+		}
+		override := base.ServiceOverride{Name: name}
+		if err := update(&override); err != nil {
+			return err
+		}
+		spec.ServiceOverride = append(spec.ServiceOverride, override)
+		return nil
+	}
+}
+
+// SetMapValue returns the map with the value set for the key. A new map is created if the map is nil.
+func SetMapValue(data map[string]string, key, value string) map[string]string {
+	if data == nil {
+		data = map[string]string{}
+	}
+	data[key] = value
+	return data
+}
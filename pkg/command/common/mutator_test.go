@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestSetConfigMapData(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		input          base.ConfigMapData
+		cmName         string
+		key            string
+		value          string
+		expectedResult base.ConfigMapData
+	}{{
+		name:           "No existing ConfigMaps",
+		input:          nil,
+		cmName:         "network",
+		key:            "ingress-class",
+		value:          "kourier.ingress.networking.knative.dev",
+		expectedResult: base.ConfigMapData{"network": {"ingress-class": "kourier.ingress.networking.knative.dev"}},
+	}, {
+		name: "Existing keys are kept",
+		input: base.ConfigMapData{
+			"network":    {"ingress-class": "istio.ingress.networking.knative.dev", "domain-template": "{{.Name}}"},
+			"deployment": {"progressDeadline": "600s"},
+		},
+		cmName: "network",
+		key:    "ingress-class",
+		value:  "'quoted' value: with #special {characters}",
+		expectedResult: base.ConfigMapData{
+			"network":    {"ingress-class": "'quoted' value: with #special {characters}", "domain-template": "{{.Name}}"},
+			"deployment": {"progressDeadline": "600s"},
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1beta1.KnativeServingSpec{}
+			spec.Config = tt.input
+			err := NewKnativeServingSpec(&spec).Mutate(SetConfigMapData(tt.cmName, tt.key, tt.value))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.Config, tt.expectedResult)
+		})
+	}
+}
+
+func TestUpdateDeploymentOverride(t *testing.T) {
+	setLabel := func(override *base.WorkloadOverride) error {
+		override.Labels = SetMapValue(override.Labels, "key", "value")
+		return nil
+	}
+
+	spec := v1beta1.KnativeEventingSpec{}
+	spec.DeploymentOverride = []base.WorkloadOverride{{Name: "eventing-controller"}}
+	err := NewKnativeEventingSpec(&spec).Mutate(UpdateDeploymentOverride("eventing-controller", setLabel),
+		UpdateDeploymentOverride("eventing-webhook", setLabel))
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, []base.WorkloadOverride{
+		{Name: "eventing-controller", Labels: map[string]string{"key": "value"}},
+		{Name: "eventing-webhook", Labels: map[string]string{"key": "value"}},
+	})
+}
+
+func TestUpdateServiceOverride(t *testing.T) {
+	spec := v1beta1.KnativeServingSpec{}
+	err := NewKnativeServingSpec(&spec).Mutate(UpdateServiceOverride("activator-service", func(override *base.ServiceOverride) error {
+		return fmt.Errorf("test error")
+	}))
+	testingUtil.AssertEqual(t, err.Error(), "test error")
+	testingUtil.AssertEqual(t, len(spec.ServiceOverride), 0)
+
+	err = NewKnativeServingSpec(&spec).Mutate(UpdateServiceOverride("activator-service", func(override *base.ServiceOverride) error {
+		override.Selector = SetMapValue(override.Selector, "app", "activator")
+		return nil
+	}))
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertDeepEqual(t, spec.ServiceOverride, []base.ServiceOverride{
+		{Name: "activator-service", Selector: map[string]string{"app": "activator"}},
+	})
+}
+
+func TestMutateStopsAtFirstError(t *testing.T) {
+	spec := v1beta1.KnativeServingSpec{}
+	err := NewKnativeServingSpec(&spec).Mutate(
+		func(spec *KnativeSpec) error {
+			return fmt.Errorf("test error")
+		},
+		SetConfigMapData("network", "ingress-class", "kourier.ingress.networking.knative.dev"))
+	testingUtil.AssertEqual(t, err.Error(), "test error")
+	testingUtil.AssertDeepEqual(t, spec.Config, base.ConfigMapData(nil))
+}
+
+func TestReplaceSpec(t *testing.T) {
+	spec := v1beta1.KnativeEventingSpec{}
+	spec.Version = "1.8"
+	spec.Config = base.ConfigMapData{"logging": {"loglevel.controller": "debug"}}
+
+	knativeSpec := NewKnativeEventingSpec(&spec)
+	err := knativeSpec.Mutate(ReplaceSpec([]byte(`{"version":"1.7","registry":{"default":"example.com/knative"}}`)))
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, spec.Version, "1.8")
+	testingUtil.AssertEqual(t, spec.Registry.Default, "example.com/knative")
+	testingUtil.AssertDeepEqual(t, spec.Config, base.ConfigMapData(nil))
+
+	err = knativeSpec.Mutate(SetVersion("1.9"))
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, spec.Version, "1.9")
+}
+
+func TestExpectResourceVersion(t *testing.T) {
+	ks := v1beta1.KnativeServing{}
+	ks.Name, ks.ResourceVersion = "knative-serving", "10"
+	spec := NewKnativeServingSpec(&ks.Spec)
+	spec.ObjectMeta = &ks.ObjectMeta
+
+	testingUtil.AssertEqual(t, spec.Mutate(ExpectResourceVersion("10")), nil)
+	testingUtil.AssertEqual(t, spec.Mutate(ExpectResourceVersion("")), nil)
+	err := spec.Mutate(ExpectResourceVersion("9"))
+	testingUtil.AssertEqual(t, errors.Is(err, ErrResourceVersionChanged), true)
+	// The change is not retried like a conflict
+	testingUtil.AssertEqual(t, apierrs.IsConflict(err), false)
+}
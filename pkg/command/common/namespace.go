@@ -33,17 +33,17 @@ type Namespace struct {
 }
 
 // CreateNamespace creates the namespace if it is not available in the Kubernetes cluster
-func (ns *Namespace) CreateNamespace(namespace string) error {
+func (ns *Namespace) CreateNamespace(ctx context.Context, namespace string) error {
 	if namespace != "default" {
 		// Create the namespace if it is not available
-		_, err := ns.Client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		_, err := ns.Client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
 		if apierrors.IsNotFound(err) {
 			nspace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
 			if strings.EqualFold(ns.Component, ServingComponent) {
 				nspace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace,
 					Labels: map[string]string{"istio-injection": "enabled"}}}
 			}
-			ns.Client.CoreV1().Namespaces().Create(context.TODO(), nspace, metav1.CreateOptions{})
+			ns.Client.CoreV1().Namespaces().Create(ctx, nspace, metav1.CreateOptions{})
 		} else if err != nil {
 			return err
 		}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// PreviousVersionAnnotation records the version of the Knative component before the plugin changed it
+	PreviousVersionAnnotation = "operator.knative.dev/kn-previous-version"
+	// PreviousSpecAnnotation records the spec of the Knative custom resource before the plugin changed it
+	PreviousSpecAnnotation = "operator.knative.dev/kn-previous-spec"
+)
+
+// SavePreviousState records the current version and spec of the Knative custom resource in its annotations,
+// so that the change applied afterwards can be rolled back. Nothing is recorded if the custom resource does not exist.
+func (ko *KnativeOperatorCR) SavePreviousState(ctx context.Context, component, namespace string) error {
+	_, err := ko.MutateSpec(ctx, component, namespace, false, savePreviousState)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// savePreviousState sets the annotations of the version and the spec before the change
+func savePreviousState(spec *KnativeSpec) error {
+	annotations, err := previousStateAnnotations(spec.ObjectMeta.GetAnnotations(),
+		installedVersion(spec.StatusVersion, spec.Version), spec.content())
+	if err != nil {
+		return err
+	}
+	spec.ObjectMeta.SetAnnotations(annotations)
+	return nil
+}
+
+// recordPreviousState applies the mutators, and sets the annotations of the version and the spec before them, if they
+// change the spec. Nothing is recorded for a custom resource, which is not read from the cluster.
+func recordPreviousState(mutators []SpecMutator) SpecMutator {
+	return func(spec *KnativeSpec) error {
+		version := installedVersion(spec.StatusVersion, spec.Version)
+		previous, err := json.Marshal(spec.content())
+		if err != nil {
+			return err
+		}
+		if err = spec.Mutate(mutators...); err != nil {
+			return err
+		}
+		if spec.ObjectMeta == nil || spec.ObjectMeta.ResourceVersion == "" {
+			return nil
+		}
+		current, err := json.Marshal(spec.content())
+		if err != nil || string(previous) == string(current) {
+			return err
+		}
+
+		annotations, err := previousStateAnnotations(spec.ObjectMeta.GetAnnotations(), version, json.RawMessage(previous))
+		if err != nil {
+			return err
+		}
+		spec.ObjectMeta.SetAnnotations(annotations)
+		return nil
+	}
+}
+
+// GetPreviousState returns the version and the spec in JSON recorded by SavePreviousState
+func (ko *KnativeOperatorCR) GetPreviousState(ctx context.Context, component, namespace string) (string, string, error) {
+	var annotations map[string]string
+	if strings.EqualFold(component, ServingComponent) {
+		ks, err := ko.GetKnativeServingInCluster(ctx, namespace)
+		if err != nil {
+			return "", "", err
+		}
+		annotations = ks.GetAnnotations()
+	} else if strings.EqualFold(component, EventingComponent) {
+		ke, err := ko.GetKnativeEventingInCluster(ctx, namespace)
+		if err != nil {
+			return "", "", err
+		}
+		annotations = ke.GetAnnotations()
+	} else {
+		return "", "", fmt.Errorf("unknow component is set in --component or -c\n")
+	}
+
+	return annotations[PreviousVersionAnnotation], annotations[PreviousSpecAnnotation], nil
+}
+
+// installedVersion returns the version reported in the status, or the version in the spec if the status has none
+func installedVersion(statusVersion, specVersion string) string {
+	if statusVersion != "" {
+		return statusVersion
+	}
+	return specVersion
+}
+
+func previousStateAnnotations(annotations map[string]string, version string, spec interface{}) (map[string]string, error) {
+	specData, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(annotations)+2)
+	for key, value := range annotations {
+		result[key] = value
+	}
+	result[PreviousVersionAnnotation] = version
+	result[PreviousSpecAnnotation] = string(specData)
+	return result, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestInstalledVersion(t *testing.T) {
+	testingUtil.AssertEqual(t, installedVersion("1.5.0", "1.5"), "1.5.0")
+	testingUtil.AssertEqual(t, installedVersion("", "1.5"), "1.5")
+	testingUtil.AssertEqual(t, installedVersion("", ""), "")
+}
+
+func TestPreviousStateAnnotations(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		annotations    map[string]string
+		version        string
+		spec           interface{}
+		expectedResult map[string]string
+	}{{
+		name:        "No existing annotations",
+		annotations: nil,
+		version:     "1.5.0",
+		spec: v1beta1.KnativeServingSpec{
+			CommonSpec: base.CommonSpec{
+				Version: "1.5",
+			},
+		},
+		expectedResult: map[string]string{
+			PreviousVersionAnnotation: "1.5.0",
+			PreviousSpecAnnotation:    `{"registry":{},"version":"1.5","controller-custom-certs":{"type":"","name":""}}`,
+		},
+	}, {
+		name: "Existing annotations are kept and previous state is overwritten",
+		annotations: map[string]string{
+			"test-key":                "test-value",
+			PreviousVersionAnnotation: "1.4.0",
+			PreviousSpecAnnotation:    `{"version":"1.4"}`,
+		},
+		version: "1.5.0",
+		spec: v1beta1.KnativeEventingSpec{
+			CommonSpec: base.CommonSpec{
+				Version: "1.5",
+			},
+		},
+		expectedResult: map[string]string{
+			"test-key":                "test-value",
+			PreviousVersionAnnotation: "1.5.0",
+			PreviousSpecAnnotation:    `{"registry":{},"version":"1.5"}`,
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := previousStateAnnotations(tt.annotations, tt.version, tt.spec)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, result, tt.expectedResult)
+		})
+	}
+}
+
+func TestRecordPreviousState(t *testing.T) {
+	for _, tt := range []struct {
+		name                string
+		resourceVersion     string
+		mutator             SpecMutator
+		expectedAnnotations map[string]string
+	}{{
+		name:            "Spec changed",
+		resourceVersion: "1",
+		mutator:         SetVersion("1.6"),
+		expectedAnnotations: map[string]string{
+			"test-key":                "test-value",
+			PreviousVersionAnnotation: "1.5.0",
+			PreviousSpecAnnotation:    `{"registry":{},"version":"1.5"}`,
+		},
+	}, {
+		name:                "Spec not changed",
+		resourceVersion:     "1",
+		mutator:             SetVersion("1.5"),
+		expectedAnnotations: map[string]string{"test-key": "test-value"},
+	}, {
+		name:                "Custom resource to be created",
+		mutator:             SetVersion("1.6"),
+		expectedAnnotations: map[string]string{"test-key": "test-value"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			ke := &v1beta1.KnativeEventing{}
+			ke.ResourceVersion = tt.resourceVersion
+			ke.Annotations = map[string]string{"test-key": "test-value"}
+			ke.Spec.Version = "1.5"
+			spec := NewKnativeEventingSpec(&ke.Spec)
+			spec.ObjectMeta, spec.StatusVersion = &ke.ObjectMeta, "1.5.0"
+
+			err := recordPreviousState([]SpecMutator{tt.mutator})(spec)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, ke.Annotations, tt.expectedAnnotations)
+		})
+	}
+}
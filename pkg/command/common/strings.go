@@ -16,6 +16,8 @@ limitations under the License.
 
 package common
 
+import "strings"
+
 func Contains(s []string, searchterm string) bool {
 	set := make(map[string]struct{}, len(s))
 	for _, s := range s {
@@ -25,3 +27,40 @@ func Contains(s []string, searchterm string) bool {
 	_, ok := set[searchterm]
 	return ok
 }
+
+// Suggestions returns the candidates close to the term, e.g. to correct a typo. A candidate is close, when it
+// starts with the term, or is within a small edit distance of it.
+func Suggestions(term string, candidates []string) []string {
+	maxDistance := len(term) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	suggestions := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, term) || editDistance(term, candidate) <= maxDistance {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -29,3 +29,11 @@ func TestContains(t *testing.T) {
 	exists = Contains(input, "bird-not-exist")
 	testingUtil.AssertEqual(t, exists, false)
 }
+
+func TestSuggestions(t *testing.T) {
+	candidates := []string{"activator", "autoscaler", "autoscaler-hpa", "controller", "webhook"}
+	testingUtil.AssertDeepEqual(t, Suggestions("activater", candidates), []string{"activator"})
+	testingUtil.AssertDeepEqual(t, Suggestions("autoscaler", candidates), []string{"autoscaler", "autoscaler-hpa"})
+	testingUtil.AssertDeepEqual(t, Suggestions("webhok", candidates), []string{"webhook"})
+	testingUtil.AssertDeepEqual(t, Suggestions("queue-proxy", candidates), []string{})
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package common
 
 import (
+	"strings"
+
 	"golang.org/x/mod/semver"
 )
 
@@ -28,3 +30,22 @@ func GetMajor(version string) (bool, string) {
 	}
 	return validity, major
 }
+
+// IsVersionAtLeast checks if the Knative version is the minimum version or newer. The version latest, nightly or
+// empty is always newer.
+func IsVersionAtLeast(version, minimum string) bool {
+	v := SemanticVersion(version)
+	if v == "" {
+		return true
+	}
+	return semver.Compare(v, SemanticVersion(minimum)) >= 0
+}
+
+// SemanticVersion returns the canonical semantic version of the Knative version, e.g. v1.11.0 for 1.11, or empty for
+// latest, nightly or an invalid version
+func SemanticVersion(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return semver.Canonical(version)
+}
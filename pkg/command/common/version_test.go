@@ -48,3 +48,36 @@ func TestGetMajor(t *testing.T) {
 		})
 	}
 }
+
+func TestIsVersionAtLeast(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		version  string
+		minimum  string
+		expected bool
+	}{{
+		name:     "Newer version",
+		version:  "1.12.1",
+		minimum:  "1.12",
+		expected: true,
+	}, {
+		name:     "Older version with prefix",
+		version:  "v1.11.3",
+		minimum:  "1.12",
+		expected: false,
+	}, {
+		name:     "Latest version",
+		version:  "latest",
+		minimum:  "1.12",
+		expected: true,
+	}, {
+		name:     "Empty version",
+		version:  "",
+		minimum:  "1.12",
+		expected: true,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			testingUtil.AssertEqual(t, IsVersionAtLeast(tt.version, tt.minimum), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+	operatorv1beta1 "knative.dev/operator/pkg/client/clientset/versioned/typed/operator/v1beta1"
+)
+
+// DefaultTimeout is the default time to wait for the Knative resources to reach a certain state
+const DefaultTimeout = 5 * time.Minute
+
+// ErrWaitTimeout is returned when the condition is not met within the timeout
+var ErrWaitTimeout = errors.New("timed out waiting for the condition")
+
+// ListWatch lists and watches the objects of a certain kind
+type ListWatch struct {
+	// List returns the objects and the resource version of the list
+	List func(ctx context.Context) ([]runtime.Object, string, error)
+	// Watch watches the changes of the objects since the resource version
+	Watch func(ctx context.Context, resourceVersion string) (watch.Interface, error)
+}
+
+// WatchUntil lists the objects, then watches their changes until the condition on the objects, keyed by
+// their names, is met. The condition is evaluated on every change, so that the wait reacts immediately.
+// The objects are listed again, when the watch is closed by the server.
+func WatchUntil(ctx context.Context, timeout time.Duration, lw ListWatch,
+	condition func(objects map[string]runtime.Object) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		items, resourceVersion, err := lw.List(ctx)
+		if err != nil {
+			return waitError(ctx, timeout, err)
+		}
+		objects := make(map[string]runtime.Object, len(items))
+		for _, item := range items {
+			objects[objectName(item)] = item
+		}
+		if done, err := condition(objects); err != nil || done {
+			return err
+		}
+
+		watcher, err := lw.Watch(ctx, resourceVersion)
+		if err != nil {
+			return waitError(ctx, timeout, err)
+		}
+		done, err := watchEvents(ctx, watcher, objects, condition)
+		watcher.Stop()
+		if err != nil {
+			return waitError(ctx, timeout, err)
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// watchEvents applies the events of the watch on the objects until the condition is met. It returns false
+// without any error, when the watch is closed or expired, so that the objects need to be listed again.
+func watchEvents(ctx context.Context, watcher watch.Interface, objects map[string]runtime.Object,
+	condition func(objects map[string]runtime.Object) (bool, error)) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case event, ok := <-watcher.ResultChan():
+			if !ok || event.Type == watch.Error {
+				return false, nil
+			}
+			applyEvent(objects, event)
+			if done, err := condition(objects); err != nil || done {
+				return done, err
+			}
+		}
+	}
+}
+
+// applyEvent updates the objects with the change in the event
+func applyEvent(objects map[string]runtime.Object, event watch.Event) {
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		objects[objectName(event.Object)] = event.Object
+	case watch.Deleted:
+		delete(objects, objectName(event.Object))
+	}
+}
+
+func objectName(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
+
+// waitError returns ErrWaitTimeout, if the wait failed because of the timeout
+func waitError(ctx context.Context, timeout time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrWaitTimeout, timeout)
+	}
+	return err
+}
+
+// KnativeServingListWatch lists and watches the KnativeServing called name, or all the KnativeServings if the
+// name is empty
+func KnativeServingListWatch(client operatorv1beta1.KnativeServingInterface, name string) ListWatch {
+	return ListWatch{
+		List: func(ctx context.Context) ([]runtime.Object, string, error) {
+			list, err := client.List(ctx, nameListOptions(name, ""))
+			if err != nil {
+				return nil, "", err
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return client.Watch(ctx, nameListOptions(name, resourceVersion))
+		},
+	}
+}
+
+// KnativeEventingListWatch lists and watches the KnativeEventing called name, or all the KnativeEventings if the
+// name is empty
+func KnativeEventingListWatch(client operatorv1beta1.KnativeEventingInterface, name string) ListWatch {
+	return ListWatch{
+		List: func(ctx context.Context) ([]runtime.Object, string, error) {
+			list, err := client.List(ctx, nameListOptions(name, ""))
+			if err != nil {
+				return nil, "", err
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return client.Watch(ctx, nameListOptions(name, resourceVersion))
+		},
+	}
+}
+
+// DeploymentListWatch lists and watches the deployments under the namespace, which match the label selector
+func DeploymentListWatch(client kubernetes.Interface, namespace, selector string) ListWatch {
+	return ListWatch{
+		List: func(ctx context.Context) ([]runtime.Object, string, error) {
+			list, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return nil, "", err
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return client.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+				LabelSelector:   selector,
+				ResourceVersion: resourceVersion,
+			})
+		},
+	}
+}
+
+// NamespaceListWatch lists and watches the namespace called name
+func NamespaceListWatch(client kubernetes.Interface, name string) ListWatch {
+	return ListWatch{
+		List: func(ctx context.Context) ([]runtime.Object, string, error) {
+			list, err := client.CoreV1().Namespaces().List(ctx, nameListOptions(name, ""))
+			if err != nil {
+				return nil, "", err
+			}
+			objects := make([]runtime.Object, 0, len(list.Items))
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+			return client.CoreV1().Namespaces().Watch(ctx, nameListOptions(name, resourceVersion))
+		},
+	}
+}
+
+// nameListOptions selects the object by its name, when listing or watching the objects. All the objects are
+// selected, if the name is empty.
+func nameListOptions(name, resourceVersion string) metav1.ListOptions {
+	options := metav1.ListOptions{ResourceVersion: resourceVersion}
+	if name != "" {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+	}
+	return options
+}
+
+// WaitForKnativeCRReady waits until the Knative custom resource under a certain namespace has reconciled its
+// latest spec and is ready
+func WaitForKnativeCRReady(component, namespace string, timeout time.Duration, p *pkg.OperatorParams) error {
+	operatorClient, err := p.NewOperatorClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	name := KnativeEventingName
+	lw := KnativeEventingListWatch(operatorClient.OperatorV1beta1().KnativeEventings(namespace), name)
+	if strings.EqualFold(component, ServingComponent) {
+		name = KnativeServingName
+		lw = KnativeServingListWatch(operatorClient.OperatorV1beta1().KnativeServings(namespace), name)
+	}
+
+	p.SetStep(fmt.Sprintf("Waiting for Knative %s in the namespace '%s' to be ready...", component, namespace))
+	err = WatchUntil(p.Context(), timeout, lw, func(objects map[string]runtime.Object) (bool, error) {
+		return IsKnativeCRReconciled(objects[name]), nil
+	})
+	if errors.Is(err, ErrWaitTimeout) {
+		return fmt.Errorf("Knative %s in the namespace '%s' is not ready: %w", component, namespace, err)
+	}
+	return err
+}
+
+// IsKnativeCRReconciled checks if the Knative custom resource has reconciled its latest spec and is ready
+func IsKnativeCRReconciled(obj runtime.Object) bool {
+	switch cr := obj.(type) {
+	case *v1beta1.KnativeServing:
+		return cr.Status.ObservedGeneration >= cr.Generation && cr.Status.IsReady()
+	case *v1beta1.KnativeEventing:
+		return cr.Status.ObservedGeneration >= cr.Generation && cr.Status.IsReady()
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"knative.dev/pkg/apis"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func servingWithGeneration(generation, observedGeneration int64, ready bool) *v1beta1.KnativeServing {
+	ks := &v1beta1.KnativeServing{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       KnativeServingName,
+			Generation: generation,
+		},
+	}
+	ks.Status.ObservedGeneration = observedGeneration
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	ks.Status.Conditions = []apis.Condition{{Type: apis.ConditionReady, Status: status}}
+	return ks
+}
+
+func isServingReady(objects map[string]runtime.Object) (bool, error) {
+	return IsKnativeCRReconciled(objects[KnativeServingName]), nil
+}
+
+func TestWatchUntil(t *testing.T) {
+	t.Run("Condition met by the list", func(t *testing.T) {
+		watched := false
+		lw := ListWatch{
+			List: func(ctx context.Context) ([]runtime.Object, string, error) {
+				return []runtime.Object{servingWithGeneration(1, 1, true)}, "1", nil
+			},
+			Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+				watched = true
+				return watch.NewFake(), nil
+			},
+		}
+		err := WatchUntil(context.Background(), time.Second, lw, isServingReady)
+		testingUtil.AssertEqual(t, err, nil)
+		testingUtil.AssertEqual(t, watched, false)
+	})
+
+	t.Run("Condition met by an event", func(t *testing.T) {
+		watcher := watch.NewFake()
+		lw := ListWatch{
+			List: func(ctx context.Context) ([]runtime.Object, string, error) {
+				return []runtime.Object{servingWithGeneration(2, 1, true)}, "1", nil
+			},
+			Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+				testingUtil.AssertEqual(t, resourceVersion, "1")
+				go func() {
+					watcher.Modify(servingWithGeneration(2, 2, false))
+					watcher.Modify(servingWithGeneration(2, 2, true))
+				}()
+				return watcher, nil
+			},
+		}
+		err := WatchUntil(context.Background(), 5*time.Second, lw, isServingReady)
+		testingUtil.AssertEqual(t, err, nil)
+	})
+
+	t.Run("List again after the watch is closed", func(t *testing.T) {
+		lists := 0
+		lw := ListWatch{
+			List: func(ctx context.Context) ([]runtime.Object, string, error) {
+				lists++
+				return []runtime.Object{servingWithGeneration(1, 1, lists > 1)}, "1", nil
+			},
+			Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+				watcher := watch.NewFake()
+				go watcher.Stop()
+				return watcher, nil
+			},
+		}
+		err := WatchUntil(context.Background(), 5*time.Second, lw, isServingReady)
+		testingUtil.AssertEqual(t, err, nil)
+		testingUtil.AssertEqual(t, lists, 2)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		lw := ListWatch{
+			List: func(ctx context.Context) ([]runtime.Object, string, error) {
+				return []runtime.Object{}, "1", nil
+			},
+			Watch: func(ctx context.Context, resourceVersion string) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}
+		err := WatchUntil(context.Background(), 10*time.Millisecond, lw, isServingReady)
+		testingUtil.AssertEqual(t, errors.Is(err, ErrWaitTimeout), true)
+	})
+
+	t.Run("Condition error", func(t *testing.T) {
+		lw := ListWatch{
+			List: func(ctx context.Context) ([]runtime.Object, string, error) {
+				return []runtime.Object{}, "1", nil
+			},
+		}
+		err := WatchUntil(context.Background(), time.Second, lw, func(map[string]runtime.Object) (bool, error) {
+			return false, errors.New("failed")
+		})
+		testingUtil.AssertEqual(t, err.Error(), "failed")
+	})
+}
+
+func TestIsKnativeCRReconciled(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		obj      runtime.Object
+		expected bool
+	}{{
+		name:     "Ready and reconciled",
+		obj:      servingWithGeneration(2, 2, true),
+		expected: true,
+	}, {
+		name:     "Ready with an outdated status",
+		obj:      servingWithGeneration(2, 1, true),
+		expected: false,
+	}, {
+		name:     "Not ready",
+		obj:      servingWithGeneration(2, 2, false),
+		expected: false,
+	}, {
+		name:     "Missing",
+		obj:      nil,
+		expected: false,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			testingUtil.AssertEqual(t, IsKnativeCRReconciled(tt.obj), tt.expected)
+		})
+	}
+}
+
+func TestNameListOptions(t *testing.T) {
+	testingUtil.AssertDeepEqual(t, nameListOptions("knative-serving", "42"), metav1.ListOptions{
+		FieldSelector:   "metadata.name=knative-serving",
+		ResourceVersion: "42",
+	})
+	testingUtil.AssertDeepEqual(t, nameListOptions("", "42"), metav1.ListOptions{ResourceVersion: "42"})
+}
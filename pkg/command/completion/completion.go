@@ -0,0 +1,68 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Shells are the shells supported by the completion command
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// BinaryName is the name of the plugin binary, which the completion script is generated for. A script for the kn
+// command would replace the completion of kn itself, while kn completes `kn operator` by calling the plugin binary
+// with the hidden command __complete.
+const BinaryName = "kn-operator"
+
+// NewCompletionCommand represents the command to generate the completion script for a shell
+func NewCompletionCommand() *cobra.Command {
+	var completionCmd = &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate the completion script for the shell",
+		Long: `Generate the completion script for the shell. The script completes the binary kn-operator, when the
+plugin is run standalone. kn completes "kn operator" by calling the plugin itself.`,
+		Example: `
+  # Load the completion for the current bash session
+  source <(kn operator completion bash)
+  # Load the completion for every zsh session
+  kn operator completion zsh > "${fpath[1]}/_kn-operator"`,
+		ValidArgs:             Shells,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			// The root command is called "kn operator", so that the usage matches the plugin invocation
+			use := root.Use
+			root.Use = BinaryName
+			defer func() { root.Use = use }()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("The shell %s is not supported.", args[0])
+		},
+	}
+
+	return completionCmd
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func newTestRootCommand() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "kn operator"}
+	var component, namespace string
+	configureCmd := &cobra.Command{
+		Use:  "resources",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	configureCmd.Flags().StringVarP(&component, "component", "c", "", "")
+	configureCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "")
+	rootCmd.AddCommand(configureCmd)
+	rootCmd.AddCommand(NewCompletionCommand())
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	Register(rootCmd, &pkg.OperatorParams{})
+	return rootCmd
+}
+
+func TestCompletionCommand(t *testing.T) {
+	for _, tt := range []struct {
+		shell          string
+		expectedPrefix string
+	}{{
+		shell:          "bash",
+		expectedPrefix: "# bash completion V2 for kn-operator",
+	}, {
+		shell:          "zsh",
+		expectedPrefix: "#compdef kn-operator",
+	}, {
+		shell:          "fish",
+		expectedPrefix: "# fish completion for kn-operator",
+	}, {
+		shell:          "powershell",
+		expectedPrefix: "# powershell completion for kn-operator",
+	}} {
+		t.Run(tt.shell, func(t *testing.T) {
+			rootCmd := newTestRootCommand()
+			out := &bytes.Buffer{}
+			rootCmd.SetOut(out)
+			rootCmd.SetArgs([]string{"completion", tt.shell})
+			testingUtil.AssertEqual(t, rootCmd.Execute(), nil)
+			testingUtil.AssertEqual(t, strings.HasPrefix(out.String(), tt.expectedPrefix), true)
+			testingUtil.AssertEqual(t, strings.Contains(out.String(), "__start_kn "), false)
+			testingUtil.AssertEqual(t, rootCmd.Use, "kn operator")
+		})
+	}
+
+	rootCmd := newTestRootCommand()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"completion", "tcsh"})
+	testingUtil.AssertEqual(t, rootCmd.Execute() != nil, true)
+}
+
+func TestCompleteComponents(t *testing.T) {
+	rootCmd := newTestRootCommand()
+	out := &bytes.Buffer{}
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{cobra.ShellCompRequestCmd, "resources", "--component", ""})
+	testingUtil.AssertEqual(t, rootCmd.Execute(), nil)
+	testingUtil.AssertEqual(t, out.String(), "serving\neventing\n:4\n")
+}
+
+func TestExampleKeys(t *testing.T) {
+	example := `################################
+#                              #
+#    EXAMPLE CONFIGURATION     #
+#                              #
+################################
+
+# The target concurrency of a revision.
+container-concurrency-target-default: "100"
+
+# The time to keep the last pod.
+scale-to-zero-pod-retention-period: "0s"
+`
+	testingUtil.AssertDeepEqual(t, exampleKeys(example),
+		[]string{"container-concurrency-target-default", "scale-to-zero-pod-retention-period"})
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// completionFunc completes the value of a flag or an argument
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// Register adds the completions of the flags and the arguments to the command and all its subcommands.
+// The flags are completed by their names, so that the commands sharing a flag complete it in the same way.
+func Register(cmd *cobra.Command, p *pkg.OperatorParams) {
+	completions := map[string]completionFunc{
+		"component":  completeComponents,
+		"namespace":  completeNamespaces(p),
+		"deployName": completeDeployments(p),
+		"container":  completeContainers(p),
+		"cmName":     completeConfigMaps(p),
+	}
+	for flag, complete := range completions {
+		if cmd.Flags().Lookup(flag) != nil {
+			cmd.RegisterFlagCompletionFunc(flag, complete)
+		}
+	}
+	// The flag key is shared by the commands of the tolerations and the key-value pairs, but only the
+	// ConfigMaps have the keys to complete
+	if cmd.Flags().Lookup("cmName") != nil && cmd.Flags().Lookup("key") != nil {
+		cmd.RegisterFlagCompletionFunc("key", completeConfigMapKeys(p))
+	}
+	if cmd.ValidArgsFunction == nil && len(cmd.ValidArgs) == 0 {
+		cmd.ValidArgsFunction = cobra.NoFileCompletions
+	}
+
+	for _, child := range cmd.Commands() {
+		Register(child, p)
+	}
+}
+
+func completeComponents(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{common.ServingComponent, common.EventingComponent}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNamespaces completes the namespaces with Knative installed. Only the namespaces of the component
+// are completed, if the component is set.
+func completeNamespaces(p *pkg.OperatorParams) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		operatorClient, err := p.NewOperatorClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		ctx := p.Context()
+		component := flagValue(cmd, "component")
+
+		namespaces := []string{}
+		if !strings.EqualFold(component, common.EventingComponent) {
+			if list, err := operatorClient.OperatorV1beta1().KnativeServings("").List(ctx, metav1.ListOptions{}); err == nil {
+				for _, ks := range list.Items {
+					namespaces = append(namespaces, ks.Namespace)
+				}
+			}
+		}
+		if !strings.EqualFold(component, common.ServingComponent) {
+			if list, err := operatorClient.OperatorV1beta1().KnativeEventings("").List(ctx, metav1.ListOptions{}); err == nil {
+				for _, ke := range list.Items {
+					namespaces = append(namespaces, ke.Namespace)
+				}
+			}
+		}
+		return uniqueSorted(namespaces), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeDeployments completes the deployments of the component in its namespace
+func completeDeployments(p *pkg.OperatorParams) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		deployments := discoverDeployments(cmd, p)
+		return deployments.Names(), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeContainers completes the containers of the deployment set by the flag deployName
+func completeContainers(p *pkg.OperatorParams) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		deployments := discoverDeployments(cmd, p)
+		return deployments[flagValue(cmd, "deployName")], cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeConfigMaps completes the names of the ConfigMaps, which configure the component
+func completeConfigMaps(p *pkg.OperatorParams) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		client, err := p.NewKubeClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		list, err := client.CoreV1().ConfigMaps(componentNamespace(cmd)).List(p.Context(), metav1.ListOptions{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := []string{}
+		for _, cm := range list.Items {
+			if strings.HasPrefix(cm.Name, "config-") {
+				names = append(names, cm.Name)
+			}
+		}
+		return uniqueSorted(names), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeConfigMapKeys completes the keys of the ConfigMap set by the flag cmName, including the keys
+// documented in its example
+func completeConfigMapKeys(p *pkg.OperatorParams) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		client, err := p.NewKubeClient()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		name := common.FullConfigMapName(flagValue(cmd, "cmName"))
+		cm, err := client.CoreV1().ConfigMaps(componentNamespace(cmd)).Get(p.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		keys := []string{}
+		for key := range cm.Data {
+			if key != "_example" {
+				keys = append(keys, key)
+			}
+		}
+		keys = append(keys, exampleKeys(cm.Data["_example"])...)
+		return uniqueSorted(keys), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// exampleKeys returns the keys documented in the _example of a Knative ConfigMap, where each key is
+// given as a line "key: value"
+func exampleKeys(example string) []string {
+	keys := []string{}
+	for _, line := range strings.Split(example, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, " ") {
+			continue
+		}
+		if index := strings.Index(line, ":"); index > 0 {
+			keys = append(keys, strings.TrimSpace(line[:index]))
+		}
+	}
+	return keys
+}
+
+func discoverDeployments(cmd *cobra.Command, p *pkg.OperatorParams) common.ComponentDeployments {
+	client, err := p.NewKubeClient()
+	if err != nil {
+		return nil
+	}
+	deployments, err := common.DiscoverDeployments(p.Context(), client, componentName(cmd), componentNamespace(cmd))
+	if err != nil {
+		return nil
+	}
+	return deployments
+}
+
+// componentName returns the component set by the flag, which is Knative Serving by default
+func componentName(cmd *cobra.Command) string {
+	if strings.EqualFold(flagValue(cmd, "component"), common.EventingComponent) {
+		return common.EventingComponent
+	}
+	return common.ServingComponent
+}
+
+// componentNamespace returns the namespace set by the flag, or the default namespace of the component
+func componentNamespace(cmd *cobra.Command) string {
+	if namespace := flagValue(cmd, "namespace"); namespace != "" {
+		return namespace
+	}
+	if componentName(cmd) == common.EventingComponent {
+		return common.DefaultKnativeEventingNamespace
+	}
+	return common.DefaultKnativeServingNamespace
+}
+
+func flagValue(cmd *cobra.Command, name string) string {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return ""
+	}
+	return flag.Value.String()
+}
+
+func uniqueSorted(values []string) []string {
+	found := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if !found[value] {
+			found[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
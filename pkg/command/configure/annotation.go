@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -23,18 +22,12 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_deploy_label.yaml
-var servingAnnotationOverlay string
-
-//go:embed overlay/ke_deploy_label.yaml
-var eventingAnnotationOverlay string
-
-var annotationCMDFlags common.KeyValueFlags
-
 // newAnnotationCommand represents the configure commands to configure the annotations for Knative deployment
-func newAnnotationCommand(p *pkg.OperatorParams) *cobra.Command {
+func newAnnotationCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var annotationCMDFlags common.KeyValueFlags
 	var configureLabelsCmd = &cobra.Command{
 		Use:   "annotations",
 		Short: "Configure the annotations for Knative Serving and Eventing deployments or services",
@@ -44,14 +37,11 @@ func newAnnotationCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the annotations for Knative Serving and Eventing services
   kn operation annotations --component eventing --serviceName eventing-controller --key key --value value --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateLabelsAnnotationsFlags(annotationCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureAnnotations(annotationCMDFlags, p)
+			err := ConfigureAnnotations(annotationCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(annotationCMDFlags.Component, annotationCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified annotation has been configured for the deployment %s in the deployment '%s'.\n",
 				annotationCMDFlags.DeployName, annotationCMDFlags.Namespace)
@@ -65,74 +55,41 @@ func newAnnotationCommand(p *pkg.OperatorParams) *cobra.Command {
 	configureLabelsCmd.Flags().StringVar(&annotationCMDFlags.ServiceName, "serviceName", "", "The flag to specify the service name")
 	configureLabelsCmd.Flags().StringVarP(&annotationCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
 	configureLabelsCmd.Flags().StringVarP(&annotationCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+	common.AddKeyValueSourceFlags(configureLabelsCmd.Flags(), &annotationCMDFlags.KeyValueSources)
 
 	return configureLabelsCmd
 }
 
-func configureAnnotations(annotationCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+// ConfigureAnnotations sets the annotation for a Knative deployment or service
+func ConfigureAnnotations(annotationCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+	if err := validateLabelsAnnotationsFlags(annotationCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(annotationCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, annotationCMDFlags.Namespace, p)
-	if err != nil {
+	if err := common.CheckDeployment(component, annotationCMDFlags.Namespace, annotationCMDFlags.DeployName, "", p); err != nil {
 		return err
 	}
-
-	annotationOverlayContent := getOverlayYamlContentAnnotation(annotationCMDFlags)
-	valuesYaml := getYamlValuesContent(annotationCMDFlags)
-	if err := common.ApplyManifests(yamlTemplateString, annotationOverlayContent, valuesYaml, p); err != nil {
+	mutators, err := keyValueMutators(annotationCMDFlags, annotationMutator)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func getOverlayYamlContentAnnotation(annotationCMDFlags common.KeyValueFlags) string {
-	baseOverlayContent := servingAnnotationOverlay
-	if strings.EqualFold(annotationCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingAnnotationOverlay
-	}
-	resourceContent := getAnnotationConfiguration(annotationCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
+	return common.ApplyKnativeCR(component, annotationCMDFlags.Namespace, p, mutators...)
 }
 
-func getAnnotationConfiguration(annotationCMDFlags common.KeyValueFlags) string {
-	resourceArray := []string{}
-
-	tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	if annotationCMDFlags.DeployName != "" {
-		field := fmt.Sprintf("%s%s:", common.Spaces(2), "deployments")
-		resourceArray = append(resourceArray, field)
-	} else {
-		field := fmt.Sprintf("%s%s:", common.Spaces(2), "services")
-		resourceArray = append(resourceArray, field)
-	}
-
-	field := fmt.Sprintf("%s%s", common.Spaces(2), common.FieldByName("name"))
-	resourceArray = append(resourceArray, field)
-
+// annotationMutator sets the annotation for the deployment or the service
+func annotationMutator(annotationCMDFlags common.KeyValueFlags) common.SpecMutator {
 	if annotationCMDFlags.DeployName != "" {
-		deployName := fmt.Sprintf("%s- %s: %s", common.Spaces(2), "name", "#@ data.values.deployName")
-		resourceArray = append(resourceArray, deployName)
-	} else {
-		serviceName := fmt.Sprintf("%s- %s: %s", common.Spaces(2), "name", "#@ data.values.serviceName")
-		resourceArray = append(resourceArray, serviceName)
+		return common.UpdateDeploymentOverride(annotationCMDFlags.DeployName, func(override *base.WorkloadOverride) error {
+			override.Annotations = common.SetMapValue(override.Annotations, annotationCMDFlags.Key, annotationCMDFlags.Value)
+			return nil
+		})
 	}
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	field = fmt.Sprintf("%s%s:", common.Spaces(4), "annotations")
-	resourceArray = append(resourceArray, field)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(6), annotationCMDFlags.Key, "#@ data.values.value")
-	resourceArray = append(resourceArray, keyValueField)
-
-	return strings.Join(resourceArray, "\n")
+	return common.UpdateServiceOverride(annotationCMDFlags.ServiceName, func(override *base.ServiceOverride) error {
+		override.Annotations = common.SetMapValue(override.Annotations, annotationCMDFlags.Key, annotationCMDFlags.Value)
+		return nil
+	})
 }
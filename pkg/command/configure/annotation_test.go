@@ -19,77 +19,61 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-func TestGetOverlayYamlContentAnnotation(t *testing.T) {
+func TestAnnotationMutator(t *testing.T) {
 	for _, tt := range []struct {
-		name                    string
-		deploymentLabelCMDFlags common.KeyValueFlags
-		expectedResult          string
+		name                string
+		annotationCMDFlags  common.KeyValueFlags
+		input               v1beta1.KnativeServingSpec
+		expectedDeployments []base.WorkloadOverride
+		expectedServices    []base.ServiceOverride
 	}{{
-		name: "Knative Serving template for annotation configuration",
-		deploymentLabelCMDFlags: common.KeyValueFlags{
+		name: "Add the annotation to a new deployment override",
+		annotationCMDFlags: common.KeyValueFlags{
 			Key:        "test-key",
 			Value:      "test-value",
 			Component:  "serving",
 			Namespace:  "test-serving",
 			DeployName: "network",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    annotations:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
+		input: v1beta1.KnativeServingSpec{},
+		expectedDeployments: []base.WorkloadOverride{{
+			Name:        "network",
+			Annotations: map[string]string{"test-key": "test-value"},
+		}},
 	}, {
-		name: "Knative Serving template for annotation configuration for the service",
-		deploymentLabelCMDFlags: common.KeyValueFlags{
+		name: "Set the annotation of the existing service override",
+		annotationCMDFlags: common.KeyValueFlags{
 			Key:         "test-key",
 			Value:       "test-value",
 			Component:   "serving",
 			Namespace:   "test-serving",
 			ServiceName: "network",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  services:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.serviceName
-    #@overlay/match missing_ok=True
-    annotations:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
+		input: v1beta1.KnativeServingSpec{
+			CommonSpec: base.CommonSpec{
+				ServiceOverride: []base.ServiceOverride{{
+					Name:        "network",
+					Labels:      map[string]string{"label-key": "label-value"},
+					Annotations: map[string]string{"test-key": "old-value", "key": "value"},
+				}},
+			},
+		},
+		expectedServices: []base.ServiceOverride{{
+			Name:        "network",
+			Labels:      map[string]string{"label-key": "label-value"},
+			Annotations: map[string]string{"test-key": "test-value", "key": "value"},
+		}},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentAnnotation(tt.deploymentLabelCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := tt.input
+			err := common.NewKnativeServingSpec(&spec).Mutate(annotationMutator(tt.annotationCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedDeployments)
+			testingUtil.AssertDeepEqual(t, spec.ServiceOverride, tt.expectedServices)
 		})
 	}
 }
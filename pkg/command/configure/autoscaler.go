@@ -0,0 +1,289 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// minPanicWindow is the shortest panic window, which is the bucket size of the autoscaler
+const minPanicWindow = time.Second
+
+type AutoscalerFlags struct {
+	EnableScaleToZero        string
+	ScaleToZeroGracePeriod   string
+	StableWindow             string
+	PanicWindowPercentage    string
+	PanicThresholdPercentage string
+	TargetUtilization        string
+	ScaleDownDelay           string
+	InitialScale             string
+	AllowZeroInitialScale    string
+	MinScale                 string
+	MaxScale                 string
+	MaxScaleLimit            string
+	Namespace                string
+}
+
+// autoscalerKey is a flag of the autoscaler with its key in config-autoscaler and the parser of its value
+type autoscalerKey struct {
+	key   string
+	value func(flags AutoscalerFlags) string
+	parse func(value string) (string, error)
+}
+
+var autoscalerKeys = []autoscalerKey{
+	{"enable-scale-to-zero", func(f AutoscalerFlags) string { return f.EnableScaleToZero }, parseBool},
+	{"scale-to-zero-grace-period", func(f AutoscalerFlags) string { return f.ScaleToZeroGracePeriod }, parseDuration},
+	{"stable-window", func(f AutoscalerFlags) string { return f.StableWindow }, parseDuration},
+	{"panic-window-percentage", func(f AutoscalerFlags) string { return f.PanicWindowPercentage }, parsePercent},
+	{"panic-threshold-percentage", func(f AutoscalerFlags) string { return f.PanicThresholdPercentage }, parsePercent},
+	{"container-concurrency-target-percentage", func(f AutoscalerFlags) string { return f.TargetUtilization }, parsePercent},
+	{"scale-down-delay", func(f AutoscalerFlags) string { return f.ScaleDownDelay }, parseDuration},
+	{"initial-scale", func(f AutoscalerFlags) string { return f.InitialScale }, parseInt},
+	{"allow-zero-initial-scale", func(f AutoscalerFlags) string { return f.AllowZeroInitialScale }, parseBool},
+	{"min-scale", func(f AutoscalerFlags) string { return f.MinScale }, parseInt},
+	{"max-scale", func(f AutoscalerFlags) string { return f.MaxScale }, parseInt},
+	{"max-scale-limit", func(f AutoscalerFlags) string { return f.MaxScaleLimit }, parseInt},
+}
+
+// newAutoscalerCommand represents the configure command to tune the autoscaler of Knative Serving
+func newAutoscalerCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var autoscalerCMDFlags AutoscalerFlags
+	var configureAutoscalerCmd = &cobra.Command{
+		Use:   "autoscaler",
+		Short: "Configure the autoscaler for Knative Serving",
+		Example: `
+  # Configure the windows and the target utilization of the autoscaler for Knative Serving
+  kn operation configure autoscaler --stable-window 2m --panic-window-percentage 20% --target-utilization 80% --namespace knative-serving
+  # Configure the scale bounds and disable the scale to zero for Knative Serving
+  kn operation configure autoscaler --enable-scale-to-zero=false --min-scale 1 --max-scale 10 --namespace knative-serving
+  # Allow the new revisions to start without any pod
+  kn operation configure autoscaler --initial-scale 0 --allow-zero-initial-scale --namespace knative-serving`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := ConfigureAutoscaler(autoscalerCMDFlags, p)
+			if err != nil {
+				return err
+			}
+			applied.set(common.ServingComponent, autoscalerCMDFlags.Namespace)
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The autoscaler has been configured in the namespace '%s'.\n",
+				autoscalerCMDFlags.Namespace)
+			return nil
+		},
+	}
+
+	flags := configureAutoscalerCmd.Flags()
+	flags.StringVar(&autoscalerCMDFlags.EnableScaleToZero, "enable-scale-to-zero", "", "The flag to allow the revisions to scale to zero: true or false")
+	flags.Lookup("enable-scale-to-zero").NoOptDefVal = "true"
+	flags.StringVar(&autoscalerCMDFlags.ScaleToZeroGracePeriod, "scale-to-zero-grace-period", "", "The time to keep the last pod after the traffic stops, e.g. 30s")
+	flags.StringVar(&autoscalerCMDFlags.StableWindow, "stable-window", "", "The window to average the metrics in the stable mode, between 6s and 1h")
+	flags.StringVar(&autoscalerCMDFlags.PanicWindowPercentage, "panic-window-percentage", "", "The panic window as the percentage of the stable window, e.g. 10%")
+	flags.StringVar(&autoscalerCMDFlags.PanicThresholdPercentage, "panic-threshold-percentage", "", "The percentage of the target concurrency to enter the panic mode, e.g. 200%")
+	flags.StringVar(&autoscalerCMDFlags.TargetUtilization, "target-utilization", "", "The percentage of the concurrency target to scale at, e.g. 70%")
+	flags.StringVar(&autoscalerCMDFlags.ScaleDownDelay, "scale-down-delay", "", "The time to wait before scaling down, up to 1h")
+	flags.StringVar(&autoscalerCMDFlags.InitialScale, "initial-scale", "", "The number of pods of a new revision")
+	flags.StringVar(&autoscalerCMDFlags.AllowZeroInitialScale, "allow-zero-initial-scale", "", "The flag to allow the initial scale of zero: true or false")
+	flags.Lookup("allow-zero-initial-scale").NoOptDefVal = "true"
+	flags.StringVar(&autoscalerCMDFlags.MinScale, "min-scale", "", "The minimum number of pods of a revision")
+	flags.StringVar(&autoscalerCMDFlags.MaxScale, "max-scale", "", "The maximum number of pods of a revision, 0 means unlimited")
+	flags.StringVar(&autoscalerCMDFlags.MaxScaleLimit, "max-scale-limit", "", "The upper bound of the max-scale of the revisions, 0 means unlimited")
+	flags.StringVarP(&autoscalerCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+
+	return configureAutoscalerCmd
+}
+
+func validateAutoscalerFlags(autoscalerCMDFlags AutoscalerFlags) error {
+	if autoscalerCMDFlags.Namespace == "" {
+		return fmt.Errorf("You need to specify the namespace.")
+	}
+	return nil
+}
+
+// autoscalerData returns the normalized values of the flags set, keyed by the keys of config-autoscaler
+func autoscalerData(autoscalerCMDFlags AutoscalerFlags) (map[string]string, error) {
+	data := map[string]string{}
+	for _, item := range autoscalerKeys {
+		value := strings.TrimSpace(item.value(autoscalerCMDFlags))
+		if value == "" {
+			continue
+		}
+		normalized, err := item.parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("The value '%s' of %s is invalid: %v.", value, item.key, err)
+		}
+		data[item.key] = normalized
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("You need to specify at least one setting of the autoscaler.")
+	}
+	return data, nil
+}
+
+func parseBool(value string) (string, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", fmt.Errorf("expected true or false")
+	}
+	return strconv.FormatBool(b), nil
+}
+
+func parseDuration(value string) (string, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return "", fmt.Errorf("expected a duration like 30s or 1m")
+	}
+	return d.String(), nil
+}
+
+// parsePercent parses the percentage with or without the suffix %
+func parsePercent(value string) (string, error) {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return "", fmt.Errorf("expected a percentage like 70 or 70%%")
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), nil
+}
+
+func parseInt(value string) (string, error) {
+	i, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("expected an integer")
+	}
+	return strconv.FormatInt(i, 10), nil
+}
+
+// ConfigureAutoscaler sets the keys of config-autoscaler for Knative Serving in a single update
+func ConfigureAutoscaler(autoscalerCMDFlags AutoscalerFlags, p *pkg.OperatorParams) error {
+	if err := validateAutoscalerFlags(autoscalerCMDFlags); err != nil {
+		return err
+	}
+
+	data, err := autoscalerData(autoscalerCMDFlags)
+	if err != nil {
+		return err
+	}
+	return common.ApplyKnativeCR(common.ServingComponent, autoscalerCMDFlags.Namespace, p, autoscalerMutator(data))
+}
+
+// autoscalerMutator sets the keys of config-autoscaler, after validating them against the schema for the version in
+// the spec, and together with the keys already set in the custom resource and the defaults of Knative
+func autoscalerMutator(data map[string]string) common.SpecMutator {
+	return func(spec *common.KnativeSpec) error {
+		if spec.Serving == nil {
+			return fmt.Errorf("The autoscaler can only be configured for Knative Serving.")
+		}
+
+		schema, err := common.LoadConfigSchema()
+		if err != nil {
+			return err
+		}
+		for key, value := range data {
+			if err := schema.ValidateConfigMapData(common.ServingComponent, spec.Version, "config-autoscaler", key, value); err != nil {
+				return err
+			}
+		}
+
+		cmName := "autoscaler"
+		if _, ok := spec.Config["config-autoscaler"]; ok {
+			cmName = "config-autoscaler"
+		}
+		effective := autoscalerDefaults(schema, spec.Version)
+		for _, name := range []string{"autoscaler", "config-autoscaler"} {
+			for key, value := range spec.Config[name] {
+				effective[key] = value
+			}
+		}
+		for key, value := range data {
+			effective[key] = value
+		}
+		if err := validateAutoscalerConfig(effective); err != nil {
+			return err
+		}
+
+		for key, value := range data {
+			if err := common.SetConfigMapData(cmName, key, value)(spec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// autoscalerDefaults returns the defaults of config-autoscaler in the schema for the version of Knative Serving
+func autoscalerDefaults(schema common.ConfigSchema, version string) map[string]string {
+	defaults := map[string]string{}
+	cmSchemas, _ := schema.ConfigMaps(common.ServingComponent, version)
+	for key, keySchema := range cmSchemas["config-autoscaler"] {
+		if keySchema.Default != "" {
+			defaults[key] = keySchema.Default
+		}
+	}
+	return defaults
+}
+
+// validateAutoscalerConfig checks the keys of config-autoscaler depending on each other. The keys, which cannot be
+// parsed, are left to the validation of Knative.
+func validateAutoscalerConfig(config map[string]string) error {
+	minScale, minErr := strconv.ParseInt(config["min-scale"], 10, 32)
+	maxScale, maxErr := strconv.ParseInt(config["max-scale"], 10, 32)
+	if minErr == nil && maxErr == nil && maxScale != 0 && minScale > maxScale {
+		return fmt.Errorf("The min-scale %d cannot be greater than the max-scale %d.", minScale, maxScale)
+	}
+	maxScaleLimit, err := strconv.ParseInt(config["max-scale-limit"], 10, 32)
+	if err == nil && maxErr == nil && maxScaleLimit > 0 && maxScale > maxScaleLimit {
+		return fmt.Errorf("The max-scale %d cannot be greater than the max-scale-limit %d.", maxScale, maxScaleLimit)
+	}
+
+	initialScale, err := strconv.ParseInt(config["initial-scale"], 10, 32)
+	if err == nil {
+		if maxErr == nil && maxScale != 0 && initialScale > maxScale {
+			return fmt.Errorf("The initial-scale %d cannot be greater than the max-scale %d.", initialScale, maxScale)
+		}
+		allowZero, _ := strconv.ParseBool(config["allow-zero-initial-scale"])
+		if initialScale == 0 && !allowZero {
+			return fmt.Errorf("The initial-scale 0 needs allow-zero-initial-scale to be true, please use --allow-zero-initial-scale.")
+		}
+	}
+
+	stableWindow, err := time.ParseDuration(config["stable-window"])
+	if err != nil {
+		return nil
+	}
+	if stableWindow.Truncate(time.Second) != stableWindow {
+		return fmt.Errorf("The stable-window %s needs to be specified with at most second precision.", stableWindow)
+	}
+	panicPercentage, err := strconv.ParseFloat(strings.TrimSuffix(config["panic-window-percentage"], "%"), 64)
+	if err != nil {
+		return nil
+	}
+	panicWindow := time.Duration(float64(stableWindow) * panicPercentage / 100)
+	if panicWindow < minPanicWindow {
+		minPercentage := float64(minPanicWindow) / float64(stableWindow) * 100
+		return fmt.Errorf("The panic window of %s (%v%% of the stable-window %s) needs to be at least %s, "+
+			"so the panic-window-percentage needs to be in [%v, 100].", panicWindow, panicPercentage, stableWindow,
+			minPanicWindow, minPercentage)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"fmt"
+	"testing"
+
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestAutoscalerData(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		autoscalerCMDFlags AutoscalerFlags
+		expectedData       map[string]string
+		expectedError      error
+	}{{
+		name: "Normalized values",
+		autoscalerCMDFlags: AutoscalerFlags{
+			EnableScaleToZero:     "False",
+			StableWindow:          "2m",
+			PanicWindowPercentage: "20%",
+			TargetUtilization:     "80",
+			MaxScale:              "10",
+			AllowZeroInitialScale: "true",
+		},
+		expectedData: map[string]string{
+			"allow-zero-initial-scale":                "true",
+			"enable-scale-to-zero":                    "false",
+			"stable-window":                           "2m0s",
+			"panic-window-percentage":                 "20",
+			"container-concurrency-target-percentage": "80",
+			"max-scale":                               "10",
+		},
+	}, {
+		name:               "No setting",
+		autoscalerCMDFlags: AutoscalerFlags{},
+		expectedError:      fmt.Errorf("You need to specify at least one setting of the autoscaler."),
+	}, {
+		name:               "Invalid duration",
+		autoscalerCMDFlags: AutoscalerFlags{ScaleToZeroGracePeriod: "30"},
+		expectedError:      fmt.Errorf("The value '30' of scale-to-zero-grace-period is invalid: expected a duration like 30s or 1m."),
+	}, {
+		name:               "Invalid percentage",
+		autoscalerCMDFlags: AutoscalerFlags{TargetUtilization: "high"},
+		expectedError:      fmt.Errorf("The value 'high' of container-concurrency-target-percentage is invalid: expected a percentage like 70 or 70%%."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := autoscalerData(tt.autoscalerCMDFlags)
+			if tt.expectedError == nil {
+				testingUtil.AssertEqual(t, err, nil)
+				testingUtil.AssertDeepEqual(t, data, tt.expectedData)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			}
+		})
+	}
+}
+
+func TestAutoscalerMutator(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		data           map[string]string
+		version        string
+		config         base.ConfigMapData
+		expectedConfig base.ConfigMapData
+		expectedError  error
+	}{{
+		name: "New keys",
+		data: map[string]string{"min-scale": "1", "max-scale": "10"},
+		expectedConfig: base.ConfigMapData{
+			"autoscaler": {"min-scale": "1", "max-scale": "10"},
+		},
+	}, {
+		name: "Keys added to the existing ConfigMap with prefix",
+		data: map[string]string{"min-scale": "2"},
+		config: base.ConfigMapData{
+			"config-autoscaler": {"max-scale": "5"},
+		},
+		expectedConfig: base.ConfigMapData{
+			"config-autoscaler": {"min-scale": "2", "max-scale": "5"},
+		},
+	}, {
+		name: "Min scale greater than the max scale in the custom resource",
+		data: map[string]string{"min-scale": "6"},
+		config: base.ConfigMapData{
+			"autoscaler": {"max-scale": "5"},
+		},
+		expectedError: fmt.Errorf("The min-scale 6 cannot be greater than the max-scale 5."),
+	}, {
+		name:          "Initial scale greater than the max scale",
+		data:          map[string]string{"initial-scale": "3", "max-scale": "2"},
+		expectedError: fmt.Errorf("The initial-scale 3 cannot be greater than the max-scale 2."),
+	}, {
+		name:          "Initial scale of zero not allowed",
+		data:          map[string]string{"initial-scale": "0"},
+		expectedError: fmt.Errorf("The initial-scale 0 needs allow-zero-initial-scale to be true, please use --allow-zero-initial-scale."),
+	}, {
+		name: "Initial scale of zero allowed together",
+		data: map[string]string{"initial-scale": "0", "allow-zero-initial-scale": "true"},
+		expectedConfig: base.ConfigMapData{
+			"autoscaler": {"initial-scale": "0", "allow-zero-initial-scale": "true"},
+		},
+	}, {
+		name: "Initial scale of zero allowed in the custom resource",
+		data: map[string]string{"initial-scale": "0"},
+		config: base.ConfigMapData{
+			"autoscaler": {"allow-zero-initial-scale": "True"},
+		},
+		expectedConfig: base.ConfigMapData{
+			"autoscaler": {"initial-scale": "0", "allow-zero-initial-scale": "True"},
+		},
+	}, {
+		name: "Max scale greater than the max scale limit in the custom resource",
+		data: map[string]string{"max-scale": "20"},
+		config: base.ConfigMapData{
+			"autoscaler": {"max-scale-limit": "10"},
+		},
+		expectedError: fmt.Errorf("The max-scale 20 cannot be greater than the max-scale-limit 10."),
+	}, {
+		name: "Max scale within the max scale limit",
+		data: map[string]string{"max-scale": "10", "max-scale-limit": "10"},
+		expectedConfig: base.ConfigMapData{
+			"autoscaler": {"max-scale": "10", "max-scale-limit": "10"},
+		},
+	}, {
+		name:          "Stable window with sub-second precision",
+		data:          map[string]string{"stable-window": "6.5s"},
+		expectedError: fmt.Errorf("The stable-window 6.5s needs to be specified with at most second precision."),
+	}, {
+		name: "Panic window too short for the stable window",
+		data: map[string]string{"stable-window": "6s"},
+		expectedError: fmt.Errorf("The panic window of 600ms (10%% of the stable-window 6s) needs to be at least 1s, " +
+			"so the panic-window-percentage needs to be in [16.666666666666664, 100]."),
+	}, {
+		name: "Percentage out of range",
+		data: map[string]string{"panic-threshold-percentage": "50"},
+		expectedError: fmt.Errorf("Invalid value '50' of the key 'panic-threshold-percentage' in the ConfigMap " +
+			"'config-autoscaler': expected at least 110."),
+	}, {
+		name:          "Stable window out of range in the version of the spec",
+		data:          map[string]string{"stable-window": "2h0m0s"},
+		version:       "1.11.0",
+		expectedError: fmt.Errorf("Invalid value '2h0m0s' of the key 'stable-window' in the ConfigMap 'config-autoscaler': expected at most 1h."),
+	}, {
+		name:    "Keys not validated in a version unknown to the schema",
+		data:    map[string]string{"stable-window": "2h0m0s"},
+		version: "1.30.0",
+		expectedConfig: base.ConfigMapData{
+			"autoscaler": {"stable-window": "2h0m0s"},
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1beta1.KnativeServingSpec{}
+			spec.Config, spec.Version = tt.config, tt.version
+			err := common.NewKnativeServingSpec(&spec).Mutate(autoscalerMutator(tt.data))
+			if tt.expectedError == nil {
+				testingUtil.AssertEqual(t, err, nil)
+				testingUtil.AssertDeepEqual(t, spec.Config, tt.expectedConfig)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			}
+		})
+	}
+}
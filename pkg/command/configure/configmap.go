@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -25,31 +24,27 @@ import (
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
 
-//go:embed overlay/ks_cm_base.yaml
-var servingCMOverlay string
-
-//go:embed overlay/ke_cm_base.yaml
-var eventingCMOverlay string
-
-var cmsCMDFlags common.CMsFlags
-
 // newConfigmapsCommand represents the configure commands to update the ConfigMaps in Knative Serving or Eventing
-func newConfigmapsCommand(p *pkg.OperatorParams) *cobra.Command {
+func newConfigmapsCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var cmsCMDFlags common.CMsFlags
 	var configureCMsCmd = &cobra.Command{
 		Use:   "configmaps",
 		Short: "Configure the configmap for Knative Serving and Eventing deployments",
 		Example: `
   # Configure the CM for Knative Serving and Eventing
-  kn operation configure configmaps --component eventing --cmName eventing-controller --key key --value value --namespace knative-eventing`,
+  kn operation configure configmaps --component eventing --cmName eventing-controller --key key --value value --namespace knative-eventing
+  # Configure several keys of the CM for Knative Serving in a single update
+  kn operation configure configmaps --component serving --cmName config-autoscaler --set enable-scale-to-zero=false --set max-scale=10 --namespace knative-serving
+  # Configure the keys of the CM for Knative Serving from a file of key=value lines
+  kn operation configure configmaps --component serving --cmName config-autoscaler --from-env-file autoscaler.env --namespace knative-serving
+  # Configure a key unknown to the schema of the CM, e.g. for a newer Knative version
+  kn operation configure configmaps --component serving --cmName config-network --key new-key --value value --namespace knative-serving --allow-unknown`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateCMsFlags(cmsCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureCMs(cmsCMDFlags, p)
+			err := ConfigureConfigMaps(cmsCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(cmsCMDFlags.Component, cmsCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified ConfigMap has been configured in the namespace '%s'.\n",
 				cmsCMDFlags.Namespace)
@@ -62,15 +57,18 @@ func newConfigmapsCommand(p *pkg.OperatorParams) *cobra.Command {
 	configureCMsCmd.Flags().StringVar(&cmsCMDFlags.CMName, "cmName", "", "The flag to specify the configmap name")
 	configureCMsCmd.Flags().StringVarP(&cmsCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
 	configureCMsCmd.Flags().StringVarP(&cmsCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+	configureCMsCmd.Flags().BoolVar(&cmsCMDFlags.AllowUnknown, "allow-unknown", false,
+		"Apply the keys and the values unknown to the schema of the ConfigMap with a warning")
+	common.AddKeyValueSourceFlags(configureCMsCmd.Flags(), &cmsCMDFlags.KeyValueSources)
 
 	return configureCMsCmd
 }
 
 func validateCMsFlags(cmsCMDFlags common.CMsFlags) error {
-	if cmsCMDFlags.Key == "" {
+	if cmsCMDFlags.Key == "" && cmsCMDFlags.KeyValueSources.IsEmpty() {
 		return fmt.Errorf("You need to specify the key in the ConfigMap data.")
 	}
-	if cmsCMDFlags.Value == "" {
+	if cmsCMDFlags.Key != "" && cmsCMDFlags.Value == "" {
 		return fmt.Errorf("You need to specify the value in the ConfigMap data.")
 	}
 	if cmsCMDFlags.CMName == "" {
@@ -85,58 +83,27 @@ func validateCMsFlags(cmsCMDFlags common.CMsFlags) error {
 	return nil
 }
 
-func configureCMs(cmsCMDFlags common.CMsFlags, p *pkg.OperatorParams) error {
+// ConfigureConfigMaps sets the data of a ConfigMap of the Knative component
+func ConfigureConfigMaps(cmsCMDFlags common.CMsFlags, p *pkg.OperatorParams) error {
+	if err := validateCMsFlags(cmsCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(cmsCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, cmsCMDFlags.Namespace, p)
+	pairs, err := cmsCMDFlags.Pairs(cmsCMDFlags.Key, cmsCMDFlags.Value)
 	if err != nil {
 		return err
 	}
-
-	overlayContent := getOverlayYamlContentCM(cmsCMDFlags)
-	valuesYaml := getYamlValuesContentCMs(cmsCMDFlags)
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+	if err := common.CheckConfigMapData(component, cmsCMDFlags.Namespace, cmsCMDFlags.CMName, pairs,
+		cmsCMDFlags.AllowUnknown, p); err != nil {
 		return err
 	}
-	return nil
-}
-
-func getOverlayYamlContentCM(cmsCMDFlags common.CMsFlags) string {
-	baseOverlayContent := servingCMOverlay
-	if strings.EqualFold(cmsCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingCMOverlay
+	mutators := make([]common.SpecMutator, 0, len(pairs))
+	for _, pair := range pairs {
+		mutators = append(mutators, common.SetConfigMapData(cmsCMDFlags.CMName, pair.Key, pair.Value))
 	}
-	resourceContent := getCMConfiguration(cmsCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
-}
-
-func getCMConfiguration(cmsCMDFlags common.CMsFlags) string {
-	resourceArray := []string{}
-	tag := fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	cmName := fmt.Sprintf("%s%s:", common.Spaces(4), cmsCMDFlags.CMName)
-	resourceArray = append(resourceArray, cmName)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(6), cmsCMDFlags.Key, "#@ data.values.value")
-	resourceArray = append(resourceArray, keyValueField)
-
-	return strings.Join(resourceArray, "\n")
-}
-
-func getYamlValuesContentCMs(cmsCMDFlags common.CMsFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-	namespace := fmt.Sprintf("namespace: %s", cmsCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-	value := fmt.Sprintf("value: %s", cmsCMDFlags.Value)
-	contentArray = append(contentArray, value)
-	return strings.Join(contentArray, "\n")
+	return common.ApplyKnativeCR(component, cmsCMDFlags.Namespace, p, mutators...)
 }
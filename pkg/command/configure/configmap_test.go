@@ -104,111 +104,3 @@ func TestValidateCMsFlags(t *testing.T) {
 		})
 	}
 }
-
-func TestGetOverlayYamlContentCM(t *testing.T) {
-	for _, tt := range []struct {
-		name           string
-		cmsCMDFlags    common.CMsFlags
-		expectedResult string
-	}{{
-		name: "Knative Eventing",
-		cmsCMDFlags: common.CMsFlags{
-			Key:       "test-key",
-			Value:     "test-value",
-			Component: "eventing",
-			Namespace: "test-eventing",
-			CMName:    "eventing-controller",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  config:
-
-    #@overlay/match missing_ok=True
-    eventing-controller:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
-	}, {
-		name: "Knative Serving",
-		cmsCMDFlags: common.CMsFlags{
-			Key:       "test-key",
-			Value:     "test-value",
-			Component: "serving",
-			Namespace: "test-serving",
-			CMName:    "network",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  config:
-
-    #@overlay/match missing_ok=True
-    network:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentCM(tt.cmsCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentCMs(t *testing.T) {
-	for _, tt := range []struct {
-		name           string
-		cmsCMDFlags    common.CMsFlags
-		expectedResult string
-	}{{
-		name: "Knative Eventing",
-		cmsCMDFlags: common.CMsFlags{
-			Key:       "test-key",
-			Value:     "test-value",
-			Component: "eventing",
-			Namespace: "test-eventing",
-			CMName:    "network",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-value: test-value`,
-	}, {
-		name: "Knative Serving",
-		cmsCMDFlags: common.CMsFlags{
-			Key:       "test-key",
-			Value:     "test-value",
-			Component: "serving",
-			Namespace: "test-serving",
-			CMName:    "network",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-value: test-value`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentCMs(tt.cmsCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
@@ -15,13 +15,36 @@
 package configure
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
 
+// appliedResource is the Knative custom resource changed by a configure command, which is waited for with --wait
+type appliedResource struct {
+	Component string
+	Namespace string
+}
+
+// set records the component and the namespace of the custom resource, once the configure command has changed it
+func (resource *appliedResource) set(component, namespace string) {
+	resource.Component = common.ServingComponent
+	if strings.EqualFold(component, common.EventingComponent) {
+		resource.Component = common.EventingComponent
+	}
+	resource.Namespace = namespace
+}
+
 // NewConfigureCommand represents the configure commands for Knative Serving or eventing
 func NewConfigureCommand(p *pkg.OperatorParams) *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+	var applied appliedResource
 	var configureCmd = &cobra.Command{
 		Use:   "configure",
 		Short: "Configure the Knative Serving or Eventing",
@@ -30,19 +53,54 @@ func NewConfigureCommand(p *pkg.OperatorParams) *cobra.Command {
   kn operation configure resources --component serving --deployName activator --requestMemory 999M --namespace knative-serving
   # Configure the tolerations for Knative Serving and Eventing deployments
   kn operation configure tolerations --component eventing --deployName eventing-controller --key example-key --operator Exists --effect NoSchedule --namespace knative-eventing`,
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			// Nothing is waited for, if the command has not changed any custom resource
+			if !wait || applied.Component == "" {
+				return nil
+			}
+			if err := common.WaitForKnativeCRReady(applied.Component, applied.Namespace, timeout, p); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Knative %s is ready in the namespace '%s'.\n", applied.Component, applied.Namespace)
+			return nil
+		},
 	}
 
-	configureCmd.AddCommand(newResourcesCommand(p))
-	configureCmd.AddCommand(newTolerationsCommand(p))
-	configureCmd.AddCommand(newHACommand(p))
-	configureCmd.AddCommand(newConfigmapsCommand(p))
-	configureCmd.AddCommand(newDeploymentLabelCommand(p))
-	configureCmd.AddCommand(newImageCommand(p))
-	configureCmd.AddCommand(newEnvVarCommand(p))
-	configureCmd.AddCommand(newManifestsCommand(p))
-	configureCmd.AddCommand(newAnnotationCommand(p))
-	configureCmd.AddCommand(newNodeSelectorCommand(p))
-	configureCmd.AddCommand(newSelectorCommand(p))
+	configureCmd.PersistentFlags().BoolVar(&wait, "wait", false, "The flag to wait until the Knative component has reconciled the change and is ready")
+	configureCmd.PersistentFlags().DurationVar(&timeout, "timeout", common.DefaultTimeout, "The maximum time to wait for the Knative component to be ready")
+
+	configureCmd.AddCommand(newResourcesCommand(p, &applied))
+	configureCmd.AddCommand(newTolerationsCommand(p, &applied))
+	configureCmd.AddCommand(newHACommand(p, &applied))
+	configureCmd.AddCommand(newConfigmapsCommand(p, &applied))
+	configureCmd.AddCommand(newDeploymentLabelCommand(p, &applied))
+	configureCmd.AddCommand(newImageCommand(p, &applied))
+	configureCmd.AddCommand(newEnvVarCommand(p, &applied))
+	configureCmd.AddCommand(newManifestsCommand(p, &applied))
+	configureCmd.AddCommand(newAnnotationCommand(p, &applied))
+	configureCmd.AddCommand(newNodeSelectorCommand(p, &applied))
+	configureCmd.AddCommand(newSelectorCommand(p, &applied))
+	configureCmd.AddCommand(newDomainCommand(p, &applied))
+	configureCmd.AddCommand(newObservabilityCommand(p, &applied))
+	configureCmd.AddCommand(newAutoscalerCommand(p, &applied))
+	configureCmd.AddCommand(newProfileCommand(p, &applied))
 
 	return configureCmd
 }
+
+// keyValueMutators builds the mutator for each key-value pair given by the flags, so that all the pairs
+// are set in a single update of the Knative custom resource
+func keyValueMutators(keyValueFlags common.KeyValueFlags, mutator func(common.KeyValueFlags) common.SpecMutator) ([]common.SpecMutator, error) {
+	pairs, err := keyValueFlags.Pairs(keyValueFlags.Key, keyValueFlags.Value)
+	if err != nil {
+		return nil, err
+	}
+	mutators := make([]common.SpecMutator, 0, len(pairs))
+	for _, pair := range pairs {
+		pairFlags := keyValueFlags
+		pairFlags.Key, pairFlags.Value = pair.Key, pair.Value
+		mutators = append(mutators, mutator(pairFlags))
+	}
+	return mutators, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestCommandsDoNotShareFlags(t *testing.T) {
+	p := &pkg.OperatorParams{}
+	first := NewConfigureCommand(p)
+	second := NewConfigureCommand(p)
+
+	for _, args := range [][]string{
+		{"resources", "--container", "activator"},
+		{"nodeSelectors", "--key", "disktype"},
+	} {
+		cmd, _, err := first.Find(args[:1])
+		testingUtil.AssertEqual(t, err, nil)
+		testingUtil.AssertEqual(t, cmd.Flags().Parse(args[1:]), nil)
+	}
+
+	for _, name := range []string{"resources", "nodeSelectors", "selectors"} {
+		cmd, _, err := second.Find([]string{name})
+		testingUtil.AssertEqual(t, err, nil)
+		for _, flag := range []string{"container", "key"} {
+			if f := cmd.Flags().Lookup(flag); f != nil {
+				testingUtil.AssertEqual(t, f.Value.String(), "")
+			}
+		}
+	}
+
+	// The selectors command does not share the flags with the nodeSelectors command
+	cmd, _, err := first.Find([]string{"selectors"})
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, cmd.Flags().Lookup("key").Value.String(), "")
+}
+
+func TestKeyValueMutators(t *testing.T) {
+	flags := common.KeyValueFlags{
+		Key:        "disktype",
+		Value:      "ssd",
+		DeployName: "activator",
+		KeyValueSources: common.KeyValueSources{
+			Set: []string{"zone=east", "disktype=nvme"},
+		},
+	}
+	mutators, err := keyValueMutators(flags, nodeSelectorMutator)
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, len(mutators), 3)
+
+	spec := v1beta1.KnativeServingSpec{}
+	testingUtil.AssertEqual(t, common.NewKnativeServingSpec(&spec).Mutate(mutators...), nil)
+	testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, []base.WorkloadOverride{{
+		Name:         "activator",
+		NodeSelector: map[string]string{"disktype": "nvme", "zone": "east"},
+	}})
+
+	flags.Set = []string{"zone"}
+	_, err = keyValueMutators(flags, nodeSelectorMutator)
+	testingUtil.AssertEqual(t, err.Error(), "Invalid value 'zone' of --set: the pair needs to be in the format key=value")
+}
+
+func TestValidateKeyValueSources(t *testing.T) {
+	sources := common.KeyValueSources{Set: []string{"max-scale=10"}}
+	testingUtil.AssertEqual(t, validateCMsFlags(common.CMsFlags{
+		CMName:          "config-autoscaler",
+		Namespace:       "knative-serving",
+		KeyValueSources: sources,
+	}), nil)
+	testingUtil.AssertEqual(t, validateEnvVarsFlags(EnvVarFlags{
+		DeployName:      "activator",
+		ContainerName:   "activator",
+		Namespace:       "knative-serving",
+		KeyValueSources: sources,
+	}), nil)
+	testingUtil.AssertEqual(t, validateNodeSelectorFlags(common.KeyValueFlags{
+		Component:       "serving",
+		DeployName:      "activator",
+		Namespace:       "knative-serving",
+		KeyValueSources: sources,
+	}), nil)
+}
+
+func TestAppliedResourceSet(t *testing.T) {
+	var applied appliedResource
+	applied.set("eventing", "test-eventing")
+	testingUtil.AssertDeepEqual(t, applied, appliedResource{Component: common.EventingComponent, Namespace: "test-eventing"})
+	applied.set("", "test-serving")
+	testingUtil.AssertDeepEqual(t, applied, appliedResource{Component: common.ServingComponent, Namespace: "test-serving"})
+}
+
+func TestWaitWithoutAppliedResource(t *testing.T) {
+	cmd := NewConfigureCommand(&pkg.OperatorParams{})
+	testingUtil.AssertEqual(t, cmd.PersistentFlags().Set("wait", "true"), nil)
+	// Nothing is waited for, because no custom resource has been changed
+	testingUtil.AssertEqual(t, cmd.PersistentPostRunE(cmd, nil), nil)
+}
@@ -0,0 +1,301 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// DefaultMagicDNSSuffix is the wildcard DNS service resolving <IP>.sslip.io to the IP
+const DefaultMagicDNSSuffix = "sslip.io"
+
+type DomainFlags struct {
+	Domain         string
+	Selector       []string
+	Template       string
+	ExternalScheme string
+	MagicDNS       bool
+	MagicDNSSuffix string
+	IstioNamespace string
+	Namespace      string
+}
+
+// ingressService is a service exposing an ingress supported by Knative Serving
+type ingressService struct {
+	namespace string
+	name      string
+}
+
+// ingressServices returns the services exposing the ingresses supported by Knative Serving
+func ingressServices(servingNamespace, istioNamespace string) []ingressService {
+	if istioNamespace == "" {
+		istioNamespace = common.DefaultIstioNamespace
+	}
+	return []ingressService{
+		{namespace: servingNamespace, name: "kourier"},
+		{namespace: istioNamespace, name: "istio-ingressgateway"},
+		{namespace: "contour-external", name: "envoy"},
+	}
+}
+
+// newDomainCommand represents the configure command to set the domain and the DNS of Knative Serving
+func newDomainCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var domainCMDFlags DomainFlags
+	var configureDomainCmd = &cobra.Command{
+		Use:   "domain",
+		Short: "Configure the domain, the domain template and the magic DNS for Knative Serving",
+		Example: `
+  # Configure the default domain for Knative Serving
+  kn operation configure domain --domain example.com --namespace knative-serving
+  # Configure the domain of the Knative services labeled with app=prod
+  kn operation configure domain --domain prod.example.com --selector app=prod --namespace knative-serving
+  # Configure the domain template and the default external scheme
+  kn operation configure domain --template '{{.Name}}-{{.Namespace}}.{{.Domain}}' --external-scheme https --namespace knative-serving
+  # Configure the magic DNS of sslip.io with the IP of the ingress
+  kn operation configure domain --magic-dns --namespace knative-serving`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			domain, err := ConfigureDomain(domainCMDFlags, p)
+			if err != nil {
+				return err
+			}
+			applied.set(common.ServingComponent, domainCMDFlags.Namespace)
+
+			if domain != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "The domain '%s' has been configured in the namespace '%s'.\n",
+					domain, domainCMDFlags.Namespace)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "The domain configuration has been updated in the namespace '%s'.\n",
+					domainCMDFlags.Namespace)
+			}
+			return nil
+		},
+	}
+
+	configureDomainCmd.Flags().StringVar(&domainCMDFlags.Domain, "domain", "", "The domain of the Knative services")
+	configureDomainCmd.Flags().StringArrayVar(&domainCMDFlags.Selector, "selector", []string{},
+		"The label as key=value of the Knative services using the domain, this flag can be repeated")
+	configureDomainCmd.Flags().StringVar(&domainCMDFlags.Template, "template", "",
+		"The template of the domain names, e.g. {{.Name}}.{{.Namespace}}.{{.Domain}}")
+	configureDomainCmd.Flags().StringVar(&domainCMDFlags.ExternalScheme, "external-scheme", "",
+		"The default scheme of the URLs of the Knative services: http or https")
+	configureDomainCmd.Flags().BoolVar(&domainCMDFlags.MagicDNS, "magic-dns", false,
+		"The flag to use the magic DNS, which resolves the domain made of the IP of the ingress")
+	configureDomainCmd.Flags().StringVar(&domainCMDFlags.MagicDNSSuffix, "magic-dns-suffix", DefaultMagicDNSSuffix,
+		"The suffix of the magic DNS domain")
+	configureDomainCmd.Flags().StringVar(&domainCMDFlags.IstioNamespace, "istio-namespace", common.DefaultIstioNamespace,
+		"The namespace of Istio, whose ingress gateway is looked up for the magic DNS")
+	configureDomainCmd.Flags().StringVarP(&domainCMDFlags.Namespace, "namespace", "n", common.DefaultKnativeServingNamespace,
+		"The namespace of Knative Serving")
+
+	return configureDomainCmd
+}
+
+func validateDomainFlags(domainCMDFlags DomainFlags) error {
+	if domainCMDFlags.Domain == "" && domainCMDFlags.Template == "" && domainCMDFlags.ExternalScheme == "" && !domainCMDFlags.MagicDNS {
+		return fmt.Errorf("You need to specify the domain, the template, the external scheme or the magic DNS.")
+	}
+	if domainCMDFlags.Domain != "" && domainCMDFlags.MagicDNS {
+		return fmt.Errorf("You can specify either the domain or the magic DNS.")
+	}
+	if domainCMDFlags.Domain != "" {
+		if errs := validation.IsDNS1123Subdomain(domainCMDFlags.Domain); len(errs) > 0 {
+			return fmt.Errorf("The domain '%s' is invalid: %s.", domainCMDFlags.Domain, strings.Join(errs, "; "))
+		}
+	}
+	if len(domainCMDFlags.Selector) > 0 && domainCMDFlags.Domain == "" {
+		return fmt.Errorf("You need to specify the domain of the selector.")
+	}
+	if _, err := parseSelector(domainCMDFlags.Selector); err != nil {
+		return err
+	}
+	if domainCMDFlags.Template != "" {
+		if err := validateDomainTemplate(domainCMDFlags.Template); err != nil {
+			return err
+		}
+	}
+	if domainCMDFlags.ExternalScheme != "" && domainCMDFlags.ExternalScheme != "http" && domainCMDFlags.ExternalScheme != "https" {
+		return fmt.Errorf("You need to specify the external scheme: http or https.")
+	}
+	if domainCMDFlags.Namespace == "" {
+		return fmt.Errorf("You need to specify the namespace.")
+	}
+	return nil
+}
+
+// parseSelector parses the labels given as key=value
+func parseSelector(selector []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, item := range selector {
+		key, value, found := strings.Cut(item, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("The selector '%s' needs to be in the format key=value.", item)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// validateDomainTemplate checks that the template produces a valid domain name, which is unique per Knative service
+// by containing its name, its namespace and the domain
+func validateDomainTemplate(domainTemplate string) error {
+	tmpl, err := template.New("domain-template").Parse(domainTemplate)
+	if err != nil {
+		return fmt.Errorf("The template is invalid: %v.", err)
+	}
+
+	buf := bytes.Buffer{}
+	err = tmpl.Execute(&buf, map[string]interface{}{
+		"Name":        "foo",
+		"Namespace":   "bar",
+		"Domain":      "baz.com",
+		"Annotations": map[string]string{},
+		"Labels":      map[string]string{},
+	})
+	if err != nil {
+		return fmt.Errorf("The template is invalid: %v.", err)
+	}
+	domainName := buf.String()
+	if errs := validation.IsDNS1123Subdomain(domainName); len(errs) > 0 {
+		return fmt.Errorf("The template produces the invalid domain name '%s': %s.", domainName, strings.Join(errs, "; "))
+	}
+	for _, value := range []string{"foo", "bar", "baz.com"} {
+		if !strings.Contains(domainName, value) {
+			return fmt.Errorf("The template needs to contain {{.Name}}, {{.Namespace}} and {{.Domain}}.")
+		}
+	}
+	return nil
+}
+
+// ConfigureDomain sets the domain, the domain template and the external scheme of Knative Serving in a single update.
+// It returns the domain, which is made of the IP of the ingress for the magic DNS.
+func ConfigureDomain(domainCMDFlags DomainFlags, p *pkg.OperatorParams) (string, error) {
+	if err := validateDomainFlags(domainCMDFlags); err != nil {
+		return "", err
+	}
+
+	domain := domainCMDFlags.Domain
+	if domainCMDFlags.MagicDNS {
+		client, err := p.NewKubeClient()
+		if err != nil {
+			return "", fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+		}
+		ip, err := findIngressIP(p.Context(), client, ingressServices(domainCMDFlags.Namespace, domainCMDFlags.IstioNamespace))
+		if err != nil {
+			return "", err
+		}
+		domain = magicDNSDomain(ip, domainCMDFlags.MagicDNSSuffix)
+	}
+
+	mutators := []common.SpecMutator{}
+	if domain != "" {
+		labels, _ := parseSelector(domainCMDFlags.Selector)
+		mutator, err := domainMutator(domain, labels)
+		if err != nil {
+			return "", err
+		}
+		mutators = append(mutators, mutator)
+	}
+	if domainCMDFlags.Template != "" {
+		mutators = append(mutators, common.SetConfigMapData("network", "domain-template", domainCMDFlags.Template))
+	}
+	if domainCMDFlags.ExternalScheme != "" {
+		mutators = append(mutators, common.SetConfigMapData("network", "default-external-scheme", domainCMDFlags.ExternalScheme))
+	}
+	return domain, common.ApplyKnativeCR(common.ServingComponent, domainCMDFlags.Namespace, p, mutators...)
+}
+
+// domainMutator sets the domain in config-domain, which is kept under the name config-domain if the custom resource
+// already sets it with the prefix. A domain without selector becomes the default domain, replacing the previous
+// default one.
+func domainMutator(domain string, labels map[string]string) (common.SpecMutator, error) {
+	value := ""
+	if len(labels) > 0 {
+		content, err := yaml.Marshal(map[string]interface{}{"selector": labels})
+		if err != nil {
+			return nil, err
+		}
+		value = string(content)
+	}
+
+	return func(spec *common.KnativeSpec) error {
+		if spec.Serving == nil {
+			return fmt.Errorf("The domain can only be configured for Knative Serving.")
+		}
+		if value == "" {
+			for _, cmName := range []string{"domain", "config-domain"} {
+				for key, data := range spec.Config[cmName] {
+					if key != domain && key != "_example" && strings.TrimSpace(data) == "" {
+						delete(spec.Config[cmName], key)
+					}
+				}
+			}
+		}
+		cmName := "domain"
+		if _, ok := spec.Config["config-domain"]; ok {
+			cmName = "config-domain"
+		}
+		return common.SetConfigMapData(cmName, domain, value)(spec)
+	}, nil
+}
+
+// findIngressIP finds the external IP of the load balancer of the first ingress service found
+func findIngressIP(ctx context.Context, client kubernetes.Interface, services []ingressService) (string, error) {
+	for _, ingress := range services {
+		svc, err := client.CoreV1().Services(ingress.namespace).Get(ctx, ingress.name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if ip := loadBalancerIP(svc); ip != "" {
+			return ip, nil
+		}
+	}
+	return "", fmt.Errorf("The external IP of the ingress is not found for the magic DNS, please use --domain instead.")
+}
+
+func loadBalancerIP(svc *corev1.Service) string {
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return ""
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+	}
+	return ""
+}
+
+// magicDNSDomain returns the domain resolved to the IP by the magic DNS. The colons of an IPv6 address are
+// written as dashes.
+func magicDNSDomain(ip, suffix string) string {
+	if suffix == "" {
+		suffix = DefaultMagicDNSSuffix
+	}
+	return fmt.Sprintf("%s.%s", strings.ReplaceAll(ip, ":", "-"), strings.TrimPrefix(suffix, "."))
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestValidateDomainFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		domainCMDFlags DomainFlags
+		expectedResult error
+	}{{
+		name: "Domain with selector",
+		domainCMDFlags: DomainFlags{
+			Domain:    "prod.example.com",
+			Selector:  []string{"app=prod"},
+			Namespace: "knative-serving",
+		},
+	}, {
+		name: "Template and external scheme",
+		domainCMDFlags: DomainFlags{
+			Template:       "{{.Name}}-{{.Namespace}}.{{.Domain}}",
+			ExternalScheme: "https",
+			Namespace:      "knative-serving",
+		},
+	}, {
+		name:           "Nothing to configure",
+		domainCMDFlags: DomainFlags{Namespace: "knative-serving"},
+		expectedResult: fmt.Errorf("You need to specify the domain, the template, the external scheme or the magic DNS."),
+	}, {
+		name: "Domain and magic DNS",
+		domainCMDFlags: DomainFlags{
+			Domain:    "example.com",
+			MagicDNS:  true,
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You can specify either the domain or the magic DNS."),
+	}, {
+		name: "Selector without domain",
+		domainCMDFlags: DomainFlags{
+			Selector:  []string{"app=prod"},
+			MagicDNS:  true,
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the domain of the selector."),
+	}, {
+		name: "Invalid selector",
+		domainCMDFlags: DomainFlags{
+			Domain:    "example.com",
+			Selector:  []string{"app"},
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("The selector 'app' needs to be in the format key=value."),
+	}, {
+		name: "Template without the namespace",
+		domainCMDFlags: DomainFlags{
+			Template:  "{{.Name}}.{{.Domain}}",
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("The template needs to contain {{.Name}}, {{.Namespace}} and {{.Domain}}."),
+	}, {
+		name: "Template producing an invalid domain name",
+		domainCMDFlags: DomainFlags{
+			Template:  "{{.Name}}_{{.Namespace}}.{{.Domain}}",
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("The template produces the invalid domain name 'foo_bar.baz.com': a lowercase RFC 1123 subdomain " +
+			"must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character " +
+			"(e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')."),
+	}, {
+		name: "Invalid external scheme",
+		domainCMDFlags: DomainFlags{
+			ExternalScheme: "ftp",
+			Namespace:      "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the external scheme: http or https."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateDomainFlags(tt.domainCMDFlags)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
+
+func TestDomainMutator(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		domain         string
+		labels         map[string]string
+		config         base.ConfigMapData
+		expectedConfig base.ConfigMapData
+	}{{
+		name:   "Default domain replacing the previous one",
+		domain: "example.com",
+		config: base.ConfigMapData{
+			"config-domain": {"old.example.com": "", "prod.example.com": "selector:\n  app: prod\n"},
+		},
+		expectedConfig: base.ConfigMapData{
+			"config-domain": {"example.com": "", "prod.example.com": "selector:\n  app: prod\n"},
+		},
+	}, {
+		name:   "Default domain without any config-domain",
+		domain: "example.com",
+		config: base.ConfigMapData{
+			"network": {"ingress-class": "kourier.ingress.networking.knative.dev"},
+		},
+		expectedConfig: base.ConfigMapData{
+			"network": {"ingress-class": "kourier.ingress.networking.knative.dev"},
+			"domain":  {"example.com": ""},
+		},
+	}, {
+		name:   "Domain with selector",
+		domain: "prod.example.com",
+		labels: map[string]string{"app": "prod"},
+		config: base.ConfigMapData{
+			"domain": {"example.com": ""},
+		},
+		expectedConfig: base.ConfigMapData{
+			"domain": {"example.com": "", "prod.example.com": "selector:\n  app: prod\n"},
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			mutator, err := domainMutator(tt.domain, tt.labels)
+			testingUtil.AssertEqual(t, err, nil)
+			spec := v1beta1.KnativeServingSpec{}
+			spec.Config = tt.config
+			err = common.NewKnativeServingSpec(&spec).Mutate(mutator)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.Config, tt.expectedConfig)
+		})
+	}
+}
+
+func TestIngressServices(t *testing.T) {
+	testingUtil.AssertDeepEqual(t, ingressServices("knative-serving", "custom-istio"), []ingressService{
+		{namespace: "knative-serving", name: "kourier"},
+		{namespace: "custom-istio", name: "istio-ingressgateway"},
+		{namespace: "contour-external", name: "envoy"},
+	})
+	testingUtil.AssertEqual(t, ingressServices("knative-serving", "")[1].namespace, "istio-system")
+}
+
+func TestMagicDNSDomain(t *testing.T) {
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+			Ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}, {IP: "10.0.0.1"}},
+		}},
+	}
+	testingUtil.AssertEqual(t, loadBalancerIP(svc), "10.0.0.1")
+	testingUtil.AssertEqual(t, loadBalancerIP(&corev1.Service{}), "")
+
+	testingUtil.AssertEqual(t, magicDNSDomain("10.0.0.1", ""), "10.0.0.1.sslip.io")
+	testingUtil.AssertEqual(t, magicDNSDomain("2001:db8::1", ".nip.io"), "2001-db8--1.nip.io")
+}
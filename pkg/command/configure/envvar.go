@@ -15,23 +15,18 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_envvar.yaml
-var servingEnvVarOverlay string
-
-//go:embed overlay/ke_envvar.yaml
-var eventingEnvVarOverlay string
-
 type EnvVarFlags struct {
 	EnvName       string
 	EnvValue      string
@@ -39,12 +34,12 @@ type EnvVarFlags struct {
 	Namespace     string
 	DeployName    string
 	ContainerName string
+	common.KeyValueSources
 }
 
-var envVarFlags EnvVarFlags
-
 // newEnvVarCommand represents the configure commands to configure the env vars for Knative Deployment resources
-func newEnvVarCommand(p *pkg.OperatorParams) *cobra.Command {
+func newEnvVarCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var envVarFlags EnvVarFlags
 	var configureImagesCmd = &cobra.Command{
 		Use:   "envvars",
 		Short: "Configure the env vars for Knative",
@@ -52,14 +47,11 @@ func newEnvVarCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the env vars for Knative
   kn operation configure envvars --component eventing --deployName eventing-controller --container eventing-controller --name key --value value --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateEnvVarsFlags(envVarFlags); err != nil {
-				return err
-			}
-
-			err := configureEnvVars(envVarFlags, p)
+			err := ConfigureEnvVars(envVarFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(envVarFlags.Component, envVarFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified images has been configured.\n")
 			return nil
@@ -72,15 +64,16 @@ func newEnvVarCommand(p *pkg.OperatorParams) *cobra.Command {
 	configureImagesCmd.Flags().StringVarP(&envVarFlags.Component, "component", "c", "", "The flag to specify the component name")
 	configureImagesCmd.Flags().StringVarP(&envVarFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
 	configureImagesCmd.Flags().StringVar(&envVarFlags.ContainerName, "container", "", "The name of the container")
+	common.AddKeyValueSourceFlags(configureImagesCmd.Flags(), &envVarFlags.KeyValueSources)
 
 	return configureImagesCmd
 }
 
 func validateEnvVarsFlags(envVarFlags EnvVarFlags) error {
-	if envVarFlags.EnvName == "" {
+	if envVarFlags.EnvName == "" && envVarFlags.KeyValueSources.IsEmpty() {
 		return fmt.Errorf("You need to specify the name for the environment variable.")
 	}
-	if envVarFlags.EnvValue == "" {
+	if envVarFlags.EnvName != "" && envVarFlags.EnvValue == "" {
 		return fmt.Errorf("You need to specify the value for the environment variable.")
 	}
 
@@ -101,50 +94,56 @@ func validateEnvVarsFlags(envVarFlags EnvVarFlags) error {
 	return nil
 }
 
-func configureEnvVars(envVarFlags EnvVarFlags, p *pkg.OperatorParams) error {
+// ConfigureEnvVars sets the environment variable for a container of a Knative deployment
+func ConfigureEnvVars(envVarFlags EnvVarFlags, p *pkg.OperatorParams) error {
+	if err := validateEnvVarsFlags(envVarFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(envVarFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, envVarFlags.Namespace, p)
-	if err != nil {
+	if err := common.CheckDeployment(component, envVarFlags.Namespace, envVarFlags.DeployName, envVarFlags.ContainerName, p); err != nil {
 		return err
 	}
-
-	overlayContent := getOverlayYamlContentEnvvar(envVarFlags)
-	valuesYaml := getYamlValuesContentEnvvars(envVarFlags)
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+	pairs, err := envVarFlags.Pairs(envVarFlags.EnvName, envVarFlags.EnvValue)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func getOverlayYamlContentEnvvar(envVarFlags EnvVarFlags) string {
-	baseOverlayContent := servingEnvVarOverlay
-	if strings.EqualFold(envVarFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingEnvVarOverlay
+	mutators := make([]common.SpecMutator, 0, len(pairs))
+	for _, pair := range pairs {
+		pairFlags := envVarFlags
+		pairFlags.EnvName, pairFlags.EnvValue = pair.Key, pair.Value
+		mutators = append(mutators, envVarMutator(pairFlags))
 	}
-	return baseOverlayContent
+	return common.ApplyKnativeCR(component, envVarFlags.Namespace, p, mutators...)
 }
 
-func getYamlValuesContentEnvvars(envVarFlags EnvVarFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-
-	namespace := fmt.Sprintf("namespace: %s", envVarFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-
-	deployName := fmt.Sprintf("deployName: %s", envVarFlags.DeployName)
-	contentArray = append(contentArray, deployName)
-
-	value := fmt.Sprintf("containerName: %s", envVarFlags.ContainerName)
-	contentArray = append(contentArray, value)
-
-	envName := fmt.Sprintf("envVarName: %s", envVarFlags.EnvName)
-	contentArray = append(contentArray, envName)
+// envVarMutator sets the environment variable for the container of the deployment
+func envVarMutator(envVarFlags EnvVarFlags) common.SpecMutator {
+	return common.UpdateDeploymentOverride(envVarFlags.DeployName, func(override *base.WorkloadOverride) error {
+		for i := range override.Env {
+			if override.Env[i].Container == envVarFlags.ContainerName {
+				override.Env[i].EnvVars = setEnvVar(override.Env[i].EnvVars, envVarFlags.EnvName, envVarFlags.EnvValue)
+				return nil
+			}
+		}
+		override.Env = append(override.Env, base.EnvRequirementsOverride{
+			Container: envVarFlags.ContainerName,
+			EnvVars:   setEnvVar(nil, envVarFlags.EnvName, envVarFlags.EnvValue),
+		})
+		return nil
+	})
+}
 
-	envValue := fmt.Sprintf("envVarValue: %s", envVarFlags.EnvValue)
-	contentArray = append(contentArray, envValue)
-	return strings.Join(contentArray, "\n")
+func setEnvVar(envVars []corev1.EnvVar, name, value string) []corev1.EnvVar {
+	for i := range envVars {
+		if envVars[i].Name == name {
+			envVars[i].Value = value
+			envVars[i].ValueFrom = nil
+			return envVars
+		}
+	}
+	return append(envVars, corev1.EnvVar{Name: name, Value: value})
 }
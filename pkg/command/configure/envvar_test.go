@@ -18,7 +18,12 @@ import (
 	"fmt"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateEnvVarsFlags(t *testing.T) {
@@ -121,136 +126,98 @@ func TestValidateEnvVarsFlags(t *testing.T) {
 	}
 }
 
-func TestGetOverlayYamlContentEnvvar(t *testing.T) {
+func TestEnvVarMutator(t *testing.T) {
 	for _, tt := range []struct {
 		name           string
 		envVarFlags    EnvVarFlags
-		expectedResult string
+		input          v1beta1.KnativeServingSpec
+		expectedResult []base.WorkloadOverride
 	}{{
-		name: "Knative Eventing",
+		name: "Add the environment variable to a new deployment override",
 		envVarFlags: EnvVarFlags{
-			EnvName:       "test-key",
-			EnvValue:      "test-value",
-			Component:     "eventing",
-			Namespace:     "test-eventing",
-			DeployName:    "eventing-controller",
-			ContainerName: "container",
+			EnvName:       "env",
+			EnvValue:      "value",
+			Component:     "serving",
+			Namespace:     "test-serving",
+			DeployName:    "controller",
+			ContainerName: "controller",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    env:
-    #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.containerName
-      #@overlay/match missing_ok=True
-      envVars:
-      #@overlay/match by="name",missing_ok=True
-      - name: #@ data.values.envVarName
-        #@overlay/match missing_ok=True
-        value: #@ data.values.envVarValue
-`,
+		input: v1beta1.KnativeServingSpec{},
+		expectedResult: []base.WorkloadOverride{{
+			Name: "controller",
+			Env: []base.EnvRequirementsOverride{{
+				Container: "controller",
+				EnvVars:   []corev1.EnvVar{{Name: "env", Value: "value"}},
+			}},
+		}},
 	}, {
-		name: "Knative Serving",
+		name: "Add the environment variable to the existing container",
 		envVarFlags: EnvVarFlags{
-			EnvName:       "test-key",
-			EnvValue:      "test-value",
+			EnvName:       "env",
+			EnvValue:      "value",
 			Component:     "serving",
 			Namespace:     "test-serving",
 			DeployName:    "controller",
-			ContainerName: "container",
+			ContainerName: "controller",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    env:
-    #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.containerName
-      #@overlay/match missing_ok=True
-      envVars:
-      #@overlay/match by="name",missing_ok=True
-      - name: #@ data.values.envVarName
-        #@overlay/match missing_ok=True
-        value: #@ data.values.envVarValue
-`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentEnvvar(tt.envVarFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentEnvvars(t *testing.T) {
-	for _, tt := range []struct {
-		name           string
-		envVarFlags    EnvVarFlags
-		expectedResult string
-	}{{
-		name: "Knative Eventing",
-		envVarFlags: EnvVarFlags{
-			EnvName:       "test-key",
-			EnvValue:      "test-value",
-			Component:     "eventing",
-			Namespace:     "test-eventing",
-			DeployName:    "eventing-controller",
-			ContainerName: "container",
+		input: v1beta1.KnativeServingSpec{
+			CommonSpec: base.CommonSpec{
+				DeploymentOverride: []base.WorkloadOverride{{
+					Name: "controller",
+					Env: []base.EnvRequirementsOverride{{
+						Container: "controller",
+						EnvVars:   []corev1.EnvVar{{Name: "env1", Value: "value1"}},
+					}},
+				}},
+			},
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-deployName: eventing-controller
-containerName: container
-envVarName: test-key
-envVarValue: test-value`,
+		expectedResult: []base.WorkloadOverride{{
+			Name: "controller",
+			Env: []base.EnvRequirementsOverride{{
+				Container: "controller",
+				EnvVars:   []corev1.EnvVar{{Name: "env1", Value: "value1"}, {Name: "env", Value: "value"}},
+			}},
+		}},
 	}, {
-		name: "Knative Serving",
+		name: "Set the value of the existing environment variable",
 		envVarFlags: EnvVarFlags{
-			EnvName:       "test-key",
-			EnvValue:      "test-value",
+			EnvName:       "env",
+			EnvValue:      "value",
 			Component:     "serving",
 			Namespace:     "test-serving",
 			DeployName:    "controller",
-			ContainerName: "container",
+			ContainerName: "sidecar",
+		},
+		input: v1beta1.KnativeServingSpec{
+			CommonSpec: base.CommonSpec{
+				DeploymentOverride: []base.WorkloadOverride{{
+					Name: "controller",
+					Env: []base.EnvRequirementsOverride{{
+						Container: "controller",
+						EnvVars:   []corev1.EnvVar{{Name: "env", Value: "value1"}},
+					}, {
+						Container: "sidecar",
+						EnvVars:   []corev1.EnvVar{{Name: "env", Value: "value1"}},
+					}},
+				}},
+			},
 		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-deployName: controller
-containerName: container
-envVarName: test-key
-envVarValue: test-value`,
+		expectedResult: []base.WorkloadOverride{{
+			Name: "controller",
+			Env: []base.EnvRequirementsOverride{{
+				Container: "controller",
+				EnvVars:   []corev1.EnvVar{{Name: "env", Value: "value1"}},
+			}, {
+				Container: "sidecar",
+				EnvVars:   []corev1.EnvVar{{Name: "env", Value: "value"}},
+			}},
+		}},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentEnvvars(tt.envVarFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := tt.input
+			err := common.NewKnativeServingSpec(&spec).Mutate(envVarMutator(tt.envVarFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedResult)
 		})
 	}
 }
@@ -15,8 +15,8 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -24,14 +24,9 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_replica.yaml
-var servingHAOverlay string
-
-//go:embed overlay/ke_replica.yaml
-var eventingHAOverlay string
-
 type HAFlags struct {
 	Replicas   string
 	Component  string
@@ -39,10 +34,9 @@ type HAFlags struct {
 	DeployName string
 }
 
-var haCMDFlags HAFlags
-
 // newHACommand represents the HA configure commands for Serving or Eventing
-func newHACommand(p *pkg.OperatorParams) *cobra.Command {
+func newHACommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var haCMDFlags HAFlags
 	var configureHAsCmd = &cobra.Command{
 		Use:   "replicas",
 		Short: "Configure the number of replicas for Knative Serving and Eventing deployments",
@@ -50,14 +44,11 @@ func newHACommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the number of replicas for Knative Serving and Eventing deployments
   kn operation configure replicas --component eventing --deployName eventing-controller --replicas 3 --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateHAsFlags(haCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureHAs(haCMDFlags, p)
+			err := ConfigureHA(haCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(haCMDFlags.Component, haCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified number of replicas has been configured in the namespace '%s'.\n",
 				haCMDFlags.Namespace)
@@ -89,82 +80,40 @@ func validateHAsFlags(haCMDFlags HAFlags) error {
 	return nil
 }
 
-func configureHAs(haCMDFlags HAFlags, p *pkg.OperatorParams) error {
-	component := common.ServingComponent
-	if strings.EqualFold(haCMDFlags.Component, common.EventingComponent) {
-		component = common.EventingComponent
-	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, haCMDFlags.Namespace, p)
-	if err != nil {
-		return err
-	}
-
-	overlayContent := getOverlayYamlContentHA(haCMDFlags)
-	valuesYaml := getYamlValuesContentHAs(haCMDFlags)
-
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+// ConfigureHA sets the number of the replicas for the Knative component or a Knative deployment
+func ConfigureHA(haCMDFlags HAFlags, p *pkg.OperatorParams) error {
+	if err := validateHAsFlags(haCMDFlags); err != nil {
 		return err
 	}
-	return nil
-}
 
-func getOverlayYamlContentHA(haCMDFlags HAFlags) string {
-	baseOverlayContent := servingHAOverlay
+	component := common.ServingComponent
 	if strings.EqualFold(haCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingHAOverlay
+		component = common.EventingComponent
 	}
-	haContent := getHAConfiguration(haCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, haContent)
-	return baseOverlayContent
-}
-
-func getHAConfiguration(haCMDFlags HAFlags) string {
-	resourceArray := []string{}
-	if haCMDFlags.DeployName == "" {
-		// Set the HA number of replicas globally to all deployments
-		tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		haField := fmt.Sprintf("%s%s", common.Spaces(2), "high-availability:")
-		resourceArray = append(resourceArray, haField)
-		tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		replicaField := fmt.Sprintf("%s%s", common.Spaces(4), "replicas: #@ data.values.replicas")
-		resourceArray = append(resourceArray, replicaField)
-	} else {
-		tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		deploymentField := fmt.Sprintf("%s%s", common.Spaces(2), "deployments:")
-		resourceArray = append(resourceArray, deploymentField)
-		tag = fmt.Sprintf("%s%s", common.Spaces(2), common.FieldByName("name"))
-		resourceArray = append(resourceArray, tag)
-		nameField := fmt.Sprintf("%s%s", common.Spaces(2), "- name: #@ data.values.name")
-		resourceArray = append(resourceArray, nameField)
-		tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		replicaField := fmt.Sprintf("%s%s", common.Spaces(4), "replicas: #@ data.values.replicas")
-		resourceArray = append(resourceArray, replicaField)
+	if err := common.CheckDeployment(component, haCMDFlags.Namespace, haCMDFlags.DeployName, "", p); err != nil {
+		return err
 	}
-
-	return strings.Join(resourceArray, "\n")
+	return common.ApplyKnativeCR(component, haCMDFlags.Namespace, p, haMutator(haCMDFlags))
 }
 
-func getYamlValuesContentHAs(haCMDFlags HAFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-
-	namespace := fmt.Sprintf("namespace: %s", haCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-
-	if haCMDFlags.DeployName == "" {
-		replicas := fmt.Sprintf("replicas: %s", haCMDFlags.Replicas)
-		contentArray = append(contentArray, replicas)
-	} else {
-		deployName := fmt.Sprintf("name: %s", haCMDFlags.DeployName)
-		contentArray = append(contentArray, deployName)
-		replicas := fmt.Sprintf("replicas: %s", haCMDFlags.Replicas)
-		contentArray = append(contentArray, replicas)
+// haMutator sets the number of replicas globally to all deployments, or to the specified deployment
+func haMutator(haCMDFlags HAFlags) common.SpecMutator {
+	return func(spec *common.KnativeSpec) error {
+		replicas, err := strconv.ParseInt(haCMDFlags.Replicas, 10, 32)
+		if err != nil || replicas < 0 {
+			return fmt.Errorf("The number of the replicas needs to be a non-negative integer.")
+		}
+		number := int32(replicas)
+
+		if haCMDFlags.DeployName == "" {
+			spec.HighAvailability = &base.HighAvailability{
+				Replicas: &number,
+			}
+			return nil
+		}
+		return common.UpdateDeploymentOverride(haCMDFlags.DeployName, func(override *base.WorkloadOverride) error {
+			override.Replicas = &number
+			return nil
+		})(spec)
 	}
-
-	return strings.Join(contentArray, "\n")
 }
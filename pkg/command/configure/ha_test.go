@@ -18,185 +18,12 @@ import (
 	"fmt"
 	"testing"
 
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-func TestGetOverlayYamlContentHA(t *testing.T) {
-	for _, tt := range []struct {
-		name           string
-		haCMDFlags     HAFlags
-		expectedResult string
-	}{{
-		name: "Knative Eventing",
-		haCMDFlags: HAFlags{
-			Replicas:   "4",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			DeployName: "eventing-controller",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.name
-    #@overlay/match missing_ok=True
-    replicas: #@ data.values.replicas`,
-	}, {
-		name: "Knative Serving",
-		haCMDFlags: HAFlags{
-			Replicas:   "2",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "activator",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.name
-    #@overlay/match missing_ok=True
-    replicas: #@ data.values.replicas`,
-	}, {
-		name: "Knative Eventing with no deployment name",
-		haCMDFlags: HAFlags{
-			Replicas:  "4",
-			Component: "eventing",
-			Namespace: "test-eventing",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  high-availability:
-    #@overlay/match missing_ok=True
-    replicas: #@ data.values.replicas`,
-	}, {
-		name: "Knative Serving with no deployment name",
-		haCMDFlags: HAFlags{
-			Replicas:  "2",
-			Component: "serving",
-			Namespace: "test-serving",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  high-availability:
-    #@overlay/match missing_ok=True
-    replicas: #@ data.values.replicas`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentHA(tt.haCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentHAs(t *testing.T) {
-	for _, tt := range []struct {
-		name           string
-		haCMDFlags     HAFlags
-		expectedResult string
-	}{{
-		name: "Knative Eventing",
-		haCMDFlags: HAFlags{
-			Replicas:   "2",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			DeployName: "eventing-controller",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-name: eventing-controller
-replicas: 2`,
-	}, {
-		name: "Knative Serving",
-		haCMDFlags: HAFlags{
-			Replicas:   "2",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "activator",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-name: activator
-replicas: 2`,
-	}, {
-		name: "Knative Eventing with no deployment name",
-		haCMDFlags: HAFlags{
-			Replicas:  "2",
-			Component: "eventing",
-			Namespace: "test-eventing",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-replicas: 2`,
-	}, {
-		name: "Knative Serving with no deployment name",
-		haCMDFlags: HAFlags{
-			Replicas:  "2",
-			Component: "serving",
-			Namespace: "test-serving",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-replicas: 2`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentHAs(tt.haCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
 func TestValidateHAsFlags(t *testing.T) {
 	for _, tt := range []struct {
 		name           string
@@ -256,3 +83,60 @@ func TestValidateHAsFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestHAMutator(t *testing.T) {
+	two := int32(2)
+	for _, tt := range []struct {
+		name                     string
+		haCMDFlags               HAFlags
+		input                    v1beta1.KnativeEventingSpec
+		expectedHighAvailability *base.HighAvailability
+		expectedDeployments      []base.WorkloadOverride
+		expectedErr              error
+	}{{
+		name: "Set the replicas for all deployments",
+		haCMDFlags: HAFlags{
+			Replicas:  "2",
+			Component: "eventing",
+			Namespace: "test-eventing",
+		},
+		input: v1beta1.KnativeEventingSpec{},
+		expectedHighAvailability: &base.HighAvailability{
+			Replicas: &two,
+		},
+	}, {
+		name: "Set the replicas for the deployment",
+		haCMDFlags: HAFlags{
+			Replicas:   "2",
+			Component:  "eventing",
+			Namespace:  "test-eventing",
+			DeployName: "eventing-controller",
+		},
+		input: v1beta1.KnativeEventingSpec{},
+		expectedDeployments: []base.WorkloadOverride{{
+			Name:     "eventing-controller",
+			Replicas: &two,
+		}},
+	}, {
+		name: "Invalid number of replicas",
+		haCMDFlags: HAFlags{
+			Replicas:  "two",
+			Component: "eventing",
+			Namespace: "test-eventing",
+		},
+		input:       v1beta1.KnativeEventingSpec{},
+		expectedErr: fmt.Errorf("The number of the replicas needs to be a non-negative integer."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.input
+			err := common.NewKnativeEventingSpec(&spec).Mutate(haMutator(tt.haCMDFlags))
+			if tt.expectedErr != nil {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedErr.Error())
+				return
+			}
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.HighAvailability, tt.expectedHighAvailability)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedDeployments)
+		})
+	}
+}
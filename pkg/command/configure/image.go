@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -26,12 +25,6 @@ import (
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
 
-//go:embed overlay/ks_image.yaml
-var servingImageOverlay string
-
-//go:embed overlay/ke_image.yaml
-var eventingImageOverlay string
-
 type ImageFlags struct {
 	ImageUrl   string
 	Component  string
@@ -40,10 +33,9 @@ type ImageFlags struct {
 	ImageKey   string
 }
 
-var imageCMDFlags ImageFlags
-
 // newImageCommand represents the configure commands to configure the image for Knative
-func newImageCommand(p *pkg.OperatorParams) *cobra.Command {
+func newImageCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var imageCMDFlags ImageFlags
 	var configureImagesCmd = &cobra.Command{
 		Use:   "images",
 		Short: "Configure the images for Knative",
@@ -51,14 +43,11 @@ func newImageCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the images for Knative
   kn operation configure images --component eventing --deployName eventing-controller --imageKey key --imageURL value --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateImagesFlags(imageCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureImages(imageCMDFlags, p)
+			err := ConfigureImages(imageCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(imageCMDFlags.Component, imageCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified images has been configured.\n")
 			return nil
@@ -91,85 +80,39 @@ func validateImagesFlags(imageCMDFlags ImageFlags) error {
 	return nil
 }
 
-func configureImages(imageCMDFlags ImageFlags, p *pkg.OperatorParams) error {
+// ConfigureImages sets the image for the Knative component or a Knative deployment
+func ConfigureImages(imageCMDFlags ImageFlags, p *pkg.OperatorParams) error {
+	if err := validateImagesFlags(imageCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(imageCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, imageCMDFlags.Namespace, p)
-	if err != nil {
-		return err
-	}
-
-	overlayContent := getOverlayYamlContentImage(imageCMDFlags)
-	valuesYaml := getYamlValuesContentImages(imageCMDFlags)
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+	if err := common.CheckDeployment(component, imageCMDFlags.Namespace, imageCMDFlags.DeployName, "", p); err != nil {
 		return err
 	}
-	return nil
+	return common.ApplyKnativeCR(component, imageCMDFlags.Namespace, p, imageMutator(imageCMDFlags))
 }
 
-func getOverlayYamlContentImage(imageCMDFlags ImageFlags) string {
-	baseOverlayContent := servingImageOverlay
-	if strings.EqualFold(imageCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingImageOverlay
+// imageMutator sets the default registry, the image of the queue sidecar, or the override of the image
+func imageMutator(imageCMDFlags ImageFlags) common.SpecMutator {
+	if strings.EqualFold(imageCMDFlags.ImageKey, "default") {
+		return func(spec *common.KnativeSpec) error {
+			spec.Registry.Default = imageCMDFlags.ImageUrl
+			return nil
+		}
+	} else if strings.EqualFold(imageCMDFlags.ImageKey, "queue-sidecar-image") || imageCMDFlags.ImageKey == "queueSidecarImage" {
+		return common.SetConfigMapData("deployment", "queue-sidecar-image", imageCMDFlags.ImageUrl)
 	}
 
-	resourceContent := getImageConfiguration(imageCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
-}
-
-func getImageConfiguration(imageCMDFlags ImageFlags) string {
-	resourceArray := []string{}
-	fieldFirstLine := "registry"
-	fieldSecondLine := "override"
 	imageKey := imageCMDFlags.ImageKey
-	if imageCMDFlags.DeployName != "" && !strings.EqualFold(imageCMDFlags.ImageKey, "default") {
+	if imageCMDFlags.DeployName != "" {
 		imageKey = fmt.Sprintf("%s/%s", imageCMDFlags.DeployName, imageCMDFlags.ImageKey)
 	}
-
-	if strings.EqualFold(imageCMDFlags.ImageKey, "default") {
-		tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		field := fmt.Sprintf("%s%s:", common.Spaces(2), "registry")
-		resourceArray = append(resourceArray, field)
-		tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(4), imageCMDFlags.ImageKey, "#@ data.values.imageValue")
-		resourceArray = append(resourceArray, keyValueField)
-		return strings.Join(resourceArray, "\n")
-	} else if strings.EqualFold(imageCMDFlags.ImageKey, "queue-sidecar-image") || imageCMDFlags.ImageKey == "queueSidecarImage" {
-		fieldFirstLine = "config"
-		fieldSecondLine = "deployment"
-		imageKey = "queue-sidecar-image"
+	return func(spec *common.KnativeSpec) error {
+		spec.Registry.Override = common.SetMapValue(spec.Registry.Override, imageKey, imageCMDFlags.ImageUrl)
+		return nil
 	}
-
-	tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-	field := fmt.Sprintf("%s%s:", common.Spaces(2), fieldFirstLine)
-	resourceArray = append(resourceArray, field)
-	tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-	field = fmt.Sprintf("%s%s:", common.Spaces(4), fieldSecondLine)
-	resourceArray = append(resourceArray, field)
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-	keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(6), imageKey, "#@ data.values.imageValue")
-	resourceArray = append(resourceArray, keyValueField)
-
-	return strings.Join(resourceArray, "\n")
-}
-
-func getYamlValuesContentImages(imageCMDFlags ImageFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-	namespace := fmt.Sprintf("namespace: %s", imageCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-	deployName := fmt.Sprintf("deployName: %s", imageCMDFlags.DeployName)
-	contentArray = append(contentArray, deployName)
-	value := fmt.Sprintf("imageValue: %s", imageCMDFlags.ImageUrl)
-	contentArray = append(contentArray, value)
-	return strings.Join(contentArray, "\n")
 }
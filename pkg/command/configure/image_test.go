@@ -18,7 +18,10 @@ import (
 	"fmt"
 	"testing"
 
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateImagesFlags(t *testing.T) {
@@ -104,164 +107,64 @@ func TestValidateImagesFlags(t *testing.T) {
 	}
 }
 
-func TestGetOverlayYamlContentImage(t *testing.T) {
+func TestImageMutator(t *testing.T) {
 	for _, tt := range []struct {
-		name           string
-		imageCMDFlags  ImageFlags
-		expectedResult string
+		name             string
+		imageCMDFlags    ImageFlags
+		expectedRegistry base.Registry
+		expectedConfig   base.ConfigMapData
 	}{{
-		name: "Knative Eventing",
+		name: "Set the default registry",
 		imageCMDFlags: ImageFlags{
-			ImageKey:  "test-key",
-			ImageUrl:  "test-value",
-			Component: "eventing",
-			Namespace: "test-eventing",
+			ImageUrl:  "docker.io/knative-images/${NAME}:v0.13.0",
+			Component: "serving",
+			Namespace: "test-serving",
+			ImageKey:  "default",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  registry:
-    #@overlay/match missing_ok=True
-    override:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.imageValue`,
-	}, {
-		name: "Knative Serving with the image key default",
-		imageCMDFlags: ImageFlags{
-			ImageKey:   "default",
-			ImageUrl:   "test-value",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "network",
+		expectedRegistry: base.Registry{
+			Default: "docker.io/knative-images/${NAME}:v0.13.0",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  registry:
-    #@overlay/match missing_ok=True
-    default: #@ data.values.imageValue`,
 	}, {
-		name: "Knative Serving",
+		name: "Set the image of the queue sidecar",
 		imageCMDFlags: ImageFlags{
-			ImageKey:   "test-key",
-			ImageUrl:   "test-value",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "network",
+			ImageUrl:  "docker.io/knative-images/queue:v0.13.0",
+			Component: "serving",
+			Namespace: "test-serving",
+			ImageKey:  "queueSidecarImage",
+		},
+		expectedConfig: base.ConfigMapData{
+			"deployment": {"queue-sidecar-image": "docker.io/knative-images/queue:v0.13.0"},
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  registry:
-    #@overlay/match missing_ok=True
-    override:
-      #@overlay/match missing_ok=True
-      network/test-key: #@ data.values.imageValue`,
 	}, {
-		name: "Knative Serving for queue-sidecar-image",
+		name: "Set the image of the container",
 		imageCMDFlags: ImageFlags{
-			ImageKey:  "queue-sidecar-image",
-			ImageUrl:  "test-value",
+			ImageUrl:  "docker.io/knative-images/controller:v0.13.0",
 			Component: "serving",
 			Namespace: "test-serving",
+			ImageKey:  "controller",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  config:
-    #@overlay/match missing_ok=True
-    deployment:
-      #@overlay/match missing_ok=True
-      queue-sidecar-image: #@ data.values.imageValue`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentImage(tt.imageCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentImages(t *testing.T) {
-	for _, tt := range []struct {
-		name           string
-		imageCMDFlags  ImageFlags
-		expectedResult string
-	}{{
-		name: "Knative Eventing",
-		imageCMDFlags: ImageFlags{
-			ImageKey:   "test-key",
-			ImageUrl:   "test-value",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			DeployName: "network",
+		expectedRegistry: base.Registry{
+			Override: map[string]string{"controller": "docker.io/knative-images/controller:v0.13.0"},
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-deployName: network
-imageValue: test-value`,
 	}, {
-		name: "Knative Serving",
+		name: "Set the image of the container in the deployment",
 		imageCMDFlags: ImageFlags{
-			ImageKey:   "test-key",
-			ImageUrl:   "test-value",
+			ImageUrl:   "docker.io/knative-images/controller:v0.13.0",
 			Component:  "serving",
 			Namespace:  "test-serving",
-			DeployName: "network",
+			DeployName: "net-istio-controller",
+			ImageKey:   "controller",
+		},
+		expectedRegistry: base.Registry{
+			Override: map[string]string{"net-istio-controller/controller": "docker.io/knative-images/controller:v0.13.0"},
 		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-deployName: network
-imageValue: test-value`,
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentImages(tt.imageCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := v1beta1.KnativeServingSpec{}
+			err := common.NewKnativeServingSpec(&spec).Mutate(imageMutator(tt.imageCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.Registry, tt.expectedRegistry)
+			testingUtil.AssertDeepEqual(t, spec.Config, tt.expectedConfig)
 		})
 	}
 }
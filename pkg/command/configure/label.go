@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -24,18 +23,12 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_deploy_label.yaml
-var servingLabelOverlay string
-
-//go:embed overlay/ke_deploy_label.yaml
-var eventingLabelOverlay string
-
-var deploymentLabelCMDFlags common.KeyValueFlags
-
 // newDeploymentLabelCommand represents the configure commands to configure the labels for Knative deployment
-func newDeploymentLabelCommand(p *pkg.OperatorParams) *cobra.Command {
+func newDeploymentLabelCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var deploymentLabelCMDFlags common.KeyValueFlags
 	var configureLabelsCmd = &cobra.Command{
 		Use:   "labels",
 		Short: "Configure the labels for Knative Serving and Eventing deployments",
@@ -43,14 +36,11 @@ func newDeploymentLabelCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the labels for Knative Serving and Eventing deployments
   kn operation configure labels --component eventing --deployName eventing-controller --key key --value value --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateLabelsAnnotationsFlags(deploymentLabelCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureLabels(deploymentLabelCMDFlags, p)
+			err := ConfigureLabels(deploymentLabelCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(deploymentLabelCMDFlags.Component, deploymentLabelCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified labels has been configured for the deployment %s in the deployment '%s'.\n",
 				deploymentLabelCMDFlags.DeployName, deploymentLabelCMDFlags.Namespace)
@@ -64,15 +54,16 @@ func newDeploymentLabelCommand(p *pkg.OperatorParams) *cobra.Command {
 	configureLabelsCmd.Flags().StringVar(&deploymentLabelCMDFlags.ServiceName, "serviceName", "", "The flag to specify the service name")
 	configureLabelsCmd.Flags().StringVarP(&deploymentLabelCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
 	configureLabelsCmd.Flags().StringVarP(&deploymentLabelCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+	common.AddKeyValueSourceFlags(configureLabelsCmd.Flags(), &deploymentLabelCMDFlags.KeyValueSources)
 
 	return configureLabelsCmd
 }
 
 func validateKeyValuePairs(deploymentLabelCMDFlags common.KeyValueFlags) error {
-	if deploymentLabelCMDFlags.Key == "" {
+	if deploymentLabelCMDFlags.Key == "" && deploymentLabelCMDFlags.KeyValueSources.IsEmpty() {
 		return fmt.Errorf("You need to specify the key.")
 	}
-	if deploymentLabelCMDFlags.Value == "" {
+	if deploymentLabelCMDFlags.Key != "" && deploymentLabelCMDFlags.Value == "" {
 		return fmt.Errorf("You need to specify the value.")
 	}
 	if deploymentLabelCMDFlags.Namespace == "" {
@@ -97,90 +88,36 @@ func validateLabelsAnnotationsFlags(deploymentLabelCMDFlags common.KeyValueFlags
 	return nil
 }
 
-func configureLabels(deploymentLabelCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+// ConfigureLabels sets the label for a Knative deployment or service
+func ConfigureLabels(deploymentLabelCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+	if err := validateLabelsAnnotationsFlags(deploymentLabelCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(deploymentLabelCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, deploymentLabelCMDFlags.Namespace, p)
-	if err != nil {
+	if err := common.CheckDeployment(component, deploymentLabelCMDFlags.Namespace, deploymentLabelCMDFlags.DeployName, "", p); err != nil {
 		return err
 	}
-
-	overlayContent := getOverlayYamlContentLabel(deploymentLabelCMDFlags)
-	valuesYaml := getYamlValuesContent(deploymentLabelCMDFlags)
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+	mutators, err := keyValueMutators(deploymentLabelCMDFlags, labelMutator)
+	if err != nil {
 		return err
 	}
-	return nil
+	return common.ApplyKnativeCR(component, deploymentLabelCMDFlags.Namespace, p, mutators...)
 }
 
-func getOverlayYamlContentLabel(deploymentLabelCMDFlags common.KeyValueFlags) string {
-	baseOverlayContent := servingLabelOverlay
-	if strings.EqualFold(deploymentLabelCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingLabelOverlay
-	}
-	resourceContent := getLabelConfiguration(deploymentLabelCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
-}
-
-func getLabelConfiguration(deploymentLabelCMDFlags common.KeyValueFlags) string {
-	resourceArray := []string{}
-
-	tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	if deploymentLabelCMDFlags.DeployName != "" {
-		field := fmt.Sprintf("%s%s:", common.Spaces(2), "deployments")
-		resourceArray = append(resourceArray, field)
-	} else {
-		field := fmt.Sprintf("%s%s:", common.Spaces(2), "services")
-		resourceArray = append(resourceArray, field)
-	}
-
-	field := fmt.Sprintf("%s%s", common.Spaces(2), common.FieldByName("name"))
-	resourceArray = append(resourceArray, field)
-
+// labelMutator sets the label for the deployment or the service
+func labelMutator(deploymentLabelCMDFlags common.KeyValueFlags) common.SpecMutator {
 	if deploymentLabelCMDFlags.DeployName != "" {
-		deployName := fmt.Sprintf("%s- %s: %s", common.Spaces(2), "name", "#@ data.values.deployName")
-		resourceArray = append(resourceArray, deployName)
-	} else {
-		serviceName := fmt.Sprintf("%s- %s: %s", common.Spaces(2), "name", "#@ data.values.serviceName")
-		resourceArray = append(resourceArray, serviceName)
-	}
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	field = fmt.Sprintf("%s%s:", common.Spaces(4), "labels")
-	resourceArray = append(resourceArray, field)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(6), deploymentLabelCMDFlags.Key, "#@ data.values.value")
-	resourceArray = append(resourceArray, keyValueField)
-
-	return strings.Join(resourceArray, "\n")
-}
-
-func getYamlValuesContent(deploymentLabelCMDFlags common.KeyValueFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-	namespace := fmt.Sprintf("namespace: %s", deploymentLabelCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-
-	if deploymentLabelCMDFlags.DeployName != "" {
-		deployName := fmt.Sprintf("deployName: %s", deploymentLabelCMDFlags.DeployName)
-		contentArray = append(contentArray, deployName)
-	} else {
-		serviceName := fmt.Sprintf("serviceName: %s", deploymentLabelCMDFlags.ServiceName)
-		contentArray = append(contentArray, serviceName)
+		return common.UpdateDeploymentOverride(deploymentLabelCMDFlags.DeployName, func(override *base.WorkloadOverride) error {
+			override.Labels = common.SetMapValue(override.Labels, deploymentLabelCMDFlags.Key, deploymentLabelCMDFlags.Value)
+			return nil
+		})
 	}
-
-	value := fmt.Sprintf("value: %s", deploymentLabelCMDFlags.Value)
-	contentArray = append(contentArray, value)
-	return strings.Join(contentArray, "\n")
+	return common.UpdateServiceOverride(deploymentLabelCMDFlags.ServiceName, func(override *base.ServiceOverride) error {
+		override.Labels = common.SetMapValue(override.Labels, deploymentLabelCMDFlags.Key, deploymentLabelCMDFlags.Value)
+		return nil
+	})
 }
@@ -20,6 +20,8 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateLabelsAnnotationsFlags(t *testing.T) {
@@ -125,13 +127,15 @@ func TestValidateLabelsAnnotationsFlags(t *testing.T) {
 	}
 }
 
-func TestGetOverlayYamlContentLabel(t *testing.T) {
+func TestLabelMutator(t *testing.T) {
 	for _, tt := range []struct {
 		name                    string
 		deploymentLabelCMDFlags common.KeyValueFlags
-		expectedResult          string
+		input                   v1beta1.KnativeEventingSpec
+		expectedDeployments     []base.WorkloadOverride
+		expectedServices        []base.ServiceOverride
 	}{{
-		name: "Knative Eventing",
+		name: "Set the label of the existing deployment override",
 		deploymentLabelCMDFlags: common.KeyValueFlags{
 			Key:        "test-key",
 			Value:      "test-value",
@@ -139,86 +143,59 @@ func TestGetOverlayYamlContentLabel(t *testing.T) {
 			Namespace:  "test-eventing",
 			DeployName: "eventing-controller",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    labels:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentLabel(tt.deploymentLabelCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentLabels(t *testing.T) {
-	for _, tt := range []struct {
-		name                    string
-		deploymentLabelCMDFlags common.KeyValueFlags
-		expectedResult          string
-	}{{
-		name: "Knative Eventing",
-		deploymentLabelCMDFlags: common.KeyValueFlags{
-			Key:        "test-key",
-			Value:      "test-value",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			DeployName: "network",
+		input: v1beta1.KnativeEventingSpec{
+			CommonSpec: base.CommonSpec{
+				DeploymentOverride: []base.WorkloadOverride{{
+					Name:   "eventing-webhook",
+					Labels: map[string]string{"test-key": "value"},
+				}, {
+					Name:   "eventing-controller",
+					Labels: map[string]string{"key": "value"},
+				}},
+			},
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-deployName: network
-value: test-value`,
+		expectedDeployments: []base.WorkloadOverride{{
+			Name:   "eventing-webhook",
+			Labels: map[string]string{"test-key": "value"},
+		}, {
+			Name:   "eventing-controller",
+			Labels: map[string]string{"key": "value", "test-key": "test-value"},
+		}},
 	}, {
-		name: "Knative Eventing with service name",
+		name: "Add the label to a new service override",
 		deploymentLabelCMDFlags: common.KeyValueFlags{
 			Key:         "test-key",
 			Value:       "test-value",
 			Component:   "eventing",
 			Namespace:   "test-eventing",
-			ServiceName: "network",
+			ServiceName: "eventing-webhook",
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-serviceName: network
-value: test-value`,
+		input: v1beta1.KnativeEventingSpec{},
+		expectedServices: []base.ServiceOverride{{
+			Name:   "eventing-webhook",
+			Labels: map[string]string{"test-key": "test-value"},
+		}},
 	}, {
-		name: "Knative Serving",
+		name: "Values are not interpreted as YAML",
 		deploymentLabelCMDFlags: common.KeyValueFlags{
-			Key:        "test-key",
-			Value:      "test-value",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "network",
+			Key:         "test-key",
+			Value:       "value: {with} #special: characters",
+			Component:   "eventing",
+			Namespace:   "test-eventing",
+			ServiceName: "eventing-webhook",
 		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-deployName: network
-value: test-value`,
+		input: v1beta1.KnativeEventingSpec{},
+		expectedServices: []base.ServiceOverride{{
+			Name:   "eventing-webhook",
+			Labels: map[string]string{"test-key": "value: {with} #special: characters"},
+		}},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContent(tt.deploymentLabelCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := tt.input
+			err := common.NewKnativeEventingSpec(&spec).Mutate(labelMutator(tt.deploymentLabelCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedDeployments)
+			testingUtil.AssertDeepEqual(t, spec.ServiceOverride, tt.expectedServices)
 		})
 	}
 }
@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -24,15 +23,10 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_custom_manifests.yaml
-var servingManifestsOverlay string
-
-//go:embed overlay/ke_custom_manifests.yaml
-var eventingManifestsOverlay string
-
-type manifestsFlags struct {
+type ManifestsFlags struct {
 	File              string
 	OperatorNamespace string
 	Namespace         string
@@ -41,10 +35,9 @@ type manifestsFlags struct {
 	Accessible        bool
 }
 
-var manifestsCMDFlags manifestsFlags
-
 // newManifestsCommand represents the configure commands to configure the additional manifests
-func newManifestsCommand(p *pkg.OperatorParams) *cobra.Command {
+func newManifestsCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var manifestsCMDFlags ManifestsFlags
 	var configureManifestsCmd = &cobra.Command{
 		Use:   "manifests",
 		Short: "Configure the custom manifests for Knative",
@@ -52,14 +45,11 @@ func newManifestsCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the custom manifests for Knative
   kn operation configure manifests --component eventing --namespace knative-eventing --operatorNamespace default --file filePath`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateManifestsFlags(manifestsCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureManifests(manifestsCMDFlags, p)
+			err := ConfigureManifests(manifestsCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(manifestsCMDFlags.Component, manifestsCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified custom manifests has been configured.\n")
 			return nil
@@ -76,7 +66,7 @@ func newManifestsCommand(p *pkg.OperatorParams) *cobra.Command {
 	return configureManifestsCmd
 }
 
-func validateManifestsFlags(manifestsCMDFlags manifestsFlags) error {
+func validateManifestsFlags(manifestsCMDFlags ManifestsFlags) error {
 	if manifestsCMDFlags.File == "" {
 		return fmt.Errorf("You need to specify the local path of the file containing the custom manifests.")
 	}
@@ -89,7 +79,8 @@ func validateManifestsFlags(manifestsCMDFlags manifestsFlags) error {
 	return nil
 }
 
-func UpdateOperatorForCustomManifests(manifestsCMDFlags manifestsFlags, p *pkg.OperatorParams) error {
+func UpdateOperatorForCustomManifests(manifestsCMDFlags ManifestsFlags, p *pkg.OperatorParams) error {
+	ctx := p.Context()
 	kubeClient, err := p.NewKubeClient()
 	if err != nil {
 		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
@@ -104,18 +95,23 @@ func UpdateOperatorForCustomManifests(manifestsCMDFlags manifestsFlags, p *pkg.O
 		return err
 	}
 
-	if err = kubeResource.CreateOrUpdateConfigMap(common.ConfigMapName, manifestsCMDFlags.Namespace, data, manifestsCMDFlags.Overwrite); err != nil {
+	if err = kubeResource.CreateOrUpdateConfigMap(ctx, common.ConfigMapName, manifestsCMDFlags.Namespace, data, manifestsCMDFlags.Overwrite); err != nil {
 		return err
 	}
 
-	if err = kubeResource.UpdateOperatorDeployment(common.KnativeOperatorName, manifestsCMDFlags.OperatorNamespace); err != nil {
+	if err = kubeResource.UpdateOperatorDeployment(ctx, common.KnativeOperatorName, manifestsCMDFlags.OperatorNamespace); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func configureManifests(manifestsCMDFlags manifestsFlags, p *pkg.OperatorParams) error {
+// ConfigureManifests adds the custom manifests to the Knative component
+func ConfigureManifests(manifestsCMDFlags ManifestsFlags, p *pkg.OperatorParams) error {
+	if err := validateManifestsFlags(manifestsCMDFlags); err != nil {
+		return err
+	}
+
 	if !manifestsCMDFlags.Accessible {
 		if err := UpdateOperatorForCustomManifests(manifestsCMDFlags, p); err != nil {
 			return err
@@ -127,60 +123,28 @@ func configureManifests(manifestsCMDFlags manifestsFlags, p *pkg.OperatorParams)
 	if strings.EqualFold(manifestsCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, manifestsCMDFlags.Namespace, p)
-	if err != nil {
-		return err
-	}
-
-	overlayContent := getOverlayYamlContentManifest(manifestsCMDFlags)
-	valuesYaml := getYamlValuesContentManifests(manifestsCMDFlags)
-	if err = common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
-		return err
-	}
-	return nil
+	return common.ApplyKnativeCR(component, manifestsCMDFlags.Namespace, p, manifestsMutator(manifestsCMDFlags))
 }
 
-func getOverlayYamlContentManifest(manifestsCMDFlags manifestsFlags) string {
-	baseOverlayContent := servingManifestsOverlay
-	if strings.EqualFold(manifestsCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingManifestsOverlay
-	}
-	resourceContent := getManifestsConfiguration(manifestsCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
-}
-
-func getManifestsConfiguration(manifestsCMDFlags manifestsFlags) string {
-	resourceArray := []string{}
-	tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-	if manifestsCMDFlags.Overwrite {
-		tag = fmt.Sprintf("%s%s", common.Spaces(2), common.YttReplaceTag)
-		resourceArray = append(resourceArray, tag)
+// manifestsMutator adds the path of the custom manifests into the additional manifests, or replaces all the
+// additional manifests with it, if overwrite is set.
+func manifestsMutator(manifestsCMDFlags ManifestsFlags) common.SpecMutator {
+	manifestsPath := common.MountPath
+	if manifestsCMDFlags.Accessible {
+		manifestsPath = manifestsCMDFlags.File
 	}
 
-	field := fmt.Sprintf("%s%s:", common.Spaces(2), "additionalManifests")
-	resourceArray = append(resourceArray, field)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(2), common.FieldByName("URL"))
-	resourceArray = append(resourceArray, tag)
-
-	field = fmt.Sprintf("%s- %s: %s", common.Spaces(2), "URL", "#@ data.values.manifestsPath")
-	resourceArray = append(resourceArray, field)
-
-	return strings.Join(resourceArray, "\n")
-}
-
-func getYamlValuesContentManifests(manifestsCMDFlags manifestsFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-	namespace := fmt.Sprintf("namespace: %s", manifestsCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-	deployName := fmt.Sprintf("manifestsPath: %s", common.MountPath)
-	if manifestsCMDFlags.Accessible {
-		deployName = fmt.Sprintf("manifestsPath: %s", manifestsCMDFlags.File)
+	return func(spec *common.KnativeSpec) error {
+		if manifestsCMDFlags.Overwrite {
+			spec.AdditionalManifests = []base.Manifest{{Url: manifestsPath}}
+			return nil
+		}
+		for _, manifest := range spec.AdditionalManifests {
+			if manifest.Url == manifestsPath {
+				return nil
+			}
+		}
+		spec.AdditionalManifests = append(spec.AdditionalManifests, base.Manifest{Url: manifestsPath})
+		return nil
 	}
-	contentArray = append(contentArray, deployName)
-	return strings.Join(contentArray, "\n")
 }
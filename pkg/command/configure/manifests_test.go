@@ -18,17 +18,20 @@ import (
 	"fmt"
 	"testing"
 
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateManifestsFlags(t *testing.T) {
 	for _, tt := range []struct {
 		name              string
-		manifestsCMDFlags manifestsFlags
+		manifestsCMDFlags ManifestsFlags
 		expectedResult    error
 	}{{
 		name: "Knative Eventing with no file path",
-		manifestsCMDFlags: manifestsFlags{
+		manifestsCMDFlags: ManifestsFlags{
 			Component:         "eventing",
 			Namespace:         "test-eventing",
 			OperatorNamespace: "eventing-controller",
@@ -36,7 +39,7 @@ func TestValidateManifestsFlags(t *testing.T) {
 		expectedResult: fmt.Errorf("You need to specify the local path of the file containing the custom manifests."),
 	}, {
 		name: "Knative Eventing",
-		manifestsCMDFlags: manifestsFlags{
+		manifestsCMDFlags: ManifestsFlags{
 			File:              "test-file.yaml",
 			Component:         "eventing",
 			Namespace:         "test-eventing",
@@ -45,7 +48,7 @@ func TestValidateManifestsFlags(t *testing.T) {
 		expectedResult: nil,
 	}, {
 		name: "Knative Eventing with no namespace",
-		manifestsCMDFlags: manifestsFlags{
+		manifestsCMDFlags: ManifestsFlags{
 			File:              "file.yaml",
 			Component:         "eventing",
 			OperatorNamespace: "eventing-controller",
@@ -53,7 +56,7 @@ func TestValidateManifestsFlags(t *testing.T) {
 		expectedResult: fmt.Errorf("You need to specify the namespace for the Knative component."),
 	}, {
 		name: "Knative Eventing with invalid component",
-		manifestsCMDFlags: manifestsFlags{
+		manifestsCMDFlags: ManifestsFlags{
 			File:              "file.yaml",
 			Namespace:         "test",
 			Component:         "test",
@@ -72,100 +75,48 @@ func TestValidateManifestsFlags(t *testing.T) {
 	}
 }
 
-func TestGetYamlValuesContentManifests(t *testing.T) {
+func TestManifestsMutator(t *testing.T) {
 	for _, tt := range []struct {
 		name              string
-		manifestsCMDFlags manifestsFlags
-		expectedResult    string
+		manifestsCMDFlags ManifestsFlags
+		input             []base.Manifest
+		expectedResult    []base.Manifest
 	}{{
-		name: "Knative Eventing",
-		manifestsCMDFlags: manifestsFlags{
-			Component: "eventing",
-			Namespace: "test-eventing",
+		name: "Add the mounted path of the custom manifests",
+		manifestsCMDFlags: ManifestsFlags{
+			File:      "test.yaml",
+			Namespace: "test-serving",
+			Component: "serving",
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-manifestsPath: /knative-custom-manifest`,
+		input:          []base.Manifest{{Url: "https://example.com/manifests.yaml"}},
+		expectedResult: []base.Manifest{{Url: "https://example.com/manifests.yaml"}, {Url: common.MountPath}},
 	}, {
-		name: "Knative Eventing with accessible file",
-		manifestsCMDFlags: manifestsFlags{
-			File:       "public-file-link",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			Accessible: true,
-		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-manifestsPath: public-file-link`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentManifests(tt.manifestsCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetOverlayYamlContentManifests(t *testing.T) {
-	for _, tt := range []struct {
-		name              string
-		manifestsCMDFlags manifestsFlags
-		expectedResult    string
-	}{{
-		name: "Knative Eventing",
-		manifestsCMDFlags: manifestsFlags{
-			File:      "local-test-file",
-			Component: "eventing",
-			Namespace: "test-eventing",
+		name: "Existing path is not added again",
+		manifestsCMDFlags: ManifestsFlags{
+			File:      "test.yaml",
+			Namespace: "test-serving",
+			Component: "serving",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  additionalManifests:
-  #@overlay/match by="URL",missing_ok=True
-  - URL: #@ data.values.manifestsPath`,
+		input:          []base.Manifest{{Url: common.MountPath}},
+		expectedResult: []base.Manifest{{Url: common.MountPath}},
 	}, {
-		name: "Knative Serving with overwrite mode",
-		manifestsCMDFlags: manifestsFlags{
-			File:      "local-test-file",
-			Component: "serving",
-			Namespace: "test-eventing",
-			Overwrite: true,
+		name: "Overwrite the additional manifests with the accessible path",
+		manifestsCMDFlags: ManifestsFlags{
+			File:       "https://example.com/custom.yaml",
+			Namespace:  "test-serving",
+			Component:  "serving",
+			Overwrite:  true,
+			Accessible: true,
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  #@overlay/replace or_add=True
-  additionalManifests:
-  #@overlay/match by="URL",missing_ok=True
-  - URL: #@ data.values.manifestsPath`,
+		input:          []base.Manifest{{Url: "https://example.com/manifests.yaml"}},
+		expectedResult: []base.Manifest{{Url: "https://example.com/custom.yaml"}},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentManifest(tt.manifestsCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := v1beta1.KnativeServingSpec{}
+			spec.AdditionalManifests = tt.input
+			err := common.NewKnativeServingSpec(&spec).Mutate(manifestsMutator(tt.manifestsCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.AdditionalManifests, tt.expectedResult)
 		})
 	}
 }
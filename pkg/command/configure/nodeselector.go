@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -24,18 +23,12 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_deploy_label.yaml
-var servingNodeSelectorOverlay string
-
-//go:embed overlay/ke_deploy_label.yaml
-var eventingNodeSelectorOverlay string
-
-var nodeSelectorCMDFlags common.KeyValueFlags
-
 // newNodeSelectorCommand represents the configure commands to configure the nodeSelector for Knative deployment
-func newNodeSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
+func newNodeSelectorCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var nodeSelectorCMDFlags common.KeyValueFlags
 	var configureNodeSelectorsCmd = &cobra.Command{
 		Use:   "nodeSelectors",
 		Short: "Configure the node selectors for Knative Serving and Eventing deployments",
@@ -43,14 +36,11 @@ func newNodeSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the nodeSelectors for Knative Serving and Eventing deployments
   kn operation nodeSelectors --component eventing --deployName eventing-controller --key key --value value --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateNodeSelectorFlags(nodeSelectorCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureNodeSelectors(nodeSelectorCMDFlags, p)
+			err := ConfigureNodeSelectors(nodeSelectorCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(nodeSelectorCMDFlags.Component, nodeSelectorCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified annotation has been configured for the deployment %s in the deployment '%s'.\n",
 				nodeSelectorCMDFlags.DeployName, nodeSelectorCMDFlags.Namespace)
@@ -63,6 +53,7 @@ func newNodeSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
 	configureNodeSelectorsCmd.Flags().StringVar(&nodeSelectorCMDFlags.DeployName, "deployName", "", "The flag to specify the deployment name")
 	configureNodeSelectorsCmd.Flags().StringVarP(&nodeSelectorCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
 	configureNodeSelectorsCmd.Flags().StringVarP(&nodeSelectorCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+	common.AddKeyValueSourceFlags(configureNodeSelectorsCmd.Flags(), &nodeSelectorCMDFlags.KeyValueSources)
 
 	return configureNodeSelectorsCmd
 }
@@ -77,60 +68,30 @@ func validateNodeSelectorFlags(keyValuesCMDFlags common.KeyValueFlags) error {
 	return nil
 }
 
-func configureNodeSelectors(nodeSelectorCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+// ConfigureNodeSelectors sets the node selector for a Knative deployment
+func ConfigureNodeSelectors(nodeSelectorCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+	if err := validateNodeSelectorFlags(nodeSelectorCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(nodeSelectorCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, nodeSelectorCMDFlags.Namespace, p)
-	if err != nil {
+	if err := common.CheckDeployment(component, nodeSelectorCMDFlags.Namespace, nodeSelectorCMDFlags.DeployName, "", p); err != nil {
 		return err
 	}
-
-	overlayContent := getOverlayYamlContentNodeSelector(nodeSelectorCMDFlags)
-	valuesYaml := getYamlValuesContent(nodeSelectorCMDFlags)
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+	mutators, err := keyValueMutators(nodeSelectorCMDFlags, nodeSelectorMutator)
+	if err != nil {
 		return err
 	}
-	return nil
-}
-
-func getOverlayYamlContentNodeSelector(nodeSelectorCMDFlags common.KeyValueFlags) string {
-	baseOverlayContent := servingNodeSelectorOverlay
-	if strings.EqualFold(nodeSelectorCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingNodeSelectorOverlay
-	}
-	resourceContent := getNodeSelectorConfiguration(nodeSelectorCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
+	return common.ApplyKnativeCR(component, nodeSelectorCMDFlags.Namespace, p, mutators...)
 }
 
-func getNodeSelectorConfiguration(annotationCMDFlags common.KeyValueFlags) string {
-	resourceArray := []string{}
-
-	tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	field := fmt.Sprintf("%s%s:", common.Spaces(2), "deployments")
-	resourceArray = append(resourceArray, field)
-
-	field = fmt.Sprintf("%s%s", common.Spaces(2), common.FieldByName("name"))
-	resourceArray = append(resourceArray, field)
-
-	deployName := fmt.Sprintf("%s- %s: %s", common.Spaces(2), "name", "#@ data.values.deployName")
-	resourceArray = append(resourceArray, deployName)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	field = fmt.Sprintf("%s%s:", common.Spaces(4), "nodeSelector")
-	resourceArray = append(resourceArray, field)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(6), annotationCMDFlags.Key, "#@ data.values.value")
-	resourceArray = append(resourceArray, keyValueField)
-
-	return strings.Join(resourceArray, "\n")
+// nodeSelectorMutator sets the node selector for the deployment
+func nodeSelectorMutator(nodeSelectorCMDFlags common.KeyValueFlags) common.SpecMutator {
+	return common.UpdateDeploymentOverride(nodeSelectorCMDFlags.DeployName, func(override *base.WorkloadOverride) error {
+		override.NodeSelector = common.SetMapValue(override.NodeSelector, nodeSelectorCMDFlags.Key, nodeSelectorCMDFlags.Value)
+		return nil
+	})
 }
@@ -20,6 +20,8 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateNodeSelectorFlags(t *testing.T) {
@@ -105,45 +107,53 @@ func TestValidateNodeSelectorFlags(t *testing.T) {
 	}
 }
 
-func TestGetOverlayYamlContentNodeSelector(t *testing.T) {
+func TestNodeSelectorMutator(t *testing.T) {
 	for _, tt := range []struct {
-		name                    string
-		deploymentLabelCMDFlags common.KeyValueFlags
-		expectedResult          string
+		name                 string
+		nodeSelectorCMDFlags common.KeyValueFlags
+		input                v1beta1.KnativeServingSpec
+		expectedResult       []base.WorkloadOverride
 	}{{
-		name: "Knative Serving template for NodeSelector configuration",
-		deploymentLabelCMDFlags: common.KeyValueFlags{
+		name: "Add the node selector to a new deployment override",
+		nodeSelectorCMDFlags: common.KeyValueFlags{
 			Key:        "test-key",
 			Value:      "test-value",
 			Component:  "serving",
 			Namespace:  "test-serving",
 			DeployName: "network",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    nodeSelector:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
+		input: v1beta1.KnativeServingSpec{},
+		expectedResult: []base.WorkloadOverride{{
+			Name:         "network",
+			NodeSelector: map[string]string{"test-key": "test-value"},
+		}},
+	}, {
+		name: "Set the node selector of the existing deployment override",
+		nodeSelectorCMDFlags: common.KeyValueFlags{
+			Key:        "test-key",
+			Value:      "test-value",
+			Component:  "serving",
+			Namespace:  "test-serving",
+			DeployName: "network",
+		},
+		input: v1beta1.KnativeServingSpec{
+			CommonSpec: base.CommonSpec{
+				DeploymentOverride: []base.WorkloadOverride{{
+					Name:         "network",
+					NodeSelector: map[string]string{"test-key": "old-value", "key": "value"},
+				}},
+			},
+		},
+		expectedResult: []base.WorkloadOverride{{
+			Name:         "network",
+			NodeSelector: map[string]string{"test-key": "test-value", "key": "value"},
+		}},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentNodeSelector(tt.deploymentLabelCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := tt.input
+			err := common.NewKnativeServingSpec(&spec).Mutate(nodeSelectorMutator(tt.nodeSelectorCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedResult)
 		})
 	}
 }
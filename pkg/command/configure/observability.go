@@ -0,0 +1,214 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+var (
+	metricsBackends = []string{"prometheus", "opencensus", "none"}
+	tracingBackends = []string{"zipkin", "none"}
+	logLevels       = []string{"debug", "info", "warn", "error", "dpanic", "panic", "fatal"}
+
+	// defaultLogComponents are the components, whose log level is set if no component is specified
+	defaultLogComponents = map[string][]string{
+		common.ServingComponent:  {"activator", "autoscaler", "controller", "queueproxy", "webhook"},
+		common.EventingComponent: {"controller", "webhook"},
+	}
+)
+
+type ObservabilityFlags struct {
+	MetricsBackend    string
+	OpenCensusAddress string
+	TracingBackend    string
+	ZipkinEndpoint    string
+	SampleRate        string
+	LogLevel          string
+	LogComponents     []string
+	Component         string
+	Namespace         string
+}
+
+// newObservabilityCommand represents the configure command to set the logging, the metrics and the tracing
+// of Knative Serving or Eventing
+func newObservabilityCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var observabilityCMDFlags ObservabilityFlags
+	var configureObservabilityCmd = &cobra.Command{
+		Use:   "observability",
+		Short: "Configure the logging, the metrics and the tracing for Knative Serving and Eventing",
+		Example: `
+  # Configure the metrics backend and the tracing with zipkin for Knative Serving
+  kn operation configure observability --component serving --metrics-backend prometheus --tracing-backend zipkin --zipkin-endpoint http://zipkin.istio-system.svc.cluster.local:9411/api/v2/spans --sample-rate 0.1 --namespace knative-serving
+  # Configure the log level of the controller and the activator for Knative Serving
+  kn operation configure observability --component serving --log-level debug --components controller,activator --namespace knative-serving`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := ConfigureObservability(observabilityCMDFlags, p)
+			if err != nil {
+				return err
+			}
+			applied.set(observabilityCMDFlags.Component, observabilityCMDFlags.Namespace)
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The observability has been configured in the namespace '%s'.\n",
+				observabilityCMDFlags.Namespace)
+			return nil
+		},
+	}
+
+	configureObservabilityCmd.Flags().StringVar(&observabilityCMDFlags.MetricsBackend, "metrics-backend", "",
+		"The backend of the metrics: prometheus, opencensus or none")
+	configureObservabilityCmd.Flags().StringVar(&observabilityCMDFlags.OpenCensusAddress, "opencensus-address", "",
+		"The address as host:port of the OpenCensus collector")
+	configureObservabilityCmd.Flags().StringVar(&observabilityCMDFlags.TracingBackend, "tracing-backend", "",
+		"The backend of the tracing: zipkin or none")
+	configureObservabilityCmd.Flags().StringVar(&observabilityCMDFlags.ZipkinEndpoint, "zipkin-endpoint", "",
+		"The URL of the zipkin endpoint receiving the spans")
+	configureObservabilityCmd.Flags().StringVar(&observabilityCMDFlags.SampleRate, "sample-rate", "",
+		"The rate between 0 and 1 of the sampled requests")
+	configureObservabilityCmd.Flags().StringVar(&observabilityCMDFlags.LogLevel, "log-level", "",
+		"The log level: debug, info, warn, error, dpanic, panic or fatal")
+	configureObservabilityCmd.Flags().StringSliceVar(&observabilityCMDFlags.LogComponents, "components", []string{},
+		"The comma-separated components using the log level, e.g. controller,activator (default is all the main components)")
+	configureObservabilityCmd.Flags().StringVarP(&observabilityCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
+	configureObservabilityCmd.Flags().StringVarP(&observabilityCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+
+	return configureObservabilityCmd
+}
+
+func validateObservabilityFlags(observabilityCMDFlags ObservabilityFlags) error {
+	if observabilityCMDFlags.MetricsBackend == "" && observabilityCMDFlags.OpenCensusAddress == "" &&
+		observabilityCMDFlags.TracingBackend == "" && observabilityCMDFlags.ZipkinEndpoint == "" &&
+		observabilityCMDFlags.SampleRate == "" && observabilityCMDFlags.LogLevel == "" {
+		return fmt.Errorf("You need to specify the metrics, the tracing or the log level to configure.")
+	}
+	if observabilityCMDFlags.MetricsBackend != "" && !common.Contains(metricsBackends, observabilityCMDFlags.MetricsBackend) {
+		return fmt.Errorf("You need to specify the metrics backend: %s.", strings.Join(metricsBackends, ", "))
+	}
+	if observabilityCMDFlags.OpenCensusAddress != "" {
+		if observabilityCMDFlags.MetricsBackend != "" && observabilityCMDFlags.MetricsBackend != "opencensus" {
+			return fmt.Errorf("The OpenCensus address can only be set for the metrics backend opencensus.")
+		}
+		if _, _, err := net.SplitHostPort(observabilityCMDFlags.OpenCensusAddress); err != nil {
+			return fmt.Errorf("The OpenCensus address '%s' needs to be in the format host:port.", observabilityCMDFlags.OpenCensusAddress)
+		}
+	}
+	if observabilityCMDFlags.TracingBackend != "" && !common.Contains(tracingBackends, observabilityCMDFlags.TracingBackend) {
+		return fmt.Errorf("You need to specify the tracing backend: %s.", strings.Join(tracingBackends, ", "))
+	}
+	if observabilityCMDFlags.TracingBackend == "zipkin" && observabilityCMDFlags.ZipkinEndpoint == "" {
+		return fmt.Errorf("You need to specify the zipkin endpoint for the tracing backend zipkin.")
+	}
+	if observabilityCMDFlags.ZipkinEndpoint != "" {
+		if observabilityCMDFlags.TracingBackend == "none" {
+			return fmt.Errorf("The zipkin endpoint can only be set for the tracing backend zipkin.")
+		}
+		if err := validateEndpointURL(observabilityCMDFlags.ZipkinEndpoint); err != nil {
+			return err
+		}
+	}
+	if observabilityCMDFlags.SampleRate != "" {
+		rate, err := strconv.ParseFloat(observabilityCMDFlags.SampleRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return fmt.Errorf("The sample rate needs to be a number between 0 and 1.")
+		}
+	}
+	if observabilityCMDFlags.LogLevel != "" && !common.Contains(logLevels, observabilityCMDFlags.LogLevel) {
+		return fmt.Errorf("You need to specify the log level: %s.", strings.Join(logLevels, ", "))
+	}
+	if len(observabilityCMDFlags.LogComponents) > 0 && observabilityCMDFlags.LogLevel == "" {
+		return fmt.Errorf("You need to specify the log level of the components.")
+	}
+	if observabilityCMDFlags.Namespace == "" {
+		return fmt.Errorf("You need to specify the namespace.")
+	}
+	if !strings.EqualFold(observabilityCMDFlags.Component, common.ServingComponent) && !strings.EqualFold(observabilityCMDFlags.Component, common.EventingComponent) {
+		return fmt.Errorf("You need to specify the component for Knative: serving or eventing.")
+	}
+	return nil
+}
+
+// validateEndpointURL checks that the endpoint is an absolute HTTP or HTTPS URL
+func validateEndpointURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("The endpoint '%s' needs to be an absolute URL with the scheme http or https.", endpoint)
+	}
+	return nil
+}
+
+// ConfigureObservability sets the keys of the logging, the metrics and the tracing of the Knative component
+// in a single update
+func ConfigureObservability(observabilityCMDFlags ObservabilityFlags, p *pkg.OperatorParams) error {
+	if err := validateObservabilityFlags(observabilityCMDFlags); err != nil {
+		return err
+	}
+
+	component := common.ServingComponent
+	if strings.EqualFold(observabilityCMDFlags.Component, common.EventingComponent) {
+		component = common.EventingComponent
+	}
+	return common.ApplyKnativeCR(component, observabilityCMDFlags.Namespace, p,
+		observabilityMutators(component, observabilityCMDFlags)...)
+}
+
+// observabilityMutators maps the flags to the keys of the ConfigMaps logging, observability and tracing
+func observabilityMutators(component string, observabilityCMDFlags ObservabilityFlags) []common.SpecMutator {
+	mutators := []common.SpecMutator{}
+	if backend := observabilityCMDFlags.MetricsBackend; backend != "" {
+		mutators = append(mutators, common.SetConfigMapData("observability", "metrics.backend-destination", backend))
+		// Only Knative Serving reports the metrics of the requests
+		if component == common.ServingComponent {
+			mutators = append(mutators, common.SetConfigMapData("observability", "metrics.request-metrics-backend-destination", backend))
+		}
+	}
+	if address := observabilityCMDFlags.OpenCensusAddress; address != "" {
+		mutators = append(mutators, common.SetConfigMapData("observability", "metrics.opencensus-address", address))
+	}
+
+	backend := observabilityCMDFlags.TracingBackend
+	if backend == "" && observabilityCMDFlags.ZipkinEndpoint != "" {
+		backend = "zipkin"
+	}
+	if backend != "" {
+		mutators = append(mutators, common.SetConfigMapData("tracing", "backend", backend))
+	}
+	if endpoint := observabilityCMDFlags.ZipkinEndpoint; endpoint != "" {
+		mutators = append(mutators, common.SetConfigMapData("tracing", "zipkin-endpoint", endpoint))
+	}
+	if rate := observabilityCMDFlags.SampleRate; rate != "" {
+		mutators = append(mutators, common.SetConfigMapData("tracing", "sample-rate", rate))
+	}
+
+	if level := observabilityCMDFlags.LogLevel; level != "" {
+		components := observabilityCMDFlags.LogComponents
+		if len(components) == 0 {
+			components = defaultLogComponents[component]
+		}
+		for _, name := range components {
+			mutators = append(mutators, common.SetConfigMapData("logging", "loglevel."+strings.TrimSpace(name), level))
+		}
+	}
+	return mutators
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"fmt"
+	"testing"
+
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestValidateObservabilityFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name                  string
+		observabilityCMDFlags ObservabilityFlags
+		expectedResult        error
+	}{{
+		name: "Metrics and tracing",
+		observabilityCMDFlags: ObservabilityFlags{
+			MetricsBackend: "prometheus",
+			TracingBackend: "zipkin",
+			ZipkinEndpoint: "http://zipkin.istio-system.svc.cluster.local:9411/api/v2/spans",
+			SampleRate:     "0.1",
+			Component:      "serving",
+			Namespace:      "knative-serving",
+		},
+	}, {
+		name: "Log level of the components",
+		observabilityCMDFlags: ObservabilityFlags{
+			LogLevel:      "debug",
+			LogComponents: []string{"controller", "activator"},
+			Component:     "serving",
+			Namespace:     "knative-serving",
+		},
+	}, {
+		name:                  "Nothing to configure",
+		observabilityCMDFlags: ObservabilityFlags{Component: "serving", Namespace: "knative-serving"},
+		expectedResult:        fmt.Errorf("You need to specify the metrics, the tracing or the log level to configure."),
+	}, {
+		name: "Invalid metrics backend",
+		observabilityCMDFlags: ObservabilityFlags{
+			MetricsBackend: "stackdriver",
+			Component:      "serving",
+			Namespace:      "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the metrics backend: prometheus, opencensus, none."),
+	}, {
+		name: "OpenCensus address for prometheus",
+		observabilityCMDFlags: ObservabilityFlags{
+			MetricsBackend:    "prometheus",
+			OpenCensusAddress: "otel-collector:55678",
+			Component:         "eventing",
+			Namespace:         "knative-eventing",
+		},
+		expectedResult: fmt.Errorf("The OpenCensus address can only be set for the metrics backend opencensus."),
+	}, {
+		name: "Invalid OpenCensus address",
+		observabilityCMDFlags: ObservabilityFlags{
+			MetricsBackend:    "opencensus",
+			OpenCensusAddress: "otel-collector",
+			Component:         "eventing",
+			Namespace:         "knative-eventing",
+		},
+		expectedResult: fmt.Errorf("The OpenCensus address 'otel-collector' needs to be in the format host:port."),
+	}, {
+		name: "Zipkin without endpoint",
+		observabilityCMDFlags: ObservabilityFlags{
+			TracingBackend: "zipkin",
+			Component:      "serving",
+			Namespace:      "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the zipkin endpoint for the tracing backend zipkin."),
+	}, {
+		name: "Invalid zipkin endpoint",
+		observabilityCMDFlags: ObservabilityFlags{
+			ZipkinEndpoint: "zipkin:9411/api/v2/spans",
+			Component:      "serving",
+			Namespace:      "knative-serving",
+		},
+		expectedResult: fmt.Errorf("The endpoint 'zipkin:9411/api/v2/spans' needs to be an absolute URL with the scheme http or https."),
+	}, {
+		name: "Zipkin endpoint without tracing",
+		observabilityCMDFlags: ObservabilityFlags{
+			TracingBackend: "none",
+			ZipkinEndpoint: "http://zipkin:9411/api/v2/spans",
+			Component:      "serving",
+			Namespace:      "knative-serving",
+		},
+		expectedResult: fmt.Errorf("The zipkin endpoint can only be set for the tracing backend zipkin."),
+	}, {
+		name: "Sample rate out of range",
+		observabilityCMDFlags: ObservabilityFlags{
+			SampleRate: "10",
+			Component:  "serving",
+			Namespace:  "knative-serving",
+		},
+		expectedResult: fmt.Errorf("The sample rate needs to be a number between 0 and 1."),
+	}, {
+		name: "Invalid log level",
+		observabilityCMDFlags: ObservabilityFlags{
+			LogLevel:  "verbose",
+			Component: "serving",
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the log level: debug, info, warn, error, dpanic, panic, fatal."),
+	}, {
+		name: "Components without log level",
+		observabilityCMDFlags: ObservabilityFlags{
+			SampleRate:    "0.5",
+			LogComponents: []string{"controller"},
+			Component:     "serving",
+			Namespace:     "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the log level of the components."),
+	}, {
+		name: "No component",
+		observabilityCMDFlags: ObservabilityFlags{
+			LogLevel:  "info",
+			Namespace: "knative-serving",
+		},
+		expectedResult: fmt.Errorf("You need to specify the component for Knative: serving or eventing."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateObservabilityFlags(tt.observabilityCMDFlags)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
+
+func TestObservabilityMutators(t *testing.T) {
+	for _, tt := range []struct {
+		name                  string
+		component             string
+		observabilityCMDFlags ObservabilityFlags
+		expectedConfig        base.ConfigMapData
+	}{{
+		name:      "Knative Serving with all the settings",
+		component: common.ServingComponent,
+		observabilityCMDFlags: ObservabilityFlags{
+			MetricsBackend: "prometheus",
+			ZipkinEndpoint: "http://zipkin:9411/api/v2/spans",
+			SampleRate:     "0.1",
+			LogLevel:       "debug",
+			LogComponents:  []string{"controller", "activator"},
+		},
+		expectedConfig: base.ConfigMapData{
+			"observability": {
+				"metrics.backend-destination":                 "prometheus",
+				"metrics.request-metrics-backend-destination": "prometheus",
+			},
+			"tracing": {
+				"backend":         "zipkin",
+				"zipkin-endpoint": "http://zipkin:9411/api/v2/spans",
+				"sample-rate":     "0.1",
+			},
+			"logging": {
+				"loglevel.controller": "debug",
+				"loglevel.activator":  "debug",
+			},
+		},
+	}, {
+		name:      "Knative Eventing with the default log components",
+		component: common.EventingComponent,
+		observabilityCMDFlags: ObservabilityFlags{
+			MetricsBackend:    "opencensus",
+			OpenCensusAddress: "otel-collector:55678",
+			LogLevel:          "warn",
+		},
+		expectedConfig: base.ConfigMapData{
+			"observability": {
+				"metrics.backend-destination": "opencensus",
+				"metrics.opencensus-address":  "otel-collector:55678",
+			},
+			"logging": {
+				"loglevel.controller": "warn",
+				"loglevel.webhook":    "warn",
+			},
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1beta1.KnativeServingSpec{}
+			err := common.NewKnativeServingSpec(&spec).Mutate(observabilityMutators(tt.component, tt.observabilityCMDFlags)...)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.Config, tt.expectedConfig)
+		})
+	}
+}
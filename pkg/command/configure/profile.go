@@ -0,0 +1,349 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+	"knative.dev/operator/pkg/apis/operator/base"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+//go:embed profiles/*.yaml
+var profileFiles embed.FS
+
+// ProfileNames are the names of the curated profiles shipped with the plugin
+var ProfileNames = []string{"production", "development", "minimal"}
+
+// Profile is a named and versioned set of overrides of the Knative custom resources
+type Profile struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	// Serving and Eventing are the overrides of the specs of Knative Serving and Eventing
+	Serving  *base.CommonSpec `json:"serving,omitempty"`
+	Eventing *base.CommonSpec `json:"eventing,omitempty"`
+}
+
+type ProfileFlags struct {
+	File      string
+	Component string
+	Namespace string
+	Yes       bool
+	DryRun    bool
+}
+
+// newProfileCommand represents the configure command to apply a profile to Knative Serving or Eventing
+func newProfileCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var profileCMDFlags ProfileFlags
+	var configureProfileCmd = &cobra.Command{
+		Use:   "profile production|development|minimal",
+		Short: "Configure Knative Serving or Eventing with a curated or a custom profile",
+		Example: `
+  # Configure Knative Serving with the production profile, after confirming the difference
+  kn operation configure profile production --component serving --namespace knative-serving
+  # Show the difference made by the minimal profile to Knative Eventing without applying it
+  kn operation configure profile minimal --component eventing --namespace knative-eventing --dry-run
+  # Configure Knative Serving with the profile in a file without confirmation
+  kn operation configure profile --file my-profile.yaml --component serving --namespace knative-serving --yes`,
+		ValidArgs: ProfileNames,
+		Args:      cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if err := validateProfileFlags(name, profileCMDFlags); err != nil {
+				return err
+			}
+			profile, err := LoadProfile(name, profileCMDFlags.File)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			diff, resourceVersion, err := DiffProfile(profile, profileCMDFlags, p)
+			if err != nil {
+				return err
+			}
+			if diff == "" {
+				fmt.Fprintf(out, "The profile '%s' (version %s) is already applied in the namespace '%s'.\n",
+					profile.Name, profile.Version, profileCMDFlags.Namespace)
+				return nil
+			}
+			fmt.Fprintf(out, "The profile '%s' (version %s) changes the spec of Knative %s in the namespace '%s':\n%s\n",
+				profile.Name, profile.Version, profileCMDFlags.Component, profileCMDFlags.Namespace, diff)
+			if profileCMDFlags.DryRun {
+				return nil
+			}
+			if !profileCMDFlags.Yes {
+				if !isTerminal(cmd.InOrStdin()) {
+					return fmt.Errorf("The profile cannot be confirmed without a terminal, please use --yes to apply it.")
+				}
+				if !confirm(cmd.InOrStdin(), out, "Apply the profile? [y/N]: ") {
+					fmt.Fprintln(out, "The profile has not been applied.")
+					return nil
+				}
+			}
+
+			// The profile is only applied to the custom resource, which the difference has been shown for
+			if err := ConfigureProfile(profile, profileCMDFlags, resourceVersion, p); err != nil {
+				return err
+			}
+			applied.set(profileCMDFlags.Component, profileCMDFlags.Namespace)
+			fmt.Fprintf(out, "The profile '%s' (version %s) has been applied in the namespace '%s'.\n",
+				profile.Name, profile.Version, profileCMDFlags.Namespace)
+			return nil
+		},
+	}
+
+	configureProfileCmd.Flags().StringVarP(&profileCMDFlags.File, "file", "f", "", "The path of the custom profile file")
+	configureProfileCmd.Flags().BoolVarP(&profileCMDFlags.Yes, "yes", "y", false, "The flag to apply the profile without confirmation")
+	configureProfileCmd.Flags().BoolVar(&profileCMDFlags.DryRun, "dry-run", false, "The flag to only show the difference made by the profile")
+	configureProfileCmd.Flags().StringVarP(&profileCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
+	configureProfileCmd.Flags().StringVarP(&profileCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+
+	return configureProfileCmd
+}
+
+func validateProfileFlags(name string, profileCMDFlags ProfileFlags) error {
+	if name == "" && profileCMDFlags.File == "" {
+		return fmt.Errorf("You need to specify the profile: %s, or the profile file.", strings.Join(ProfileNames, ", "))
+	}
+	if name != "" && profileCMDFlags.File != "" {
+		return fmt.Errorf("You can specify either the profile or the profile file.")
+	}
+	if name != "" && !common.Contains(ProfileNames, name) {
+		return fmt.Errorf("The profile '%s' is not known, please use one of %s.", name, strings.Join(ProfileNames, ", "))
+	}
+	if profileCMDFlags.Namespace == "" {
+		return fmt.Errorf("You need to specify the namespace.")
+	}
+	if !strings.EqualFold(profileCMDFlags.Component, common.ServingComponent) && !strings.EqualFold(profileCMDFlags.Component, common.EventingComponent) {
+		return fmt.Errorf("You need to specify the component for Knative: serving or eventing.")
+	}
+	return nil
+}
+
+// LoadProfile loads the curated profile of the name, or the custom profile in the file. The unknown fields are
+// rejected, so that a typo is not silently ignored.
+func LoadProfile(name, file string) (*Profile, error) {
+	var content []byte
+	var err error
+	if file != "" {
+		content, err = os.ReadFile(file)
+	} else {
+		content, err = profileFiles.ReadFile(fmt.Sprintf("profiles/%s.yaml", name))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	profile := &Profile{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(profile); err != nil {
+		return nil, fmt.Errorf("The profile is invalid: %v.", err)
+	}
+	if profile.Name == "" {
+		profile.Name = name
+		if file != "" {
+			profile.Name = file
+		}
+	}
+	if profile.Version == "" {
+		profile.Version = "unversioned"
+	}
+	return profile, nil
+}
+
+// componentSpec returns the overrides of the profile for the component, which can only set the high availability,
+// the deployments, the pod disruption budgets and the ConfigMaps
+func (profile *Profile) componentSpec(component string) (*base.CommonSpec, error) {
+	spec := profile.Serving
+	if strings.EqualFold(component, common.EventingComponent) {
+		spec = profile.Eventing
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("The profile '%s' has no overrides for Knative %s.", profile.Name, component)
+	}
+
+	supported := base.CommonSpec{
+		Config:                      spec.Config,
+		DeploymentOverride:          spec.DeploymentOverride,
+		HighAvailability:            spec.HighAvailability,
+		PodDisruptionBudgetOverride: spec.PodDisruptionBudgetOverride,
+	}
+	specData, _ := json.Marshal(spec)
+	supportedData, _ := json.Marshal(supported)
+	if !bytes.Equal(specData, supportedData) {
+		return nil, fmt.Errorf("The profile '%s' can only set high-availability, deployments, podDisruptionBudgets and config.", profile.Name)
+	}
+	for _, override := range spec.DeploymentOverride {
+		if len(override.Env) > 0 || len(override.ReadinessProbes) > 0 || len(override.LivenessProbes) > 0 || override.HostNetwork != nil {
+			return nil, fmt.Errorf("The profile '%s' cannot set env, readinessProbes, livenessProbes or hostNetwork of the deployment '%s'.",
+				profile.Name, override.Name)
+		}
+	}
+	return spec, nil
+}
+
+// DiffProfile returns the difference made by the profile to the Knative custom resource, without applying it,
+// together with the resource version of the custom resource the difference is made on
+func DiffProfile(profile *Profile, profileCMDFlags ProfileFlags, p *pkg.OperatorParams) (string, string, error) {
+	component, spec, err := profileComponentSpec(profile, profileCMDFlags)
+	if err != nil {
+		return "", "", err
+	}
+	return common.DiffKnativeCR(component, profileCMDFlags.Namespace, p, profileMutator(spec))
+}
+
+// ConfigureProfile applies all the overrides of the profile to the Knative custom resource in a single update.
+// It fails, if the custom resource is not at the resource version any more, so that the applied spec is the one
+// of the difference. Nothing is checked for an empty resource version.
+func ConfigureProfile(profile *Profile, profileCMDFlags ProfileFlags, resourceVersion string, p *pkg.OperatorParams) error {
+	component, spec, err := profileComponentSpec(profile, profileCMDFlags)
+	if err != nil {
+		return err
+	}
+	err = common.ApplyKnativeCR(component, profileCMDFlags.Namespace, p,
+		common.ExpectResourceVersion(resourceVersion), profileMutator(spec))
+	if errors.Is(err, common.ErrResourceVersionChanged) {
+		return fmt.Errorf("Knative %s in the namespace '%s' has been changed since the difference was shown, "+
+			"please run the command again.", component, profileCMDFlags.Namespace)
+	}
+	return err
+}
+
+func profileComponentSpec(profile *Profile, profileCMDFlags ProfileFlags) (string, *base.CommonSpec, error) {
+	component := common.ServingComponent
+	if strings.EqualFold(profileCMDFlags.Component, common.EventingComponent) {
+		component = common.EventingComponent
+	}
+	spec, err := profile.componentSpec(component)
+	return component, spec, err
+}
+
+// profileMutator merges the overrides of the profile into the spec. The fields set by the profile replace the
+// existing ones, and the others are kept.
+func profileMutator(profileSpec *base.CommonSpec) common.SpecMutator {
+	return func(spec *common.KnativeSpec) error {
+		if profileSpec.HighAvailability != nil {
+			spec.HighAvailability = profileSpec.HighAvailability.DeepCopy()
+		}
+		for _, override := range profileSpec.DeploymentOverride {
+			override := override
+			err := common.UpdateDeploymentOverride(override.Name, func(current *base.WorkloadOverride) error {
+				mergeWorkloadOverride(current, override.DeepCopy())
+				return nil
+			})(spec)
+			if err != nil {
+				return err
+			}
+		}
+		for _, pdb := range profileSpec.PodDisruptionBudgetOverride {
+			spec.PodDisruptionBudgetOverride = setPodDisruptionBudget(spec.PodDisruptionBudgetOverride, *pdb.DeepCopy())
+		}
+		for cmName, data := range profileSpec.Config {
+			for key, value := range data {
+				if err := common.SetConfigMapData(cmName, key, value)(spec); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func mergeWorkloadOverride(current, override *base.WorkloadOverride) {
+	if override.Replicas != nil {
+		current.Replicas = override.Replicas
+	}
+	for key, value := range override.Labels {
+		current.Labels = common.SetMapValue(current.Labels, key, value)
+	}
+	for key, value := range override.Annotations {
+		current.Annotations = common.SetMapValue(current.Annotations, key, value)
+	}
+	for key, value := range override.NodeSelector {
+		current.NodeSelector = common.SetMapValue(current.NodeSelector, key, value)
+	}
+	if len(override.Tolerations) > 0 {
+		current.Tolerations = override.Tolerations
+	}
+	if override.Affinity != nil {
+		current.Affinity = override.Affinity
+	}
+	if len(override.TopologySpreadConstraints) > 0 {
+		current.TopologySpreadConstraints = override.TopologySpreadConstraints
+	}
+	for _, resource := range override.Resources {
+		replaced := false
+		for i := range current.Resources {
+			if current.Resources[i].Container == resource.Container {
+				current.Resources[i] = resource
+				replaced = true
+			}
+		}
+		if !replaced {
+			current.Resources = append(current.Resources, resource)
+		}
+	}
+}
+
+func setPodDisruptionBudget(pdbs []base.PodDisruptionBudgetOverride, pdb base.PodDisruptionBudgetOverride) []base.PodDisruptionBudgetOverride {
+	for i := range pdbs {
+		if pdbs[i].Name == pdb.Name {
+			pdbs[i] = pdb
+			return pdbs
+		}
+	}
+	return append(pdbs, pdb)
+}
+
+// isTerminal checks if the input is an interactive terminal, which can answer the confirmation
+func isTerminal(in io.Reader) bool {
+	file, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm asks the question, and returns true if the answer is yes
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprint(out, question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
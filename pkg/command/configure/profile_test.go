@@ -0,0 +1,237 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestValidateProfileFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		profile         string
+		profileCMDFlags ProfileFlags
+		expectedResult  error
+	}{{
+		name:            "Curated profile",
+		profile:         "production",
+		profileCMDFlags: ProfileFlags{Component: "serving", Namespace: "knative-serving"},
+	}, {
+		name:            "Profile file",
+		profileCMDFlags: ProfileFlags{File: "profile.yaml", Component: "eventing", Namespace: "knative-eventing"},
+	}, {
+		name:            "No profile",
+		profileCMDFlags: ProfileFlags{Component: "serving", Namespace: "knative-serving"},
+		expectedResult:  fmt.Errorf("You need to specify the profile: production, development, minimal, or the profile file."),
+	}, {
+		name:            "Profile and file",
+		profile:         "minimal",
+		profileCMDFlags: ProfileFlags{File: "profile.yaml", Component: "serving", Namespace: "knative-serving"},
+		expectedResult:  fmt.Errorf("You can specify either the profile or the profile file."),
+	}, {
+		name:            "Unknown profile",
+		profile:         "staging",
+		profileCMDFlags: ProfileFlags{Component: "serving", Namespace: "knative-serving"},
+		expectedResult:  fmt.Errorf("The profile 'staging' is not known, please use one of production, development, minimal."),
+	}, {
+		name:            "No namespace",
+		profile:         "production",
+		profileCMDFlags: ProfileFlags{Component: "serving"},
+		expectedResult:  fmt.Errorf("You need to specify the namespace."),
+	}, {
+		name:            "No component",
+		profile:         "production",
+		profileCMDFlags: ProfileFlags{Namespace: "knative-serving"},
+		expectedResult:  fmt.Errorf("You need to specify the component for Knative: serving or eventing."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateProfileFlags(tt.profile, tt.profileCMDFlags)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	for _, name := range ProfileNames {
+		t.Run(name, func(t *testing.T) {
+			profile, err := LoadProfile(name, "")
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, profile.Name, name)
+			testingUtil.AssertEqual(t, profile.Version, "1")
+			for _, component := range []string{common.ServingComponent, common.EventingComponent} {
+				_, err := profile.componentSpec(component)
+				testingUtil.AssertEqual(t, err, nil)
+			}
+		})
+	}
+}
+
+func TestLoadProfileFile(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		content       string
+		component     string
+		expectedError error
+	}{{
+		name: "Custom profile",
+		content: `name: custom
+version: "2"
+serving:
+  high-availability:
+    replicas: 3
+`,
+		component: common.ServingComponent,
+	}, {
+		name: "Unknown field",
+		content: `name: custom
+serving:
+  high-availabilty:
+    replicas: 3
+`,
+		component:     common.ServingComponent,
+		expectedError: fmt.Errorf("The profile is invalid: json: unknown field \"high-availabilty\"."),
+	}, {
+		name: "Unsupported field",
+		content: `name: custom
+serving:
+  version: "1.8"
+`,
+		component:     common.ServingComponent,
+		expectedError: fmt.Errorf("The profile 'custom' can only set high-availability, deployments, podDisruptionBudgets and config."),
+	}, {
+		name: "Unsupported field of the deployment",
+		content: `name: custom
+serving:
+  deployments:
+  - name: controller
+    hostNetwork: true
+`,
+		component:     common.ServingComponent,
+		expectedError: fmt.Errorf("The profile 'custom' cannot set env, readinessProbes, livenessProbes or hostNetwork of the deployment 'controller'."),
+	}, {
+		name: "No overrides for the component",
+		content: `name: custom
+serving:
+  high-availability:
+    replicas: 3
+`,
+		component:     common.EventingComponent,
+		expectedError: fmt.Errorf("The profile 'custom' has no overrides for Knative eventing."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "profile.yaml")
+			testingUtil.AssertEqual(t, os.WriteFile(file, []byte(tt.content), 0600), nil)
+			profile, err := LoadProfile("", file)
+			if err == nil {
+				_, err = profile.componentSpec(tt.component)
+			}
+			if tt.expectedError == nil {
+				testingUtil.AssertEqual(t, err, nil)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			}
+		})
+	}
+}
+
+func TestProfileMutator(t *testing.T) {
+	two, three := int32(2), int32(3)
+	spec := v1beta1.KnativeServingSpec{}
+	spec.Config = base.ConfigMapData{"autoscaler": {"max-scale": "5"}}
+	spec.DeploymentOverride = []base.WorkloadOverride{{
+		Name:     "controller",
+		Replicas: &three,
+		Labels:   map[string]string{"team": "serving"},
+		Resources: []base.ResourceRequirementsOverride{{
+			Container: "controller",
+			ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+			},
+		}},
+	}}
+	spec.PodDisruptionBudgetOverride = []base.PodDisruptionBudgetOverride{{Name: "activator-pdb"}}
+
+	profileSpec := &base.CommonSpec{
+		HighAvailability: &base.HighAvailability{Replicas: &two},
+		DeploymentOverride: []base.WorkloadOverride{{
+			Name:   "controller",
+			Labels: map[string]string{"tier": "control-plane"},
+			Resources: []base.ResourceRequirementsOverride{{
+				Container: "controller",
+				ResourceRequirements: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+			}},
+		}},
+		PodDisruptionBudgetOverride: []base.PodDisruptionBudgetOverride{{Name: "webhook-pdb"}},
+		Config:                      base.ConfigMapData{"autoscaler": {"min-scale": "1"}},
+	}
+
+	err := common.NewKnativeServingSpec(&spec).Mutate(profileMutator(profileSpec))
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, *spec.HighAvailability.Replicas, two)
+	testingUtil.AssertEqual(t, len(spec.DeploymentOverride), 1)
+	controller := spec.DeploymentOverride[0]
+	testingUtil.AssertEqual(t, *controller.Replicas, three)
+	testingUtil.AssertDeepEqual(t, controller.Labels, map[string]string{"team": "serving", "tier": "control-plane"})
+	testingUtil.AssertEqual(t, len(controller.Resources), 1)
+	testingUtil.AssertEqual(t, controller.Resources[0].Requests.Cpu().String(), "100m")
+	testingUtil.AssertEqual(t, len(spec.PodDisruptionBudgetOverride), 2)
+	testingUtil.AssertDeepEqual(t, spec.Config, base.ConfigMapData{"autoscaler": {"max-scale": "5", "min-scale": "1"}})
+}
+
+func TestIsTerminal(t *testing.T) {
+	testingUtil.AssertEqual(t, isTerminal(strings.NewReader("y\n")), false)
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "answer"))
+	testingUtil.AssertEqual(t, err, nil)
+	defer file.Close()
+	testingUtil.AssertEqual(t, isTerminal(file), false)
+}
+
+func TestConfigureProfileResourceVersionChanged(t *testing.T) {
+	ks := &v1beta1.KnativeServing{}
+	ks.Name, ks.Namespace, ks.ResourceVersion = common.KnativeServingName, "knative-serving", "10"
+	operatorClient := testingUtil.NewFakeOperatorClient(ks)
+	p := testingUtil.NewFakeOperatorParams(nil, operatorClient)
+
+	profile, err := LoadProfile("minimal", "")
+	testingUtil.AssertEqual(t, err, nil)
+	err = ConfigureProfile(profile, ProfileFlags{Component: common.ServingComponent, Namespace: "knative-serving"}, "9", p)
+	if err == nil {
+		t.Fatal("expected the profile not to be applied to the changed custom resource")
+	}
+	testingUtil.AssertEqual(t, err.Error(), "Knative serving in the namespace 'knative-serving' has been changed since "+
+		"the difference was shown, please run the command again.")
+	// The custom resource is read once and not patched
+	testingUtil.AssertEqual(t, len(operatorClient.Actions()), 1)
+	testingUtil.AssertEqual(t, operatorClient.Actions()[0].GetVerb(), "get")
+}
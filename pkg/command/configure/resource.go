@@ -15,22 +15,18 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_resource_base.yaml
-var servingResourceOverlay string
-
-//go:embed overlay/ke_resource_base.yaml
-var eventingResourceOverlay string
-
 type ResourcesFlags struct {
 	LimitCPU      string
 	LimitMemory   string
@@ -42,10 +38,9 @@ type ResourcesFlags struct {
 	DeployName    string
 }
 
-var resourcesCMDFlags ResourcesFlags
-
 // newResourcesCommand represents the configure commands for Knative Serving or Eventing
-func newResourcesCommand(p *pkg.OperatorParams) *cobra.Command {
+func newResourcesCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var resourcesCMDFlags ResourcesFlags
 	var configureResourcesCmd = &cobra.Command{
 		Use:   "resources",
 		Short: "Configure the resource for Knative Serving and Eventing deployments",
@@ -53,14 +48,11 @@ func newResourcesCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the resource for Knative Serving and Eventing deployments
   kn operation configure resources --component eventing --deployName eventing-controller --container eventing-controller --requestMemory 200Mi --requestCPU 200m --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateResourcesFlags(resourcesCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureResources(resourcesCMDFlags, p)
+			err := ConfigureResources(resourcesCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(resourcesCMDFlags.Component, resourcesCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified resources have been configured in the namespace '%s'.\n",
 				resourcesCMDFlags.Namespace)
@@ -118,120 +110,70 @@ func validateResourcesFlags(resourcesCMDFlags ResourcesFlags) error {
 	return nil
 }
 
-func configureResources(resourcesCMDFlags ResourcesFlags, p *pkg.OperatorParams) error {
+// ConfigureResources sets the resource requests and limits for a container of a Knative deployment
+func ConfigureResources(resourcesCMDFlags ResourcesFlags, p *pkg.OperatorParams) error {
+	if err := validateResourcesFlags(resourcesCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
 	if strings.EqualFold(resourcesCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, resourcesCMDFlags.Namespace, p)
-	if err != nil {
+	if err := common.CheckDeployment(component, resourcesCMDFlags.Namespace, resourcesCMDFlags.DeployName, resourcesCMDFlags.Container, p); err != nil {
 		return err
 	}
-
-	overlayContent := getOverlayYamlContentResource(resourcesCMDFlags)
-	valuesYaml := getYamlValuesContentResources(resourcesCMDFlags)
-
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
-		return err
-	}
-	return nil
+	return common.ApplyKnativeCR(component, resourcesCMDFlags.Namespace, p, resourceMutator(resourcesCMDFlags))
 }
 
-func getOverlayYamlContentResource(resourcesCMDFlags ResourcesFlags) string {
-	baseOverlayContent := servingResourceOverlay
-	if strings.EqualFold(resourcesCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingResourceOverlay
-	}
-	resourceContent := getResourceConfiguration(resourcesCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
+// resourceMutator sets the resource requests and limits for the container of the deployment
+func resourceMutator(resourcesCMDFlags ResourcesFlags) common.SpecMutator {
+	return common.UpdateDeploymentOverride(resourcesCMDFlags.DeployName, func(override *base.WorkloadOverride) error {
+		for i := range override.Resources {
+			if override.Resources[i].Container == resourcesCMDFlags.Container {
+				return setResourceRequirements(&override.Resources[i].ResourceRequirements, resourcesCMDFlags)
+			}
+		}
+
+		resourceOverride := base.ResourceRequirementsOverride{
+			Container: resourcesCMDFlags.Container,
+		}
+		if err := setResourceRequirements(&resourceOverride.ResourceRequirements, resourcesCMDFlags); err != nil {
+			return err
+		}
+		override.Resources = append(override.Resources, resourceOverride)
+		return nil
+	})
 }
 
-func getResourceConfiguration(resourcesCMDFlags ResourcesFlags) string {
-	resourceArray := []string{}
-	tag := fmt.Sprintf("%s%s", common.Spaces(6), common.FieldByName("container"))
-	resourceArray = append(resourceArray, tag)
-
-	containerField := fmt.Sprintf("%s%s", common.Spaces(4), "- container: #@ data.values.container")
-	resourceArray = append(resourceArray, containerField)
-
-	if resourcesCMDFlags.RequestCPU != "" || resourcesCMDFlags.RequestMemory != "" {
-		tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		requestField := fmt.Sprintf("%s%s", common.Spaces(6), "requests:")
-		resourceArray = append(resourceArray, requestField)
-	}
-
-	if resourcesCMDFlags.RequestCPU != "" {
-		tag = fmt.Sprintf("%s%s", common.Spaces(8), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		requestCPUField := fmt.Sprintf("%s%s", common.Spaces(8), "cpu: #@ data.values.requestCPU")
-		resourceArray = append(resourceArray, requestCPUField)
-	}
-
-	if resourcesCMDFlags.RequestMemory != "" {
-		tag = fmt.Sprintf("%s%s", common.Spaces(8), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		requestMemoryField := fmt.Sprintf("%s%s", common.Spaces(8), "memory: #@ data.values.requestMemory")
-		resourceArray = append(resourceArray, requestMemoryField)
+func setResourceRequirements(requirements *corev1.ResourceRequirements, resourcesCMDFlags ResourcesFlags) error {
+	var err error
+	if requirements.Requests, err = setResourceQuantity(requirements.Requests, corev1.ResourceCPU, resourcesCMDFlags.RequestCPU); err != nil {
+		return err
 	}
-
-	if resourcesCMDFlags.LimitCPU != "" || resourcesCMDFlags.LimitMemory != "" {
-		tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		limitField := fmt.Sprintf("%s%s", common.Spaces(6), "limits:")
-		resourceArray = append(resourceArray, limitField)
+	if requirements.Requests, err = setResourceQuantity(requirements.Requests, corev1.ResourceMemory, resourcesCMDFlags.RequestMemory); err != nil {
+		return err
 	}
-
-	if resourcesCMDFlags.LimitCPU != "" {
-		tag = fmt.Sprintf("%s%s", common.Spaces(8), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		limitCPUField := fmt.Sprintf("%s%s", common.Spaces(8), "cpu: #@ data.values.limitCPU")
-		resourceArray = append(resourceArray, limitCPUField)
+	if requirements.Limits, err = setResourceQuantity(requirements.Limits, corev1.ResourceCPU, resourcesCMDFlags.LimitCPU); err != nil {
+		return err
 	}
-
-	if resourcesCMDFlags.LimitMemory != "" {
-		tag = fmt.Sprintf("%s%s", common.Spaces(8), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		limitMemoryField := fmt.Sprintf("%s%s", common.Spaces(8), "memory: #@ data.values.limitMemory")
-		resourceArray = append(resourceArray, limitMemoryField)
+	if requirements.Limits, err = setResourceQuantity(requirements.Limits, corev1.ResourceMemory, resourcesCMDFlags.LimitMemory); err != nil {
+		return err
 	}
-
-	return strings.Join(resourceArray, "\n")
+	return nil
 }
 
-func getYamlValuesContentResources(resourcesCMDFlags ResourcesFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-	namespace := fmt.Sprintf("namespace: %s", resourcesCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-
-	container := fmt.Sprintf("container: %s", resourcesCMDFlags.Container)
-	contentArray = append(contentArray, container)
-
-	deploy := fmt.Sprintf("deployName: %s", resourcesCMDFlags.DeployName)
-	contentArray = append(contentArray, deploy)
-
-	if resourcesCMDFlags.RequestCPU != "" {
-		requestCPU := fmt.Sprintf("requestCPU: %s", resourcesCMDFlags.RequestCPU)
-		contentArray = append(contentArray, requestCPU)
-	}
-
-	if resourcesCMDFlags.RequestMemory != "" {
-		requestMemory := fmt.Sprintf("requestMemory: %s", resourcesCMDFlags.RequestMemory)
-		contentArray = append(contentArray, requestMemory)
+func setResourceQuantity(resources corev1.ResourceList, name corev1.ResourceName, value string) (corev1.ResourceList, error) {
+	if value == "" {
+		return resources, nil
 	}
-
-	if resourcesCMDFlags.LimitCPU != "" {
-		limitCPU := fmt.Sprintf("limitCPU: %s", resourcesCMDFlags.LimitCPU)
-		contentArray = append(contentArray, limitCPU)
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resources, fmt.Errorf("The value %s of the %s is invalid: %w", value, name, err)
 	}
-
-	if resourcesCMDFlags.LimitMemory != "" {
-		limitMemory := fmt.Sprintf("limitMemory: %s", resourcesCMDFlags.LimitMemory)
-		contentArray = append(contentArray, limitMemory)
+	if resources == nil {
+		resources = corev1.ResourceList{}
 	}
-
-	return strings.Join(contentArray, "\n")
+	resources[name] = quantity
+	return resources, nil
 }
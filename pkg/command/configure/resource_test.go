@@ -18,321 +18,15 @@ import (
 	"fmt"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-func TestGetOverlayYamlContentSource(t *testing.T) {
-	for _, tt := range []struct {
-		name              string
-		resourcesCMDFlags ResourcesFlags
-		expectedResult    string
-	}{{
-		name: "Knative Eventing",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitCPU:      "2.5G",
-			LimitMemory:   "1001M",
-			RequestCPU:    "2.2G",
-			RequestMemory: "999M",
-			Component:     "eventing",
-			Namespace:     "test-eventing",
-			Container:     "eventing-controller",
-			DeployName:    "eventing-controller",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    resources:
-
-      #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.container
-      #@overlay/match missing_ok=True
-      requests:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.requestCPU
-        #@overlay/match missing_ok=True
-        memory: #@ data.values.requestMemory
-      #@overlay/match missing_ok=True
-      limits:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.limitCPU
-        #@overlay/match missing_ok=True
-        memory: #@ data.values.limitMemory`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitCPU:      "2.5G",
-			LimitMemory:   "1001M",
-			RequestCPU:    "2.2G",
-			RequestMemory: "999M",
-			Component:     "serving",
-			Namespace:     "test-serving",
-			Container:     "activator",
-			DeployName:    "activator",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    resources:
-
-      #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.container
-      #@overlay/match missing_ok=True
-      requests:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.requestCPU
-        #@overlay/match missing_ok=True
-        memory: #@ data.values.requestMemory
-      #@overlay/match missing_ok=True
-      limits:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.limitCPU
-        #@overlay/match missing_ok=True
-        memory: #@ data.values.limitMemory`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitMemory: "1001M",
-			RequestCPU:  "2.2G",
-			Component:   "serving",
-			Namespace:   "test-serving",
-			Container:   "activator",
-			DeployName:  "activator",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    resources:
-
-      #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.container
-      #@overlay/match missing_ok=True
-      requests:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.requestCPU
-      #@overlay/match missing_ok=True
-      limits:
-        #@overlay/match missing_ok=True
-        memory: #@ data.values.limitMemory`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			RequestCPU: "2.2G",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			Container:  "activator",
-			DeployName: "activator",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    resources:
-
-      #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.container
-      #@overlay/match missing_ok=True
-      requests:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.requestCPU`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitCPU:   "2.2G",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			Container:  "activator",
-			DeployName: "activator",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    resources:
-
-      #@overlay/match by="container",missing_ok=True
-    - container: #@ data.values.container
-      #@overlay/match missing_ok=True
-      limits:
-        #@overlay/match missing_ok=True
-        cpu: #@ data.values.limitCPU`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentResource(tt.resourcesCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentResources(t *testing.T) {
-	for _, tt := range []struct {
-		name              string
-		resourcesCMDFlags ResourcesFlags
-		expectedResult    string
-	}{{
-		name: "Knative Eventing",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitCPU:      "2.5G",
-			LimitMemory:   "1001M",
-			RequestCPU:    "2.2G",
-			RequestMemory: "999M",
-			Component:     "eventing",
-			Namespace:     "test-eventing",
-			Container:     "eventing-controller",
-			DeployName:    "eventing-controller",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-container: eventing-controller
-deployName: eventing-controller
-requestCPU: 2.2G
-requestMemory: 999M
-limitCPU: 2.5G
-limitMemory: 1001M`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitCPU:      "2.5G",
-			LimitMemory:   "1001M",
-			RequestCPU:    "2.2G",
-			RequestMemory: "999M",
-			Component:     "serving",
-			Namespace:     "test-serving",
-			Container:     "activator",
-			DeployName:    "activator",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-container: activator
-deployName: activator
-requestCPU: 2.2G
-requestMemory: 999M
-limitCPU: 2.5G
-limitMemory: 1001M`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitMemory: "1001M",
-			RequestCPU:  "2.2G",
-			Component:   "serving",
-			Namespace:   "test-serving",
-			Container:   "activator",
-			DeployName:  "activator1",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-container: activator
-deployName: activator1
-requestCPU: 2.2G
-limitMemory: 1001M`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			RequestCPU: "2.2G",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			Container:  "activator",
-			DeployName: "activator",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-container: activator
-deployName: activator
-requestCPU: 2.2G`,
-	}, {
-		name: "Knative Serving",
-		resourcesCMDFlags: ResourcesFlags{
-			LimitCPU:   "22G",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			Container:  "activator",
-			DeployName: "activator",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-container: activator
-deployName: activator
-limitCPU: 22G`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentResources(tt.resourcesCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
 func TestValidateResourcesFlags(t *testing.T) {
 	for _, tt := range []struct {
 		name              string
@@ -408,3 +102,96 @@ func TestValidateResourcesFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceMutator(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		resourcesCMDFlags ResourcesFlags
+		input             []base.WorkloadOverride
+		expectedResult    []base.WorkloadOverride
+		expectedErr       bool
+	}{{
+		name: "Add the resources to a new deployment override",
+		resourcesCMDFlags: ResourcesFlags{
+			LimitCPU:    "1",
+			LimitMemory: "2Gi",
+			Component:   "serving",
+			Namespace:   "test-serving",
+			Container:   "activator",
+			DeployName:  "activator",
+		},
+		expectedResult: []base.WorkloadOverride{{
+			Name: "activator",
+			Resources: []base.ResourceRequirementsOverride{{
+				Container: "activator",
+				ResourceRequirements: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("2Gi"),
+					},
+				},
+			}},
+		}},
+	}, {
+		name: "Existing resources of the container are kept",
+		resourcesCMDFlags: ResourcesFlags{
+			RequestCPU:    "100m",
+			RequestMemory: "100Mi",
+			Component:     "serving",
+			Namespace:     "test-serving",
+			Container:     "activator",
+			DeployName:    "activator",
+		},
+		input: []base.WorkloadOverride{{
+			Name: "activator",
+			Resources: []base.ResourceRequirementsOverride{{
+				Container: "activator",
+				ResourceRequirements: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("10m"),
+					},
+				},
+			}},
+		}},
+		expectedResult: []base.WorkloadOverride{{
+			Name: "activator",
+			Resources: []base.ResourceRequirementsOverride{{
+				Container: "activator",
+				ResourceRequirements: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("100Mi"),
+					},
+				},
+			}},
+		}},
+	}, {
+		name: "Invalid quantity",
+		resourcesCMDFlags: ResourcesFlags{
+			RequestCPU: "one",
+			Component:  "serving",
+			Namespace:  "test-serving",
+			Container:  "activator",
+			DeployName: "activator",
+		},
+		expectedErr: true,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1beta1.KnativeServingSpec{}
+			spec.DeploymentOverride = tt.input
+			err := common.NewKnativeServingSpec(&spec).Mutate(resourceMutator(tt.resourcesCMDFlags))
+			if tt.expectedErr {
+				testingUtil.AssertEqual(t, err != nil, true)
+				return
+			}
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedResult)
+		})
+	}
+}
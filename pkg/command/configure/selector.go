@@ -15,7 +15,6 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
@@ -24,18 +23,12 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_deploy_label.yaml
-var servingSelectorOverlay string
-
-//go:embed overlay/ke_deploy_label.yaml
-var eventingSelectorOverlay string
-
-var selectorCMDFlags common.KeyValueFlags
-
 // newSelectorCommand represents the configure commands to configure the nodeSelector for Knative service
-func newSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
+func newSelectorCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var selectorCMDFlags common.KeyValueFlags
 	var configureNodeSelectorsCmd = &cobra.Command{
 		Use:   "selectors",
 		Short: "Configure the selectors for Knative Serving and Eventing services",
@@ -43,26 +36,24 @@ func newSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the selectors for Knative Serving and Eventing services
   kn operation selectors --component eventing --serviceName eventing-controller --key key --value value --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateSelectorFlags(nodeSelectorCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureSelectors(nodeSelectorCMDFlags, p)
+			err := ConfigureSelectors(selectorCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(selectorCMDFlags.Component, selectorCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified annotation has been configured for the deployment %s in the deployment '%s'.\n",
-				nodeSelectorCMDFlags.DeployName, nodeSelectorCMDFlags.Namespace)
+				selectorCMDFlags.DeployName, selectorCMDFlags.Namespace)
 			return nil
 		},
 	}
 
-	configureNodeSelectorsCmd.Flags().StringVar(&nodeSelectorCMDFlags.Key, "key", "", "The key of the data in the configmap")
-	configureNodeSelectorsCmd.Flags().StringVar(&nodeSelectorCMDFlags.Value, "value", "", "The value of the data in the configmap")
-	configureNodeSelectorsCmd.Flags().StringVar(&nodeSelectorCMDFlags.ServiceName, "serviceName", "", "The flag to specify the service name")
-	configureNodeSelectorsCmd.Flags().StringVarP(&nodeSelectorCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
-	configureNodeSelectorsCmd.Flags().StringVarP(&nodeSelectorCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+	configureNodeSelectorsCmd.Flags().StringVar(&selectorCMDFlags.Key, "key", "", "The key of the data in the configmap")
+	configureNodeSelectorsCmd.Flags().StringVar(&selectorCMDFlags.Value, "value", "", "The value of the data in the configmap")
+	configureNodeSelectorsCmd.Flags().StringVar(&selectorCMDFlags.ServiceName, "serviceName", "", "The flag to specify the service name")
+	configureNodeSelectorsCmd.Flags().StringVarP(&selectorCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
+	configureNodeSelectorsCmd.Flags().StringVarP(&selectorCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+	common.AddKeyValueSourceFlags(configureNodeSelectorsCmd.Flags(), &selectorCMDFlags.KeyValueSources)
 
 	return configureNodeSelectorsCmd
 }
@@ -77,60 +68,27 @@ func validateSelectorFlags(keyValuesCMDFlags common.KeyValueFlags) error {
 	return nil
 }
 
-func configureSelectors(nodeSelectorCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+// ConfigureSelectors sets the selector for a Knative service
+func ConfigureSelectors(selectorCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
+	if err := validateSelectorFlags(selectorCMDFlags); err != nil {
+		return err
+	}
+
 	component := common.ServingComponent
-	if strings.EqualFold(nodeSelectorCMDFlags.Component, common.EventingComponent) {
+	if strings.EqualFold(selectorCMDFlags.Component, common.EventingComponent) {
 		component = common.EventingComponent
 	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, nodeSelectorCMDFlags.Namespace, p)
+	mutators, err := keyValueMutators(selectorCMDFlags, selectorMutator)
 	if err != nil {
 		return err
 	}
-
-	overlayContent := getOverlayYamlContentSelector(nodeSelectorCMDFlags)
-	valuesYaml := getYamlValuesContent(nodeSelectorCMDFlags)
-	if err := common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
-		return err
-	}
-	return nil
+	return common.ApplyKnativeCR(component, selectorCMDFlags.Namespace, p, mutators...)
 }
 
-func getOverlayYamlContentSelector(nodeSelectorCMDFlags common.KeyValueFlags) string {
-	baseOverlayContent := servingSelectorOverlay
-	if strings.EqualFold(nodeSelectorCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingSelectorOverlay
-	}
-	resourceContent := getSelectorConfiguration(nodeSelectorCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
-}
-
-func getSelectorConfiguration(annotationCMDFlags common.KeyValueFlags) string {
-	resourceArray := []string{}
-
-	tag := fmt.Sprintf("%s%s", common.Spaces(2), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	field := fmt.Sprintf("%s%s:", common.Spaces(2), "services")
-	resourceArray = append(resourceArray, field)
-
-	field = fmt.Sprintf("%s%s", common.Spaces(2), common.FieldByName("name"))
-	resourceArray = append(resourceArray, field)
-
-	serviceName := fmt.Sprintf("%s- %s: %s", common.Spaces(2), "name", "#@ data.values.serviceName")
-	resourceArray = append(resourceArray, serviceName)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(4), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	field = fmt.Sprintf("%s%s:", common.Spaces(4), "selector")
-	resourceArray = append(resourceArray, field)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-
-	keyValueField := fmt.Sprintf("%s%s: %s", common.Spaces(6), annotationCMDFlags.Key, "#@ data.values.value")
-	resourceArray = append(resourceArray, keyValueField)
-
-	return strings.Join(resourceArray, "\n")
+// selectorMutator sets the selector for the service
+func selectorMutator(selectorCMDFlags common.KeyValueFlags) common.SpecMutator {
+	return common.UpdateServiceOverride(selectorCMDFlags.ServiceName, func(override *base.ServiceOverride) error {
+		override.Selector = common.SetMapValue(override.Selector, selectorCMDFlags.Key, selectorCMDFlags.Value)
+		return nil
+	})
 }
@@ -20,6 +20,8 @@ import (
 
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateSelectorFlags(t *testing.T) {
@@ -95,45 +97,53 @@ func TestValidateSelectorFlags(t *testing.T) {
 	}
 }
 
-func TestGetOverlayYamlContentSelector(t *testing.T) {
+func TestSelectorMutator(t *testing.T) {
 	for _, tt := range []struct {
-		name                    string
-		deploymentLabelCMDFlags common.KeyValueFlags
-		expectedResult          string
+		name             string
+		selectorCMDFlags common.KeyValueFlags
+		input            v1beta1.KnativeServingSpec
+		expectedResult   []base.ServiceOverride
 	}{{
-		name: "Knative Serving template for NodeSelector configuration",
-		deploymentLabelCMDFlags: common.KeyValueFlags{
-			Key:        "test-key",
-			Value:      "test-value",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "network",
+		name: "Add the selector to a new service override",
+		selectorCMDFlags: common.KeyValueFlags{
+			Key:         "test-key",
+			Value:       "test-value",
+			Component:   "serving",
+			Namespace:   "test-serving",
+			ServiceName: "network",
 		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-
-  #@overlay/match missing_ok=True
-  services:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.serviceName
-    #@overlay/match missing_ok=True
-    selector:
-      #@overlay/match missing_ok=True
-      test-key: #@ data.values.value`,
+		input: v1beta1.KnativeServingSpec{},
+		expectedResult: []base.ServiceOverride{{
+			Name:     "network",
+			Selector: map[string]string{"test-key": "test-value"},
+		}},
+	}, {
+		name: "Set the selector of the existing service override",
+		selectorCMDFlags: common.KeyValueFlags{
+			Key:         "test-key",
+			Value:       "test-value",
+			Component:   "serving",
+			Namespace:   "test-serving",
+			ServiceName: "network",
+		},
+		input: v1beta1.KnativeServingSpec{
+			CommonSpec: base.CommonSpec{
+				ServiceOverride: []base.ServiceOverride{{
+					Name:     "network",
+					Selector: map[string]string{"key": "value"},
+				}},
+			},
+		},
+		expectedResult: []base.ServiceOverride{{
+			Name:     "network",
+			Selector: map[string]string{"test-key": "test-value", "key": "value"},
+		}},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContentSelector(tt.deploymentLabelCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := tt.input
+			err := common.NewKnativeServingSpec(&spec).Mutate(selectorMutator(tt.selectorCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.ServiceOverride, tt.expectedResult)
 		})
 	}
 }
@@ -15,22 +15,17 @@
 package configure
 
 import (
-	_ "embed"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-//go:embed overlay/ks_toleration.yaml
-var servingTolerationOverlay string
-
-//go:embed overlay/ke_toleration.yaml
-var eventingTolerationOverlay string
-
 type TolerationsFlags struct {
 	Key        string
 	Operator   string
@@ -41,8 +36,6 @@ type TolerationsFlags struct {
 	DeployName string
 }
 
-var tolerationsCMDFlags TolerationsFlags
-
 func getValidOperators() []string {
 	return []string{"Exists", "Equal"}
 }
@@ -52,7 +45,8 @@ func getValidEffects() []string {
 }
 
 // newTolerationsCommand represents the configure commands for Knative Serving or Eventing
-func newTolerationsCommand(p *pkg.OperatorParams) *cobra.Command {
+func newTolerationsCommand(p *pkg.OperatorParams, applied *appliedResource) *cobra.Command {
+	var tolerationsCMDFlags TolerationsFlags
 	var configureTolerationsCmd = &cobra.Command{
 		Use:   "tolerations",
 		Short: "Configure the tolerations for Knative Serving and Eventing deployments",
@@ -60,14 +54,11 @@ func newTolerationsCommand(p *pkg.OperatorParams) *cobra.Command {
   # Configure the tolerations for Knative Serving and Eventing deployments
   kn operation configure tolerations --component eventing --deployName eventing-controller --key example-key --operator Exists --effect NoSchedule --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := validateTolerationsFlags(tolerationsCMDFlags); err != nil {
-				return err
-			}
-
-			err := configureTolerations(tolerationsCMDFlags, p)
+			err := ConfigureTolerations(tolerationsCMDFlags, p)
 			if err != nil {
 				return err
 			}
+			applied.set(tolerationsCMDFlags.Component, tolerationsCMDFlags.Namespace)
 
 			fmt.Fprintf(cmd.OutOrStdout(), "The specified tolerations have been configured in the namespace '%s'.\n",
 				tolerationsCMDFlags.Namespace)
@@ -111,91 +102,42 @@ func validateTolerationsFlags(tolerationsCMDFlags TolerationsFlags) error {
 	return nil
 }
 
-func configureTolerations(tolerationsCMDFlags TolerationsFlags, p *pkg.OperatorParams) error {
-	component := common.ServingComponent
-	if strings.EqualFold(tolerationsCMDFlags.Component, common.EventingComponent) {
-		component = common.EventingComponent
-	}
-	yamlTemplateString, err := common.GenerateOperatorCRString(component, tolerationsCMDFlags.Namespace, p)
-	if err != nil {
-		return err
-	}
-
-	overlayContent := getOverlayYamlContent(tolerationsCMDFlags)
-	valuesYaml := getYamlValuesContentTolerations(tolerationsCMDFlags)
-
-	if err = common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
+// ConfigureTolerations sets the toleration for a Knative deployment
+func ConfigureTolerations(tolerationsCMDFlags TolerationsFlags, p *pkg.OperatorParams) error {
+	if err := validateTolerationsFlags(tolerationsCMDFlags); err != nil {
 		return err
 	}
-	return nil
-}
 
-func getOverlayYamlContent(tolerationsCMDFlags TolerationsFlags) string {
-	baseOverlayContent := servingTolerationOverlay
+	component := common.ServingComponent
 	if strings.EqualFold(tolerationsCMDFlags.Component, common.EventingComponent) {
-		baseOverlayContent = eventingTolerationOverlay
+		component = common.EventingComponent
 	}
-	resourceContent := getTolerationConfiguration(tolerationsCMDFlags)
-	baseOverlayContent = fmt.Sprintf("%s\n%s", baseOverlayContent, resourceContent)
-	return baseOverlayContent
-}
-
-func getTolerationConfiguration(tolerationsCMDFlags TolerationsFlags) string {
-	resourceArray := []string{}
-	tag := fmt.Sprintf("%s%s", common.Spaces(4), common.FieldByName("key"))
-	resourceArray = append(resourceArray, tag)
-
-	keyField := fmt.Sprintf("%s%s", common.Spaces(4), "- key: #@ data.values.key")
-	resourceArray = append(resourceArray, keyField)
-
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-	operatorField := fmt.Sprintf("%s%s", common.Spaces(6), "operator: #@ data.values.operator")
-	resourceArray = append(resourceArray, operatorField)
-
-	if strings.EqualFold(tolerationsCMDFlags.Operator, "Equal") {
-		tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-		resourceArray = append(resourceArray, tag)
-		tolerationField := fmt.Sprintf("%s%s", common.Spaces(6), "value: #@ data.values.value")
-		resourceArray = append(resourceArray, tolerationField)
+	if err := common.CheckDeployment(component, tolerationsCMDFlags.Namespace, tolerationsCMDFlags.DeployName, "", p); err != nil {
+		return err
 	}
-	tag = fmt.Sprintf("%s%s", common.Spaces(6), common.YttMatchingTag)
-	resourceArray = append(resourceArray, tag)
-	tolerationField := fmt.Sprintf("%s%s", common.Spaces(6), "effect: #@ data.values.effect")
-	resourceArray = append(resourceArray, tolerationField)
-
-	return strings.Join(resourceArray, "\n")
+	return common.ApplyKnativeCR(component, tolerationsCMDFlags.Namespace, p, tolerationMutator(tolerationsCMDFlags))
 }
 
-func getYamlValuesContentTolerations(tolerationsCMDFlags TolerationsFlags) string {
-	contentArray := []string{}
-	header := "#@data/values\n---"
-	contentArray = append(contentArray, header)
-	namespace := fmt.Sprintf("namespace: %s", tolerationsCMDFlags.Namespace)
-	contentArray = append(contentArray, namespace)
-
-	deploy := fmt.Sprintf("deployName: %s", tolerationsCMDFlags.DeployName)
-	contentArray = append(contentArray, deploy)
-
-	if tolerationsCMDFlags.Key != "" {
-		key := fmt.Sprintf("key: %s", tolerationsCMDFlags.Key)
-		contentArray = append(contentArray, key)
-	}
-
-	if tolerationsCMDFlags.Operator != "" {
-		operator := fmt.Sprintf("operator: %s", tolerationsCMDFlags.Operator)
-		contentArray = append(contentArray, operator)
+// tolerationMutator sets the toleration with the key for the deployment
+func tolerationMutator(tolerationsCMDFlags TolerationsFlags) common.SpecMutator {
+	toleration := corev1.Toleration{
+		Key:      tolerationsCMDFlags.Key,
+		Operator: corev1.TolerationOperator(tolerationsCMDFlags.Operator),
+		Effect:   corev1.TaintEffect(tolerationsCMDFlags.Effect),
 	}
-
 	if strings.EqualFold(tolerationsCMDFlags.Operator, "Equal") {
-		value := fmt.Sprintf("value: \"%s\"", tolerationsCMDFlags.Value)
-		contentArray = append(contentArray, value)
-	}
-
-	if tolerationsCMDFlags.Effect != "" {
-		effect := fmt.Sprintf("effect: %s", tolerationsCMDFlags.Effect)
-		contentArray = append(contentArray, effect)
+		toleration.Value = tolerationsCMDFlags.Value
 	}
 
-	return strings.Join(contentArray, "\n")
+	return common.UpdateDeploymentOverride(tolerationsCMDFlags.DeployName, func(override *base.WorkloadOverride) error {
+		for i := range override.Tolerations {
+			if override.Tolerations[i].Key == toleration.Key {
+				toleration.TolerationSeconds = override.Tolerations[i].TolerationSeconds
+				override.Tolerations[i] = toleration
+				return nil
+			}
+		}
+		override.Tolerations = append(override.Tolerations, toleration)
+		return nil
+	})
 }
@@ -18,138 +18,14 @@ import (
 	"fmt"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-func TestGetOverlayYamlContent(t *testing.T) {
-	for _, tt := range []struct {
-		name                string
-		tolerationsCMDFlags TolerationsFlags
-		expectedResult      string
-	}{{
-		name: "Knative Eventing",
-		tolerationsCMDFlags: TolerationsFlags{
-			Key:        "test",
-			Operator:   "Exists",
-			Effect:     "test-effect",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			DeployName: "eventing-controller",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeEventing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeEventing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    tolerations:
-
-    #@overlay/match by="key",missing_ok=True
-    - key: #@ data.values.key
-      #@overlay/match missing_ok=True
-      operator: #@ data.values.operator
-      #@overlay/match missing_ok=True
-      effect: #@ data.values.effect`,
-	}, {
-		name: "Knative Serving",
-		tolerationsCMDFlags: TolerationsFlags{
-			Key:        "test",
-			Operator:   "Exists",
-			Effect:     "test-effect",
-			Namespace:  "test-serving",
-			DeployName: "activator",
-		},
-		expectedResult: `#@ load("@ytt:overlay", "overlay")
-#@ load("@ytt:data", "data")
-
-#@overlay/match by=overlay.subset({"kind": "KnativeServing"}),expects=1
----
-apiVersion: operator.knative.dev/v1beta1
-kind: KnativeServing
-metadata:
-  #@overlay/match missing_ok=True
-  namespace: #@ data.values.namespace
-#@overlay/match missing_ok=True
-spec:
-  #@overlay/match missing_ok=True
-  deployments:
-  #@overlay/match by="name",missing_ok=True
-  - name: #@ data.values.deployName
-    #@overlay/match missing_ok=True
-    tolerations:
-
-    #@overlay/match by="key",missing_ok=True
-    - key: #@ data.values.key
-      #@overlay/match missing_ok=True
-      operator: #@ data.values.operator
-      #@overlay/match missing_ok=True
-      effect: #@ data.values.effect`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getOverlayYamlContent(tt.tolerationsCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
-func TestGetYamlValuesContentTolerations(t *testing.T) {
-	for _, tt := range []struct {
-		name                string
-		tolerationsCMDFlags TolerationsFlags
-		expectedResult      string
-	}{{
-		name: "Knative Eventing",
-		tolerationsCMDFlags: TolerationsFlags{
-			Key:        "test",
-			Operator:   "Exists",
-			Effect:     "test-effect",
-			Component:  "eventing",
-			Namespace:  "test-eventing",
-			DeployName: "eventing-controller",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-deployName: eventing-controller
-key: test
-operator: Exists
-effect: test-effect`,
-	}, {
-		name: "Knative Serving",
-		tolerationsCMDFlags: TolerationsFlags{
-			Key:        "test",
-			Operator:   "Exists",
-			Effect:     "test-effect",
-			Component:  "serving",
-			Namespace:  "test-serving",
-			DeployName: "activator",
-		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-deployName: activator
-key: test
-operator: Exists
-effect: test-effect`,
-	}} {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentTolerations(tt.tolerationsCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
-		})
-	}
-}
-
 func TestValidateTolerationsFlags(t *testing.T) {
 	for _, tt := range []struct {
 		name                string
@@ -223,3 +99,74 @@ func TestValidateTolerationsFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestTolerationMutator(t *testing.T) {
+	for _, tt := range []struct {
+		name                string
+		tolerationsCMDFlags TolerationsFlags
+		input               []base.WorkloadOverride
+		expectedResult      []base.WorkloadOverride
+	}{{
+		name: "Add the toleration to a new deployment override",
+		tolerationsCMDFlags: TolerationsFlags{
+			Key:        "example-key",
+			Operator:   "Exists",
+			Effect:     "NoSchedule",
+			Component:  "eventing",
+			Namespace:  "test-eventing",
+			DeployName: "eventing-controller",
+		},
+		expectedResult: []base.WorkloadOverride{{
+			Name: "eventing-controller",
+			Tolerations: []corev1.Toleration{{
+				Key:      "example-key",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		}},
+	}, {
+		name: "Replace the toleration with the same key",
+		tolerationsCMDFlags: TolerationsFlags{
+			Key:        "example-key",
+			Operator:   "Equal",
+			Value:      "example-value",
+			Effect:     "NoExecute",
+			Component:  "eventing",
+			Namespace:  "test-eventing",
+			DeployName: "eventing-controller",
+		},
+		input: []base.WorkloadOverride{{
+			Name: "eventing-controller",
+			Tolerations: []corev1.Toleration{{
+				Key:      "key",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}, {
+				Key:      "example-key",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		}},
+		expectedResult: []base.WorkloadOverride{{
+			Name: "eventing-controller",
+			Tolerations: []corev1.Toleration{{
+				Key:      "key",
+				Operator: corev1.TolerationOpExists,
+				Effect:   corev1.TaintEffectNoSchedule,
+			}, {
+				Key:      "example-key",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "example-value",
+				Effect:   corev1.TaintEffectNoExecute,
+			}},
+		}},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := v1beta1.KnativeEventingSpec{}
+			spec.DeploymentOverride = tt.input
+			err := common.NewKnativeEventingSpec(&spec).Mutate(tolerationMutator(tt.tolerationsCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.DeploymentOverride, tt.expectedResult)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enable
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/base"
+)
+
+const (
+	// certManagerGroupVersion is the API of the cert-manager resources used by Knative Serving
+	certManagerGroupVersion = "cert-manager.io/v1"
+	// certManagerBuiltinVersion is the first version of Knative Serving running the cert-manager controller
+	// itself, instead of the separate release of net-certmanager
+	certManagerBuiltinVersion = "1.12"
+	netCertManagerRelease     = "https://github.com/knative/net-certmanager/releases/download/knative-v%s/release.yaml"
+)
+
+type AutoTLSFlags struct {
+	Issuer       string
+	IssuerKind   string
+	HTTPProtocol string
+	Namespace    string
+}
+
+// newAutoTLSCommand represents the enable command for the automatic TLS of Knative Serving
+func newAutoTLSCommand(p *pkg.OperatorParams) *cobra.Command {
+	var autoTLSCmdFlags AutoTLSFlags
+	var enableAutoTLSCmd = &cobra.Command{
+		Use:   "auto-tls",
+		Short: "Enable the automatic TLS with cert-manager for Knative Serving",
+		Example: `
+  # Enable the automatic TLS with the ClusterIssuer letsencrypt-prod of cert-manager
+  kn-operator enable auto-tls --issuer letsencrypt-prod --issuer-kind ClusterIssuer --namespace knative-serving`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := EnableAutoTLS(&autoTLSCmdFlags, p)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The automatic TLS with the %s '%s' was enabled in the namespace '%s'.\n",
+				autoTLSCmdFlags.IssuerKind, autoTLSCmdFlags.Issuer, autoTLSCmdFlags.Namespace)
+			return nil
+		},
+	}
+
+	enableAutoTLSCmd.Flags().StringVar(&autoTLSCmdFlags.Issuer, "issuer", "", "The name of the cert-manager issuer of the certificates")
+	enableAutoTLSCmd.Flags().StringVar(&autoTLSCmdFlags.IssuerKind, "issuer-kind", "ClusterIssuer",
+		"The kind of the cert-manager issuer: ClusterIssuer or Issuer")
+	enableAutoTLSCmd.Flags().StringVar(&autoTLSCmdFlags.HTTPProtocol, "http-protocol", "Redirected",
+		"The handling of the HTTP requests: Enabled, Redirected or Disabled")
+	enableAutoTLSCmd.Flags().StringVarP(&autoTLSCmdFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
+
+	return enableAutoTLSCmd
+}
+
+func validateAutoTLSFlags(autoTLSCmdFlags AutoTLSFlags) error {
+	if autoTLSCmdFlags.Issuer == "" {
+		return fmt.Errorf("You need to specify the name of the issuer.")
+	}
+	if autoTLSCmdFlags.IssuerKind != "ClusterIssuer" && autoTLSCmdFlags.IssuerKind != "Issuer" {
+		return fmt.Errorf("You need to specify the kind of the issuer: ClusterIssuer or Issuer.")
+	}
+	if autoTLSCmdFlags.HTTPProtocol != "Enabled" && autoTLSCmdFlags.HTTPProtocol != "Redirected" && autoTLSCmdFlags.HTTPProtocol != "Disabled" {
+		return fmt.Errorf("You need to specify the HTTP protocol: Enabled, Redirected or Disabled.")
+	}
+	return nil
+}
+
+// EnableAutoTLS enables the automatic TLS of Knative Serving with the issuer of cert-manager, after checking that
+// cert-manager is installed. The namespace defaults to knative-serving.
+func EnableAutoTLS(autoTLSCmdFlags *AutoTLSFlags, p *pkg.OperatorParams) error {
+	if err := validateAutoTLSFlags(*autoTLSCmdFlags); err != nil {
+		return err
+	}
+
+	if autoTLSCmdFlags.Namespace == "" {
+		autoTLSCmdFlags.Namespace = common.DefaultKnativeServingNamespace
+	}
+
+	client, err := p.NewKubeClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	resources, err := client.Discovery().ServerResourcesForGroupVersion(certManagerGroupVersion)
+	if apierrs.IsNotFound(err) {
+		resources = nil
+	} else if err != nil {
+		return err
+	}
+	if err := checkCertManagerResources(resources, autoTLSCmdFlags.IssuerKind); err != nil {
+		return err
+	}
+
+	knativeOperatorCR, err := common.GetKnativeOperatorCR(p)
+	if err != nil {
+		return err
+	}
+	version, err := knativeOperatorCR.GetVersion(p.Context(), common.ServingComponent, autoTLSCmdFlags.Namespace)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	mutator, err := autoTLSMutator(*autoTLSCmdFlags, version)
+	if err != nil {
+		return err
+	}
+	return common.ApplyKnativeCR(common.ServingComponent, autoTLSCmdFlags.Namespace, p, mutator)
+}
+
+// checkCertManagerResources checks that the CRDs of cert-manager needed by Knative Serving are installed
+func checkCertManagerResources(resources *metav1.APIResourceList, issuerKind string) error {
+	found := map[string]bool{}
+	if resources != nil {
+		for _, resource := range resources.APIResources {
+			found[resource.Kind] = true
+		}
+	}
+
+	missing := []string{}
+	for _, kind := range []string{"Certificate", issuerKind} {
+		if !found[kind] {
+			missing = append(missing, kind)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("The CRDs of cert-manager are not found for %s in %s, please install cert-manager first.",
+			strings.Join(missing, ", "), certManagerGroupVersion)
+	}
+	return nil
+}
+
+// autoTLSMutator sets the network and the issuer of the certificates. The versions of Knative Serving before 1.12
+// need the controller of net-certmanager in the additional manifests, and the key auto-tls instead of
+// external-domain-tls.
+func autoTLSMutator(autoTLSCmdFlags AutoTLSFlags, version string) (common.SpecMutator, error) {
+	issuerRef, err := yaml.Marshal(map[string]string{
+		"kind": autoTLSCmdFlags.IssuerKind,
+		"name": autoTLSCmdFlags.Issuer,
+	})
+	if err != nil {
+		return nil, err
+	}
+	builtin := common.IsVersionAtLeast(version, certManagerBuiltinVersion)
+
+	return func(spec *common.KnativeSpec) error {
+		if spec.Serving == nil {
+			return fmt.Errorf("The automatic TLS can only be enabled for Knative Serving.")
+		}
+
+		mutators := []common.SpecMutator{
+			common.SetConfigMapData("network", "http-protocol", autoTLSCmdFlags.HTTPProtocol),
+			common.SetConfigMapData("certmanager", "issuerRef", string(issuerRef)),
+		}
+		if builtin {
+			mutators = append(mutators, common.SetConfigMapData("network", "external-domain-tls", "Enabled"))
+		} else {
+			mutators = append(mutators, common.SetConfigMapData("network", "auto-tls", "Enabled"))
+			manifest := fmt.Sprintf(netCertManagerRelease, strings.TrimPrefix(common.SemanticVersion(version), "v"))
+			if !hasManifest(spec.AdditionalManifests, manifest) {
+				spec.AdditionalManifests = append(spec.AdditionalManifests, base.Manifest{Url: manifest})
+			}
+		}
+		for _, mutator := range mutators {
+			if err := mutator(spec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+func hasManifest(manifests []base.Manifest, url string) bool {
+	for _, manifest := range manifests {
+		if manifest.Url == url {
+			return true
+		}
+	}
+	return false
+}
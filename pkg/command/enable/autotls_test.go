@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestValidateAutoTLSFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		autoTLSCmdFlags AutoTLSFlags
+		expectedError   error
+	}{{
+		name:            "ClusterIssuer",
+		autoTLSCmdFlags: AutoTLSFlags{Issuer: "letsencrypt-prod", IssuerKind: "ClusterIssuer", HTTPProtocol: "Redirected"},
+		expectedError:   nil,
+	}, {
+		name:            "No issuer",
+		autoTLSCmdFlags: AutoTLSFlags{IssuerKind: "ClusterIssuer", HTTPProtocol: "Redirected"},
+		expectedError:   fmt.Errorf("You need to specify the name of the issuer."),
+	}, {
+		name:            "Invalid issuer kind",
+		autoTLSCmdFlags: AutoTLSFlags{Issuer: "letsencrypt-prod", IssuerKind: "CA", HTTPProtocol: "Redirected"},
+		expectedError:   fmt.Errorf("You need to specify the kind of the issuer: ClusterIssuer or Issuer."),
+	}, {
+		name:            "Invalid HTTP protocol",
+		autoTLSCmdFlags: AutoTLSFlags{Issuer: "letsencrypt-prod", IssuerKind: "Issuer", HTTPProtocol: "redirect"},
+		expectedError:   fmt.Errorf("You need to specify the HTTP protocol: Enabled, Redirected or Disabled."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAutoTLSFlags(tt.autoTLSCmdFlags)
+			if tt.expectedError == nil {
+				testingUtil.AssertEqual(t, err, nil)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			}
+		})
+	}
+}
+
+func TestCheckCertManagerResources(t *testing.T) {
+	resources := &metav1.APIResourceList{
+		GroupVersion: "cert-manager.io/v1",
+		APIResources: []metav1.APIResource{{Kind: "Certificate"}, {Kind: "ClusterIssuer"}},
+	}
+	testingUtil.AssertEqual(t, checkCertManagerResources(resources, "ClusterIssuer"), nil)
+	testingUtil.AssertEqual(t, checkCertManagerResources(resources, "Issuer").Error(),
+		"The CRDs of cert-manager are not found for Issuer in cert-manager.io/v1, please install cert-manager first.")
+	testingUtil.AssertEqual(t, checkCertManagerResources(nil, "Issuer").Error(),
+		"The CRDs of cert-manager are not found for Certificate, Issuer in cert-manager.io/v1, please install cert-manager first.")
+}
+
+func TestAutoTLSMutator(t *testing.T) {
+	flags := AutoTLSFlags{Issuer: "letsencrypt-prod", IssuerKind: "ClusterIssuer", HTTPProtocol: "Redirected"}
+	issuerRef := "kind: ClusterIssuer\nname: letsencrypt-prod\n"
+
+	for _, tt := range []struct {
+		name              string
+		version           string
+		expectedConfig    base.ConfigMapData
+		expectedManifests []base.Manifest
+	}{{
+		name:    "Knative Serving with the builtin cert-manager controller",
+		version: "1.12.0",
+		expectedConfig: base.ConfigMapData{
+			"network":     {"http-protocol": "Redirected", "external-domain-tls": "Enabled"},
+			"certmanager": {"issuerRef": issuerRef},
+		},
+	}, {
+		name:    "Knative Serving with the latest version",
+		version: "",
+		expectedConfig: base.ConfigMapData{
+			"network":     {"http-protocol": "Redirected", "external-domain-tls": "Enabled"},
+			"certmanager": {"issuerRef": issuerRef},
+		},
+	}, {
+		name:    "Knative Serving needing net-certmanager",
+		version: "1.11",
+		expectedConfig: base.ConfigMapData{
+			"network":     {"http-protocol": "Redirected", "auto-tls": "Enabled"},
+			"certmanager": {"issuerRef": issuerRef},
+		},
+		expectedManifests: []base.Manifest{{
+			Url: "https://github.com/knative/net-certmanager/releases/download/knative-v1.11.0/release.yaml",
+		}},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			mutator, err := autoTLSMutator(flags, tt.version)
+			testingUtil.AssertEqual(t, err, nil)
+			spec := v1beta1.KnativeServingSpec{}
+			// The manifest is only added once
+			for i := 0; i < 2; i++ {
+				testingUtil.AssertEqual(t, common.NewKnativeServingSpec(&spec).Mutate(mutator), nil)
+			}
+			testingUtil.AssertDeepEqual(t, spec.Config, tt.expectedConfig)
+			testingUtil.AssertDeepEqual(t, spec.AdditionalManifests, tt.expectedManifests)
+		})
+	}
+
+	spec := v1beta1.KnativeEventingSpec{}
+	mutator, _ := autoTLSMutator(flags, "")
+	testingUtil.AssertEqual(t, common.NewKnativeEventingSpec(&spec).Mutate(mutator).Error(),
+		"The automatic TLS can only be enabled for Knative Serving.")
+}
@@ -24,16 +24,19 @@ import (
 func NewEnableCommand(p *pkg.OperatorParams) *cobra.Command {
 	var enableCmd = &cobra.Command{
 		Use:   "enable",
-		Short: "Enable the ingress for Knative Serving and the eventing sources for Knative Eventing",
+		Short: "Enable the ingress and the automatic TLS for Knative Serving, and the eventing sources for Knative Eventing",
 		Example: `
   # Enable the ingress istio for Knative Serving
   kn-operator enable ingress --istio --namespace knative-serving
   # Enable the eventing source github for Knative Eventing
-  kn-operator enable eventing-source --github --namespace knative-eventing`,
+  kn-operator enable eventing-source --github --namespace knative-eventing
+  # Enable the automatic TLS for Knative Serving with the ClusterIssuer letsencrypt-prod
+  kn-operator enable auto-tls --issuer letsencrypt-prod --namespace knative-serving`,
 	}
 
 	enableCmd.AddCommand(newIngressCommand(p))
 	enableCmd.AddCommand(newEventingSourcesCommand(p))
+	enableCmd.AddCommand(newAutoTLSCommand(p))
 
 	return enableCmd
 }
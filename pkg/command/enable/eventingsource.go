@@ -15,19 +15,16 @@
 package enable
 
 import (
-	_ "embed"
 	"fmt"
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-//go:embed overlay/ke_source.yaml
-var sourceOverlayContent string
-
-type eventingSourceFlags struct {
+type EventingSourceFlags struct {
 	Ceph      bool
 	Github    bool
 	Gitlab    bool
@@ -37,10 +34,9 @@ type eventingSourceFlags struct {
 	Namespace string
 }
 
-var eventingSourceCmdFlags eventingSourceFlags
-
 // newEventingSourcesCommand represents the enable commands for eventing sources
 func newEventingSourcesCommand(p *pkg.OperatorParams) *cobra.Command {
+	var eventingSourceCmdFlags EventingSourceFlags
 	var enableEventingSourceCmd = &cobra.Command{
 		Use:   "eventing-source",
 		Short: "Enable the eventing source for Knative Eventing",
@@ -48,11 +44,7 @@ func newEventingSourcesCommand(p *pkg.OperatorParams) *cobra.Command {
   # Enable the eventing source github for Knative Serving
   kn-operator enable eventing-source --github --namespace knative-eventing`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if eventingSourceCmdFlags.Namespace == "" {
-				eventingSourceCmdFlags.Namespace = common.DefaultKnativeEventingNamespace
-			}
-
-			err := enableEventingSource(eventingSourceCmdFlags, p)
+			err := EnableEventingSources(&eventingSourceCmdFlags, p)
 			if err != nil {
 				return err
 			}
@@ -74,23 +66,32 @@ func newEventingSourcesCommand(p *pkg.OperatorParams) *cobra.Command {
 	return enableEventingSourceCmd
 }
 
-func enableEventingSource(eventingSourceCmdFlags eventingSourceFlags, p *pkg.OperatorParams) error {
-	// Generate the CR template
-	yamlTemplateString, err := common.GenerateOperatorCRString(common.EventingComponent, eventingSourceCmdFlags.Namespace, p)
-	if err != nil {
-		return err
+// EnableEventingSources enables the selected eventing sources for Knative Eventing. The namespace defaults to
+// knative-eventing.
+func EnableEventingSources(eventingSourceCmdFlags *EventingSourceFlags, p *pkg.OperatorParams) error {
+	if eventingSourceCmdFlags.Namespace == "" {
+		eventingSourceCmdFlags.Namespace = common.DefaultKnativeEventingNamespace
 	}
 
-	valuesYaml := getYamlValuesContentSource(eventingSourceCmdFlags)
-
-	if err = common.ApplyManifests(yamlTemplateString, sourceOverlayContent, valuesYaml, p); err != nil {
-		return err
-	}
-	return nil
+	return common.ApplyKnativeCR(common.EventingComponent, eventingSourceCmdFlags.Namespace, p,
+		eventingSourceMutator(*eventingSourceCmdFlags))
 }
 
-func getYamlValuesContentSource(eventingSourceCmdFlags eventingSourceFlags) string {
-	return fmt.Sprintf("#@data/values\n---\nnamespace: %s\nredis: %t\nrabbitmq: %t\ngitlab: %t\ngithub: %t\nceph: %t\nkafka: %t",
-		eventingSourceCmdFlags.Namespace, eventingSourceCmdFlags.Redis, eventingSourceCmdFlags.Rabbitmq, eventingSourceCmdFlags.Gitlab,
-		eventingSourceCmdFlags.Github, eventingSourceCmdFlags.Ceph, eventingSourceCmdFlags.Kafka)
+// eventingSourceMutator enables the selected eventing sources and disables the others
+func eventingSourceMutator(eventingSourceCmdFlags EventingSourceFlags) common.SpecMutator {
+	return func(spec *common.KnativeSpec) error {
+		if spec.Eventing == nil {
+			return fmt.Errorf("The eventing sources can only be enabled for Knative Eventing.")
+		}
+		if spec.Eventing.Source == nil {
+			spec.Eventing.Source = &v1beta1.SourceConfigs{}
+		}
+		spec.Eventing.Source.Ceph.Enabled = eventingSourceCmdFlags.Ceph
+		spec.Eventing.Source.Github.Enabled = eventingSourceCmdFlags.Github
+		spec.Eventing.Source.Gitlab.Enabled = eventingSourceCmdFlags.Gitlab
+		spec.Eventing.Source.Kafka.Enabled = eventingSourceCmdFlags.Kafka
+		spec.Eventing.Source.Rabbitmq.Enabled = eventingSourceCmdFlags.Rabbitmq
+		spec.Eventing.Source.Redis.Enabled = eventingSourceCmdFlags.Redis
+		return nil
+	}
 }
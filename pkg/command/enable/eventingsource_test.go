@@ -17,50 +17,51 @@ package enable
 import (
 	"testing"
 
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-func TestGetYamlValuesContentSource(t *testing.T) {
+func TestEventingSourceMutator(t *testing.T) {
 	for _, tt := range []struct {
 		name                   string
-		eventingSourceCmdFlags eventingSourceFlags
-		expectedResult         string
+		eventingSourceCmdFlags EventingSourceFlags
+		expectedResult         *v1beta1.SourceConfigs
 	}{{
 		name: "Knative Eventing with ceph and Kafka enabled",
-		eventingSourceCmdFlags: eventingSourceFlags{
+		eventingSourceCmdFlags: EventingSourceFlags{
 			Namespace: "test-eventing",
 			Ceph:      true,
 			Kafka:     true,
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-redis: false
-rabbitmq: false
-gitlab: false
-github: false
-ceph: true
-kafka: true`,
+		expectedResult: &v1beta1.SourceConfigs{
+			Ceph:  base.CephSourceConfiguration{Enabled: true},
+			Kafka: base.KafkaSourceConfiguration{Enabled: true},
+		},
 	}, {
 		name: "Knative Eventing with redis and github enabled",
-		eventingSourceCmdFlags: eventingSourceFlags{
+		eventingSourceCmdFlags: EventingSourceFlags{
 			Namespace: "test-eventing",
 			Github:    true,
 			Redis:     true,
 		},
-		expectedResult: `#@data/values
----
-namespace: test-eventing
-redis: true
-rabbitmq: false
-gitlab: false
-github: true
-ceph: false
-kafka: false`,
+		expectedResult: &v1beta1.SourceConfigs{
+			Github: base.GithubSourceConfiguration{Enabled: true},
+			Redis:  base.RedisSourceConfiguration{Enabled: true},
+		},
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContentSource(tt.eventingSourceCmdFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := v1beta1.KnativeEventingSpec{}
+			err := common.NewKnativeEventingSpec(&spec).Mutate(eventingSourceMutator(tt.eventingSourceCmdFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.Source, tt.expectedResult)
 		})
 	}
 }
+
+func TestEventingSourceMutatorForServing(t *testing.T) {
+	spec := v1beta1.KnativeServingSpec{}
+	err := common.NewKnativeServingSpec(&spec).Mutate(eventingSourceMutator(EventingSourceFlags{Kafka: true}))
+	testingUtil.AssertEqual(t, err.Error(), "The eventing sources can only be enabled for Knative Eventing.")
+}
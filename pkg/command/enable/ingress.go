@@ -15,7 +15,6 @@
 package enable
 
 import (
-	_ "embed"
 	"fmt"
 
 	"knative.dev/kn-plugin-operator/pkg/command/common"
@@ -23,22 +22,19 @@ import (
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
-//go:embed overlay/ks_ingress.yaml
-var overlayContent string
-
-type ingressFlags struct {
+type IngressFlags struct {
 	Istio     bool
 	Kourier   bool
 	Contour   bool
 	Namespace string
 }
 
-var ingressCmdFlags ingressFlags
-
 // newIngressCommand represents the enable commands for sources or ingresses
 func newIngressCommand(p *pkg.OperatorParams) *cobra.Command {
+	var ingressCmdFlags IngressFlags
 	var enableIngressCmd = &cobra.Command{
 		Use:   "ingress",
 		Short: "Enable the ingress for Knative Serving",
@@ -50,16 +46,7 @@ func newIngressCommand(p *pkg.OperatorParams) *cobra.Command {
   # Enable the ingress contour for Knative Serving
   kn-operator enable ingress --contour --namespace knative-serving`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := validateIngressFlags(ingressCmdFlags)
-			if err != nil {
-				return err
-			}
-
-			if ingressCmdFlags.Namespace == "" {
-				ingressCmdFlags.Namespace = common.DefaultKnativeServingNamespace
-			}
-
-			err = enableIngress(ingressCmdFlags, p)
+			err := EnableIngress(&ingressCmdFlags, p)
 			if err != nil {
 				return err
 			}
@@ -86,7 +73,7 @@ func newIngressCommand(p *pkg.OperatorParams) *cobra.Command {
 	return enableIngressCmd
 }
 
-func validateIngressFlags(ingressCMDFlags ingressFlags) error {
+func validateIngressFlags(ingressCMDFlags IngressFlags) error {
 	count := 0
 
 	if ingressCMDFlags.Istio {
@@ -110,22 +97,21 @@ func validateIngressFlags(ingressCMDFlags ingressFlags) error {
 	return nil
 }
 
-func enableIngress(ingressCMDFlags ingressFlags, p *pkg.OperatorParams) error {
-	// Generate the CR template
-	yamlTemplateString, err := common.GenerateOperatorCRString(common.ServingComponent, ingressCMDFlags.Namespace, p)
-	if err != nil {
+// EnableIngress enables the selected ingress for Knative Serving. The namespace defaults to knative-serving.
+func EnableIngress(ingressCMDFlags *IngressFlags, p *pkg.OperatorParams) error {
+	if err := validateIngressFlags(*ingressCMDFlags); err != nil {
 		return err
 	}
 
-	valuesYaml := getYamlValuesContent(ingressCMDFlags)
-
-	if err = common.ApplyManifests(yamlTemplateString, overlayContent, valuesYaml, p); err != nil {
-		return err
+	if ingressCMDFlags.Namespace == "" {
+		ingressCMDFlags.Namespace = common.DefaultKnativeServingNamespace
 	}
-	return nil
+
+	return common.ApplyKnativeCR(common.ServingComponent, ingressCMDFlags.Namespace, p, ingressMutator(*ingressCMDFlags))
 }
 
-func getYamlValuesContent(ingressCMDFlags ingressFlags) string {
+// ingressMutator enables the selected ingress, disables the others and sets the ingress class accordingly
+func ingressMutator(ingressCMDFlags IngressFlags) common.SpecMutator {
 	ingressClass := "istio.ingress.networking.knative.dev"
 	if ingressCMDFlags.Kourier {
 		ingressClass = "kourier.ingress.networking.knative.dev"
@@ -135,8 +121,16 @@ func getYamlValuesContent(ingressCMDFlags ingressFlags) string {
 		ingressClass = "contour.ingress.networking.knative.dev"
 	}
 
-	content := fmt.Sprintf("#@data/values\n---\nnamespace: %s\nkourier: %t\nistio: %t\ncontour: %t\ningressClass: %s",
-		ingressCMDFlags.Namespace, ingressCMDFlags.Kourier, ingressCMDFlags.Istio, ingressCMDFlags.Contour, ingressClass)
-
-	return content
+	return func(spec *common.KnativeSpec) error {
+		if spec.Serving == nil {
+			return fmt.Errorf("The ingress can only be enabled for Knative Serving.")
+		}
+		if spec.Serving.Ingress == nil {
+			spec.Serving.Ingress = &v1beta1.IngressConfigs{}
+		}
+		spec.Serving.Ingress.Istio.Enabled = ingressCMDFlags.Istio
+		spec.Serving.Ingress.Kourier.Enabled = ingressCMDFlags.Kourier
+		spec.Serving.Ingress.Contour.Enabled = ingressCMDFlags.Contour
+		return common.SetConfigMapData("network", "ingress-class", ingressClass)(spec)
+	}
 }
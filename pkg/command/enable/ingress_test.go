@@ -20,33 +20,36 @@ import (
 	"fmt"
 	"testing"
 
+	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/base"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
 )
 
 func TestValidateIngressFlags(t *testing.T) {
 	for _, tt := range []struct {
 		name            string
-		ingressCMDFlags ingressFlags
+		ingressCMDFlags IngressFlags
 		expectedError   error
 	}{{
 		name:            "Only Istio enabled",
-		ingressCMDFlags: ingressFlags{Istio: true},
+		ingressCMDFlags: IngressFlags{Istio: true},
 		expectedError:   nil,
 	}, {
 		name:            "No ingress enabled",
-		ingressCMDFlags: ingressFlags{Istio: false, Kourier: false, Contour: false},
+		ingressCMDFlags: IngressFlags{Istio: false, Kourier: false, Contour: false},
 		expectedError:   fmt.Errorf("You need to enable at least one ingress for Knative Serving."),
 	}, {
 		name:            "Istio and Kourier enabled",
-		ingressCMDFlags: ingressFlags{Istio: true, Kourier: true},
+		ingressCMDFlags: IngressFlags{Istio: true, Kourier: true},
 		expectedError:   fmt.Errorf("You can specify only one ingress for Knative Serving."),
 	}, {
 		name:            "Istio, Contour and Kourier enabled",
-		ingressCMDFlags: ingressFlags{Istio: true, Kourier: true, Contour: true},
+		ingressCMDFlags: IngressFlags{Istio: true, Kourier: true, Contour: true},
 		expectedError:   fmt.Errorf("You can specify only one ingress for Knative Serving."),
 	}, {
 		name:            "Only Kourier enabled",
-		ingressCMDFlags: ingressFlags{Istio: false, Kourier: true, Contour: false},
+		ingressCMDFlags: IngressFlags{Istio: false, Kourier: true, Contour: false},
 		expectedError:   nil,
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
@@ -60,54 +63,53 @@ func TestValidateIngressFlags(t *testing.T) {
 	}
 }
 
-func TestGetYamlValuesContent(t *testing.T) {
+func TestIngressMutator(t *testing.T) {
 	for _, tt := range []struct {
-		name            string
-		ingressCMDFlags ingressFlags
-		expectedResult  string
+		name                 string
+		ingressCMDFlags      IngressFlags
+		expectedIngress      *v1beta1.IngressConfigs
+		expectedIngressClass string
 	}{{
 		name: "Knative Serving with istio enabled",
-		ingressCMDFlags: ingressFlags{
+		ingressCMDFlags: IngressFlags{
 			Namespace: "test-serving",
 			Istio:     true,
 		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-kourier: false
-istio: true
-contour: false
-ingressClass: istio.ingress.networking.knative.dev`,
+		expectedIngress: &v1beta1.IngressConfigs{
+			Istio: base.IstioIngressConfiguration{Enabled: true},
+		},
+		expectedIngressClass: "istio.ingress.networking.knative.dev",
 	}, {
 		name: "Knative Serving with Kourier enabled",
-		ingressCMDFlags: ingressFlags{
+		ingressCMDFlags: IngressFlags{
 			Namespace: "test-serving",
 			Kourier:   true,
 		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-kourier: true
-istio: false
-contour: false
-ingressClass: kourier.ingress.networking.knative.dev`,
+		expectedIngress: &v1beta1.IngressConfigs{
+			Kourier: base.KourierIngressConfiguration{Enabled: true},
+		},
+		expectedIngressClass: "kourier.ingress.networking.knative.dev",
 	}, {
 		name: "Knative Serving with Contour enabled",
-		ingressCMDFlags: ingressFlags{
+		ingressCMDFlags: IngressFlags{
 			Namespace: "test-serving",
 			Contour:   true,
 		},
-		expectedResult: `#@data/values
----
-namespace: test-serving
-kourier: false
-istio: false
-contour: true
-ingressClass: contour.ingress.networking.knative.dev`,
+		expectedIngress: &v1beta1.IngressConfigs{
+			Contour: base.ContourIngressConfiguration{Enabled: true},
+		},
+		expectedIngressClass: "contour.ingress.networking.knative.dev",
 	}} {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getYamlValuesContent(tt.ingressCMDFlags)
-			testingUtil.AssertEqual(t, result, tt.expectedResult)
+			spec := v1beta1.KnativeServingSpec{
+				Ingress: &v1beta1.IngressConfigs{
+					Istio: base.IstioIngressConfiguration{Enabled: true},
+				},
+			}
+			err := common.NewKnativeServingSpec(&spec).Mutate(ingressMutator(tt.ingressCMDFlags))
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertDeepEqual(t, spec.Ingress, tt.expectedIngress)
+			testingUtil.AssertEqual(t, spec.Config["network"]["ingress-class"], tt.expectedIngressClass)
 		})
 	}
 }
@@ -0,0 +1,164 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+	"knative.dev/operator/pkg/apis/operator/base"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// featuresConfigMap is the ConfigMap of the feature flags in Knative Serving and Eventing
+const featuresConfigMap = "config-features"
+
+type featuresFlags struct {
+	Component    string
+	Namespace    string
+	AllowUnknown bool
+}
+
+// feature is the state of a feature flag in the Knative custom resource
+type feature struct {
+	Name     string
+	State    string
+	Default  string
+	Maturity string
+}
+
+// NewFeaturesCommand represents the features commands to manage the feature flags of Knative Serving or Eventing
+func NewFeaturesCommand(p *pkg.OperatorParams) *cobra.Command {
+	var featuresCmd = &cobra.Command{
+		Use:   "features",
+		Short: "List, enable or disable the feature flags of Knative Serving and Eventing",
+		Example: `
+  # List the feature flags of Knative Serving
+  kn operator features list --component serving
+  # Enable the feature flag kubernetes.podspec-affinity of Knative Serving
+  kn operator features enable kubernetes.podspec-affinity --component serving --namespace knative-serving
+  # Disable the feature flag new-trigger-filters of Knative Eventing
+  kn operator features disable new-trigger-filters --component eventing --namespace knative-eventing`,
+	}
+
+	featuresCmd.AddCommand(newListCommand(p))
+	featuresCmd.AddCommand(newToggleCommand(p, true))
+	featuresCmd.AddCommand(newToggleCommand(p, false))
+
+	return featuresCmd
+}
+
+func addFeaturesFlags(cmd *cobra.Command, featuresCMDFlags *featuresFlags) {
+	cmd.Flags().StringVarP(&featuresCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
+	cmd.Flags().StringVarP(&featuresCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative component")
+}
+
+func validateFeaturesFlags(featuresCMDFlags *featuresFlags) error {
+	if strings.EqualFold(featuresCMDFlags.Component, common.ServingComponent) {
+		featuresCMDFlags.Component = common.ServingComponent
+		if featuresCMDFlags.Namespace == "" {
+			featuresCMDFlags.Namespace = common.DefaultKnativeServingNamespace
+		}
+	} else if strings.EqualFold(featuresCMDFlags.Component, common.EventingComponent) {
+		featuresCMDFlags.Component = common.EventingComponent
+		if featuresCMDFlags.Namespace == "" {
+			featuresCMDFlags.Namespace = common.DefaultKnativeEventingNamespace
+		}
+	} else {
+		return fmt.Errorf("You need to specify the component for Knative: serving or eventing.")
+	}
+	return nil
+}
+
+// featuresSchema returns the schema of the feature flags of the component in the version, which is empty if the
+// version is not known to the schema
+func featuresSchema(component, version string) (common.ConfigMapSchema, error) {
+	schema, err := common.LoadConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+	cmSchemas, _ := schema.ConfigMaps(component, version)
+	return cmSchemas[featuresConfigMap], nil
+}
+
+// getFeaturesState returns the version in the spec of the component and the ConfigMaps set in its custom resource,
+// which are both empty if the component is not installed
+func getFeaturesState(featuresCMDFlags featuresFlags, p *pkg.OperatorParams) (string, base.ConfigMapData, error) {
+	knativeOperatorCR, err := common.GetKnativeOperatorCR(p)
+	if err != nil {
+		return "", nil, err
+	}
+	ctx := p.Context()
+	cmData, err := knativeOperatorCR.GetConfigMaps(ctx, featuresCMDFlags.Component, featuresCMDFlags.Namespace)
+	if apierrs.IsNotFound(err) {
+		return "", nil, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+	version, err := knativeOperatorCR.GetSpecVersion(ctx, featuresCMDFlags.Component, featuresCMDFlags.Namespace)
+	if err != nil {
+		return "", nil, err
+	}
+	return version, cmData, nil
+}
+
+// featuresData returns the name and the data of the ConfigMap of the feature flags in the custom resource, which can
+// be set with or without the prefix config-
+func featuresData(cmData base.ConfigMapData) (string, map[string]string) {
+	if data, ok := cmData[featuresConfigMap]; ok {
+		return featuresConfigMap, data
+	}
+	name := strings.TrimPrefix(featuresConfigMap, "config-")
+	if data, ok := cmData[name]; ok {
+		return name, data
+	}
+	return featuresConfigMap, nil
+}
+
+// collectFeatures returns the sorted feature flags known to the schema or set in the custom resource. The state of
+// a feature flag is its default, if it is not set.
+func collectFeatures(cmSchema common.ConfigMapSchema, cmData base.ConfigMapData) []feature {
+	_, data := featuresData(cmData)
+	features := []feature{}
+	for _, name := range cmSchema.Keys() {
+		keySchema, _ := cmSchema.Lookup(name)
+		state := keySchema.Default
+		if value, ok := data[name]; ok {
+			state = value
+		}
+		features = append(features, feature{
+			Name:     name,
+			State:    state,
+			Default:  keySchema.Default,
+			Maturity: keySchema.Maturity,
+		})
+	}
+	for name, value := range data {
+		if _, ok := cmSchema.Lookup(name); ok || name == "_example" {
+			continue
+		}
+		features = append(features, feature{Name: name, State: value, Default: "-", Maturity: "unknown"})
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Name < features[j].Name
+	})
+	return features
+}
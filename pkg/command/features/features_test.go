@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"knative.dev/operator/pkg/apis/operator/base"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+var testSchema = common.ConfigMapSchema{
+	"multi-container":       {Type: "enum", Values: []string{"Enabled", "Disabled"}, Default: "Enabled", Maturity: "stable"},
+	"secure-pod-defaults":   {Type: "enum", Values: []string{"Enabled", "Disabled"}, Default: "Disabled", Maturity: "alpha"},
+	"transport-encryption":  {Type: "enum", Values: []string{"disabled", "permissive", "strict"}, Default: "disabled", Maturity: "alpha"},
+	"new-trigger-filters":   {Type: "enum", Values: []string{"enabled", "disabled"}, Default: "enabled", Maturity: "beta"},
+	"kubernetes.podspec-xx": {Type: "enum", Values: []string{"Enabled", "Disabled", "Allowed"}, Default: "Allowed", Maturity: "alpha"},
+}
+
+func TestValidateFeaturesFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		featuresCMDFlags  featuresFlags
+		expectedNamespace string
+		expectedError     error
+	}{{
+		name:              "Default namespace of Knative Serving",
+		featuresCMDFlags:  featuresFlags{Component: "Serving"},
+		expectedNamespace: common.DefaultKnativeServingNamespace,
+	}, {
+		name:              "Namespace of Knative Eventing",
+		featuresCMDFlags:  featuresFlags{Component: "eventing", Namespace: "test-eventing"},
+		expectedNamespace: "test-eventing",
+	}, {
+		name:             "Missing component",
+		featuresCMDFlags: featuresFlags{},
+		expectedError:    fmt.Errorf("You need to specify the component for Knative: serving or eventing."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFeaturesFlags(&tt.featuresCMDFlags)
+			if tt.expectedError != nil {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			} else {
+				testingUtil.AssertEqual(t, err, nil)
+				testingUtil.AssertEqual(t, tt.featuresCMDFlags.Namespace, tt.expectedNamespace)
+			}
+		})
+	}
+}
+
+func TestCollectFeatures(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		cmData   base.ConfigMapData
+		expected []feature
+	}{{
+		name: "Defaults of the features known to the schema",
+		expected: []feature{
+			{Name: "kubernetes.podspec-xx", State: "Allowed", Default: "Allowed", Maturity: "alpha"},
+			{Name: "multi-container", State: "Enabled", Default: "Enabled", Maturity: "stable"},
+			{Name: "new-trigger-filters", State: "enabled", Default: "enabled", Maturity: "beta"},
+			{Name: "secure-pod-defaults", State: "Disabled", Default: "Disabled", Maturity: "alpha"},
+			{Name: "transport-encryption", State: "disabled", Default: "disabled", Maturity: "alpha"},
+		},
+	}, {
+		name: "States set in the ConfigMap without prefix",
+		cmData: base.ConfigMapData{
+			"features": {"secure-pod-defaults": "Enabled", "custom-flag": "enabled"},
+		},
+		expected: []feature{
+			{Name: "custom-flag", State: "enabled", Default: "-", Maturity: "unknown"},
+			{Name: "kubernetes.podspec-xx", State: "Allowed", Default: "Allowed", Maturity: "alpha"},
+			{Name: "multi-container", State: "Enabled", Default: "Enabled", Maturity: "stable"},
+			{Name: "new-trigger-filters", State: "enabled", Default: "enabled", Maturity: "beta"},
+			{Name: "secure-pod-defaults", State: "Enabled", Default: "Disabled", Maturity: "alpha"},
+			{Name: "transport-encryption", State: "disabled", Default: "disabled", Maturity: "alpha"},
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			testingUtil.AssertDeepEqual(t, collectFeatures(testSchema, tt.cmData), tt.expected)
+		})
+	}
+}
+
+func TestFeaturesData(t *testing.T) {
+	name, data := featuresData(base.ConfigMapData{"features": {"key": "value"}})
+	testingUtil.AssertEqual(t, name, "features")
+	testingUtil.AssertDeepEqual(t, data, map[string]string{"key": "value"})
+
+	name, data = featuresData(base.ConfigMapData{"config-network": {"key": "value"}})
+	testingUtil.AssertEqual(t, name, featuresConfigMap)
+	testingUtil.AssertEqual(t, len(data), 0)
+}
+
+func TestFeatureValue(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		component     string
+		feature       string
+		enable        bool
+		expected      string
+		expectedError string
+	}{{
+		name:      "Enable a feature of Knative Serving",
+		component: common.ServingComponent,
+		feature:   "secure-pod-defaults",
+		enable:    true,
+		expected:  "Enabled",
+	}, {
+		name:      "Disable a feature of Knative Eventing",
+		component: common.EventingComponent,
+		feature:   "new-trigger-filters",
+		expected:  "disabled",
+	}, {
+		name:      "Enable an unknown feature of Knative Eventing",
+		component: common.EventingComponent,
+		feature:   "custom-flag",
+		enable:    true,
+		expected:  "enabled",
+	}, {
+		name:      "Enable a feature with more states",
+		component: common.EventingComponent,
+		feature:   "transport-encryption",
+		enable:    true,
+		expectedError: "The feature flag 'transport-encryption' cannot be enabled, but only set to one of disabled, permissive, strict " +
+			"with the command configure configmaps.",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := featureValue(tt.component, testSchema, tt.feature, tt.enable)
+			if tt.expectedError != "" {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+			} else {
+				testingUtil.AssertEqual(t, err, nil)
+				testingUtil.AssertEqual(t, value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrintFeatures(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := printFeatures(out, []feature{
+		{Name: "multi-container", State: "Enabled", Default: "Enabled", Maturity: "stable"},
+	})
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, out.String(), "FEATURE          STATE    DEFAULT  MATURITY\n"+
+		"multi-container  Enabled  Enabled  stable\n")
+}
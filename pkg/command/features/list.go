@@ -0,0 +1,65 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kn-plugin-operator/pkg"
+)
+
+// newListCommand represents the command to list the feature flags of Knative Serving or Eventing
+func newListCommand(p *pkg.OperatorParams) *cobra.Command {
+	var featuresCMDFlags featuresFlags
+	var listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the feature flags of Knative Serving or Eventing with their states, defaults and maturities",
+		Example: `
+  # List the feature flags of Knative Serving
+  kn operator features list --component serving --namespace knative-serving`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateFeaturesFlags(&featuresCMDFlags); err != nil {
+				return err
+			}
+			version, cmData, err := getFeaturesState(featuresCMDFlags, p)
+			if err != nil {
+				return err
+			}
+			cmSchema, err := featuresSchema(featuresCMDFlags.Component, version)
+			if err != nil {
+				return err
+			}
+			return printFeatures(cmd.OutOrStdout(), collectFeatures(cmSchema, cmData))
+		},
+	}
+
+	addFeaturesFlags(listCmd, &featuresCMDFlags)
+
+	return listCmd
+}
+
+func printFeatures(out io.Writer, features []feature) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FEATURE\tSTATE\tDEFAULT\tMATURITY")
+	for _, f := range features {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Name, f.State, f.Default, f.Maturity)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// newToggleCommand represents the command to enable or disable a feature flag of Knative Serving or Eventing
+func newToggleCommand(p *pkg.OperatorParams, enable bool) *cobra.Command {
+	action, title := "disable", "Disable"
+	if enable {
+		action, title = "enable", "Enable"
+	}
+	var featuresCMDFlags featuresFlags
+	var toggleCmd = &cobra.Command{
+		Use:   fmt.Sprintf("%s FEATURE", action),
+		Short: fmt.Sprintf("%s a feature flag of Knative Serving or Eventing", title),
+		Example: fmt.Sprintf(`
+  # %s the feature flag tag-header-based-routing of Knative Serving
+  kn operator features %s tag-header-based-routing --component serving --namespace knative-serving`,
+			title, action),
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 || validateFeaturesFlags(&featuresCMDFlags) != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			cmSchema, err := featuresSchema(featuresCMDFlags.Component, "")
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return cmSchema.Keys(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := toggleFeature(args[0], enable, featuresCMDFlags, p); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The feature flag '%s' has been %sd for Knative %s in the namespace '%s'.\n",
+				args[0], action, featuresCMDFlags.Component, featuresCMDFlags.Namespace)
+			return nil
+		},
+	}
+
+	addFeaturesFlags(toggleCmd, &featuresCMDFlags)
+	toggleCmd.Flags().BoolVar(&featuresCMDFlags.AllowUnknown, "allow-unknown", false,
+		"Set the feature flag unknown to the schema of config-features with a warning")
+
+	return toggleCmd
+}
+
+// toggleFeature enables or disables the feature flag in the ConfigMap config-features of the custom resource
+func toggleFeature(name string, enable bool, featuresCMDFlags featuresFlags, p *pkg.OperatorParams) error {
+	if err := validateFeaturesFlags(&featuresCMDFlags); err != nil {
+		return err
+	}
+	version, cmData, err := getFeaturesState(featuresCMDFlags, p)
+	if err != nil {
+		return err
+	}
+	cmSchema, err := featuresSchema(featuresCMDFlags.Component, version)
+	if err != nil {
+		return err
+	}
+
+	value, err := featureValue(featuresCMDFlags.Component, cmSchema, name, enable)
+	if err != nil {
+		return err
+	}
+	pairs := []common.KeyValuePair{{Key: name, Value: value}}
+	if err := common.CheckConfigMapData(featuresCMDFlags.Component, featuresCMDFlags.Namespace, featuresConfigMap, pairs,
+		featuresCMDFlags.AllowUnknown, p); err != nil {
+		return err
+	}
+
+	cmName, _ := featuresData(cmData)
+	return common.ApplyKnativeCR(featuresCMDFlags.Component, featuresCMDFlags.Namespace, p,
+		common.SetConfigMapData(cmName, name, value))
+}
+
+// featureValue returns the value to enable or disable the feature flag. Knative Serving spells the values with
+// capital letters, and Knative Eventing with small letters.
+func featureValue(component string, cmSchema common.ConfigMapSchema, name string, enable bool) (string, error) {
+	value, action := "Disabled", "disabled"
+	if enable {
+		value, action = "Enabled", "enabled"
+	}
+	if component == common.EventingComponent {
+		value = strings.ToLower(value)
+	}
+
+	keySchema, ok := cmSchema.Lookup(name)
+	if !ok {
+		return value, nil
+	}
+	for _, allowed := range keySchema.Values {
+		if strings.EqualFold(allowed, value) {
+			return allowed, nil
+		}
+	}
+	return "", fmt.Errorf("The feature flag '%s' cannot be %s, but only set to one of %s with the command configure configmaps.",
+		name, action, strings.Join(keySchema.Values, ", "))
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+type historyFlags struct {
+	Component string
+	Namespace string
+}
+
+// NewHistoryCommand represents the history commands to list the revisions of Knative Serving or Eventing
+func NewHistoryCommand(p *pkg.OperatorParams) *cobra.Command {
+	var historyCMDFlags historyFlags
+	var historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "List the configuration revisions of Knative Serving or Eventing",
+		Example: `
+  # List the configuration revisions of Knative Serving
+  kn operator history --component serving --namespace knative-serving`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateHistoryFlags(historyCMDFlags); err != nil {
+				return err
+			}
+			fillDefaultNamespace(&historyCMDFlags.Namespace, historyCMDFlags.Component)
+
+			revisions, err := listRevisions(historyCMDFlags, p)
+			if err != nil {
+				return err
+			}
+
+			if len(revisions) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No revision is found for Knative %s in the namespace '%s'.\n",
+					historyCMDFlags.Component, historyCMDFlags.Namespace)
+				return nil
+			}
+			return printRevisions(cmd.OutOrStdout(), revisions)
+		},
+	}
+
+	historyCmd.Flags().StringVarP(&historyCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
+	historyCmd.Flags().StringVarP(&historyCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative component")
+
+	return historyCmd
+}
+
+func validateHistoryFlags(historyCMDFlags historyFlags) error {
+	return validateComponent(historyCMDFlags.Component)
+}
+
+func validateComponent(component string) error {
+	if !strings.EqualFold(component, common.ServingComponent) && !strings.EqualFold(component, common.EventingComponent) {
+		return fmt.Errorf("You need to specify the component for Knative: serving or eventing.")
+	}
+	return nil
+}
+
+func fillDefaultNamespace(namespace *string, component string) {
+	if *namespace != "" {
+		return
+	}
+	if strings.EqualFold(component, common.ServingComponent) {
+		*namespace = common.DefaultKnativeServingNamespace
+	} else if strings.EqualFold(component, common.EventingComponent) {
+		*namespace = common.DefaultKnativeEventingNamespace
+	}
+}
+
+func listRevisions(historyCMDFlags historyFlags, p *pkg.OperatorParams) ([]common.Revision, error) {
+	ctx := p.Context()
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	history := common.History{
+		KubeClient: kubeClient,
+	}
+	return history.List(ctx, historyCMDFlags.Component, historyCMDFlags.Namespace)
+}
+
+func printRevisions(out io.Writer, revisions []common.Revision) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tTIMESTAMP\tUSER\tCOMMAND")
+	for _, r := range revisions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Revision, r.Timestamp.Format(time.RFC3339), r.User, r.Command)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestValidateHistoryFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		historyFlags   historyFlags
+		expectedResult error
+	}{{
+		name: "Knative Serving",
+		historyFlags: historyFlags{
+			Component: "serving",
+		},
+		expectedResult: nil,
+	}, {
+		name: "Knative Eventing",
+		historyFlags: historyFlags{
+			Component: "Eventing",
+			Namespace: "test-eventing",
+		},
+		expectedResult: nil,
+	}, {
+		name: "Knative with invalid component name",
+		historyFlags: historyFlags{
+			Component: "eventing-test",
+		},
+		expectedResult: fmt.Errorf("You need to specify the component for Knative: serving or eventing."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateHistoryFlags(tt.historyFlags)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
+
+func TestFillDefaultNamespace(t *testing.T) {
+	namespace := ""
+	fillDefaultNamespace(&namespace, "serving")
+	testingUtil.AssertEqual(t, namespace, common.DefaultKnativeServingNamespace)
+
+	namespace = ""
+	fillDefaultNamespace(&namespace, "eventing")
+	testingUtil.AssertEqual(t, namespace, common.DefaultKnativeEventingNamespace)
+
+	namespace = "test-eventing"
+	fillDefaultNamespace(&namespace, "eventing")
+	testingUtil.AssertEqual(t, namespace, "test-eventing")
+}
+
+func TestPrintRevisions(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := printRevisions(out, []common.Revision{{
+		Revision:  1,
+		Timestamp: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC),
+		User:      "admin",
+		Command:   "kn-operator configure replicas --replicas 2",
+	}, {
+		Revision:  2,
+		Timestamp: time.Date(2022, 5, 2, 10, 0, 0, 0, time.UTC),
+		User:      "dev",
+		Command:   "kn-operator remove replicas",
+	}})
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, out.String(), `REVISION  TIMESTAMP             USER   COMMAND
+1         2022-05-01T10:00:00Z  admin  kn-operator configure replicas --replicas 2
+2         2022-05-02T10:00:00Z  dev    kn-operator remove replicas
+`)
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+type undoFlags struct {
+	Component  string
+	Namespace  string
+	ToRevision int
+}
+
+// NewUndoCommand represents the undo commands to restore a configuration revision of Knative Serving or Eventing
+func NewUndoCommand(p *pkg.OperatorParams) *cobra.Command {
+	var undoCMDFlags undoFlags
+	var undoCmd = &cobra.Command{
+		Use:   "undo",
+		Short: "Restore a configuration revision of Knative Serving or Eventing",
+		Example: `
+  # Undo the last configuration change of Knative Serving
+  kn operator undo --component serving --namespace knative-serving
+  # Restore the configuration revision 3 of Knative Eventing
+  kn operator undo --component eventing --namespace knative-eventing --to-revision 3`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateUndoFlags(undoCMDFlags); err != nil {
+				return err
+			}
+			fillDefaultNamespace(&undoCMDFlags.Namespace, undoCMDFlags.Component)
+
+			revision, err := undoRevision(undoCMDFlags, p)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The revision %d of Knative %s has been restored in the namespace '%s'.\n",
+				revision, undoCMDFlags.Component, undoCMDFlags.Namespace)
+			return nil
+		},
+	}
+
+	undoCmd.Flags().StringVarP(&undoCMDFlags.Component, "component", "c", "", "The flag to specify the component name")
+	undoCmd.Flags().StringVarP(&undoCMDFlags.Namespace, "namespace", "n", "", "The namespace of the Knative component")
+	undoCmd.Flags().IntVar(&undoCMDFlags.ToRevision, "to-revision", 0, "The revision to restore (default is the latest revision)")
+
+	return undoCmd
+}
+
+func validateUndoFlags(undoCMDFlags undoFlags) error {
+	if err := validateComponent(undoCMDFlags.Component); err != nil {
+		return err
+	}
+	if undoCMDFlags.ToRevision < 0 {
+		return fmt.Errorf("The revision needs to be a positive number.")
+	}
+	return nil
+}
+
+func undoRevision(undoCMDFlags undoFlags, p *pkg.OperatorParams) (int, error) {
+	ctx := p.Context()
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return 0, fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	history := common.History{
+		KubeClient: kubeClient,
+	}
+
+	var revision *common.Revision
+	if undoCMDFlags.ToRevision == 0 {
+		revisions, err := history.List(ctx, undoCMDFlags.Component, undoCMDFlags.Namespace)
+		if err != nil {
+			return 0, err
+		}
+		if len(revisions) == 0 {
+			return 0, fmt.Errorf("No revision is found for Knative %s in the namespace '%s'.",
+				undoCMDFlags.Component, undoCMDFlags.Namespace)
+		}
+		revision = &revisions[len(revisions)-1]
+	} else {
+		revision, err = history.Get(ctx, undoCMDFlags.Component, undoCMDFlags.Namespace, undoCMDFlags.ToRevision)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// The current spec is recorded as a new revision, so that the undo can be undone as well
+	err = common.UpdateKnativeCR(undoCMDFlags.Component, undoCMDFlags.Namespace, p, common.ReplaceSpec(revision.Spec))
+	if err != nil {
+		return 0, err
+	}
+
+	return revision.Revision, nil
+}
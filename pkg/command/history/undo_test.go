@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"fmt"
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestValidateUndoFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		undoFlags      undoFlags
+		expectedResult error
+	}{{
+		name: "Knative Serving with the latest revision",
+		undoFlags: undoFlags{
+			Component: "serving",
+		},
+		expectedResult: nil,
+	}, {
+		name: "Knative Eventing with a revision",
+		undoFlags: undoFlags{
+			Component:  "eventing",
+			ToRevision: 3,
+		},
+		expectedResult: nil,
+	}, {
+		name:           "Knative with no component name",
+		undoFlags:      undoFlags{},
+		expectedResult: fmt.Errorf("You need to specify the component for Knative: serving or eventing."),
+	}, {
+		name: "Knative Serving with a negative revision",
+		undoFlags: undoFlags{
+			Component:  "serving",
+			ToRevision: -1,
+		},
+		expectedResult: fmt.Errorf("The revision needs to be a positive number."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateUndoFlags(tt.undoFlags)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
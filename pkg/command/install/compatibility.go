@@ -0,0 +1,148 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// operatorSupportedMinors is the number of the minor versions older than its own, which a release of the Knative
+// Operator can still install
+const operatorSupportedMinors = 3
+
+// operatorMaxPatches is the number of the patch releases of a minor version, which are probed at most
+const operatorMaxPatches = 20
+
+// operatorReleaseTimeout is the maximum time to find the newest patch release of the Knative Operator
+const operatorReleaseTimeout = 30 * time.Second
+
+// operatorReleaseExists checks if the release of the Knative Operator can be downloaded
+var operatorReleaseExists = func(ctx context.Context, version string) (bool, error) {
+	url, err := getOperatorURL(version)
+	if err != nil {
+		return false, nil
+	}
+	return common.URLExists(ctx, url)
+}
+
+// compatibleOperatorVersion returns the release of the Knative Operator, which installs the version of the Knative
+// component. The Knative Operator is released together with Knative, so the newest patch of the same minor version is
+// picked, as long as it is not older than the patch of the component. The version latest or nightly picks the same
+// version of the Knative Operator. The patch releases are probed one by one, up to operatorMaxPatches within
+// operatorReleaseTimeout.
+func compatibleOperatorVersion(ctx context.Context, componentVersion string) (string, error) {
+	if strings.EqualFold(componentVersion, common.Nightly) {
+		return common.Nightly, nil
+	}
+	version := common.SemanticVersion(componentVersion)
+	if version == "" {
+		return common.Latest, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, operatorReleaseTimeout)
+	defer cancel()
+
+	minor := strings.TrimPrefix(semver.MajorMinor(version), "v")
+	newest := -1
+	for patch := 0; patch < operatorMaxPatches; patch++ {
+		exists, err := operatorReleaseExists(ctx, fmt.Sprintf("%s.%d", minor, patch))
+		if err != nil {
+			return "", fmt.Errorf("Cannot look up the releases of the Knative Operator %s.x: %v, "+
+				"please use --operator-version to specify it.", minor, err)
+		}
+		if !exists {
+			break
+		}
+		newest = patch
+	}
+	if newest < patchNumber(version) {
+		return "", fmt.Errorf("No release of the Knative Operator %s.x is found to install the Knative version %s, "+
+			"please use --operator-version to specify it.", minor, componentVersion)
+	}
+	return fmt.Sprintf("%s.%d", minor, newest), nil
+}
+
+// checkOperatorVersion checks that the release of the Knative Operator can install the version of the Knative
+// component. The versions latest, nightly or the versions not recognized are not checked.
+func checkOperatorVersion(operatorVersion, componentVersion string) error {
+	operator, component := common.SemanticVersion(operatorVersion), common.SemanticVersion(componentVersion)
+	if operator == "" || component == "" {
+		return nil
+	}
+
+	minimum := strings.TrimPrefix(component, "v")
+	if semver.Compare(operator, component) < 0 {
+		return fmt.Errorf("The Knative Operator %s is too old to install the Knative version %s, "+
+			"it needs to be version %s or newer.", operatorVersion, componentVersion, minimum)
+	}
+	if semver.Major(operator) != semver.Major(component) ||
+		semver.Compare(minorOffset(operator, -operatorSupportedMinors), semver.MajorMinor(component)) > 0 {
+		return fmt.Errorf("The Knative Operator %s is too new to install the Knative version %s, "+
+			"it needs to be version %s up to %s.", operatorVersion, componentVersion, minimum,
+			strings.TrimPrefix(minorOffset(component, operatorSupportedMinors), "v")+".x")
+	}
+	return nil
+}
+
+// patchNumber returns the patch number of the canonical semantic version
+func patchNumber(version string) int {
+	patch, _ := strconv.Atoi(strings.TrimPrefix(version, semver.MajorMinor(version)+"."))
+	return patch
+}
+
+// minorOffset returns the major and minor version, moved by the offset of minor versions
+func minorOffset(version string, offset int) string {
+	minor, _ := strconv.Atoi(strings.TrimPrefix(semver.MajorMinor(version), semver.Major(version)+"."))
+	minor += offset
+	if minor < 0 {
+		minor = 0
+	}
+	return fmt.Sprintf("%s.%d", semver.Major(version), minor)
+}
+
+// installOperatorIfMissing installs the Knative Operator for the Knative component, if it is not installed yet.
+// The Knative Operator already installed is kept, with a warning if it does not match the operator flags.
+func installOperatorIfMissing(installFlags *InstallFlags, p *pkg.OperatorParams, progress func(text string)) error {
+	exists, ns, version, err := checkIfOperatorInstalled(p)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if installFlags.OperatorNamespace != "" && installFlags.OperatorNamespace != ns {
+			p.Warn("The Knative Operator is already installed in the namespace '%s', the operator namespace '%s' is ignored.",
+				ns, installFlags.OperatorNamespace)
+		}
+		if err := checkOperatorVersion(version, installFlags.Version); err != nil {
+			p.Warn("%v", err)
+		}
+		return nil
+	}
+
+	operatorFlags, err := operatorInstallFlags(p.Context(), installFlags)
+	if err != nil {
+		return err
+	}
+	progress(fmt.Sprintf("Installing Knative Operator, Version %s...", operatorFlags.Version))
+	return installOperator(operatorFlags, p)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestCompatibleOperatorVersion(t *testing.T) {
+	defer fakeOperatorReleases("1.7.0", "1.8.0", "1.8.1", "1.8.2", "1.8.3", "1.8.4", "1.9.0", "1.9.1")()
+
+	for _, tt := range []struct {
+		name             string
+		componentVersion string
+		expected         string
+		expectedError    string
+	}{{
+		name:             "Patch version",
+		componentVersion: "1.8.3",
+		expected:         "1.8.4",
+	}, {
+		name:             "Version with prefix",
+		componentVersion: "v1.9.1",
+		expected:         "1.9.1",
+	}, {
+		name:             "Minor version",
+		componentVersion: "1.7",
+		expected:         "1.7.0",
+	}, {
+		name:             "Patch newer than the operator releases",
+		componentVersion: "1.9.2",
+		expectedError: "No release of the Knative Operator 1.9.x is found to install the Knative version 1.9.2, " +
+			"please use --operator-version to specify it.",
+	}, {
+		name:             "Minor version without operator releases",
+		componentVersion: "1.10",
+		expectedError: "No release of the Knative Operator 1.10.x is found to install the Knative version 1.10, " +
+			"please use --operator-version to specify it.",
+	}, {
+		name:             "Latest version",
+		componentVersion: "latest",
+		expected:         "latest",
+	}, {
+		name:             "Nightly version",
+		componentVersion: "nightly",
+		expected:         "nightly",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := compatibleOperatorVersion(context.Background(), tt.componentVersion)
+			if tt.expectedError != "" {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+				return
+			}
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, result, tt.expected)
+		})
+	}
+}
+
+// fakeOperatorReleases replaces the lookup of the releases of the Knative Operator with the versions, and returns
+// the function to restore it
+func fakeOperatorReleases(versions ...string) func() {
+	original := operatorReleaseExists
+	operatorReleaseExists = func(ctx context.Context, version string) (bool, error) {
+		for _, v := range versions {
+			if v == version {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return func() {
+		operatorReleaseExists = original
+	}
+}
+
+func TestCompatibleOperatorVersionProbe(t *testing.T) {
+	original := operatorReleaseExists
+	defer func() {
+		operatorReleaseExists = original
+	}()
+
+	probes := 0
+	operatorReleaseExists = func(ctx context.Context, version string) (bool, error) {
+		probes++
+		return true, nil
+	}
+	result, err := compatibleOperatorVersion(context.Background(), "1.9.0")
+	testingUtil.AssertEqual(t, err, nil)
+	testingUtil.AssertEqual(t, result, fmt.Sprintf("1.9.%d", operatorMaxPatches-1))
+	testingUtil.AssertEqual(t, probes, operatorMaxPatches)
+
+	operatorReleaseExists = func(ctx context.Context, version string) (bool, error) {
+		return false, ctx.Err()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = compatibleOperatorVersion(ctx, "1.9.0")
+	testingUtil.AssertEqual(t, err.Error(), "Cannot look up the releases of the Knative Operator 1.9.x: context canceled, "+
+		"please use --operator-version to specify it.")
+}
+
+func TestCheckOperatorVersion(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		operatorVersion  string
+		componentVersion string
+		expectedError    error
+	}{{
+		name:             "Same version",
+		operatorVersion:  "1.8.3",
+		componentVersion: "1.8.3",
+	}, {
+		name:             "Newer patch of the operator",
+		operatorVersion:  "1.8.4",
+		componentVersion: "1.8",
+	}, {
+		name:             "Older patch of the operator",
+		operatorVersion:  "1.8.0",
+		componentVersion: "1.8.3",
+		expectedError:    fmt.Errorf("The Knative Operator 1.8.0 is too old to install the Knative version 1.8.3, it needs to be version 1.8.3 or newer."),
+	}, {
+		name:             "Operator supporting the older component",
+		operatorVersion:  "v1.11.1",
+		componentVersion: "1.8.0",
+	}, {
+		name:             "Latest operator",
+		operatorVersion:  "latest",
+		componentVersion: "1.8.0",
+	}, {
+		name:             "Latest component",
+		operatorVersion:  "1.8.0",
+		componentVersion: "latest",
+	}, {
+		name:             "Operator too old",
+		operatorVersion:  "1.7.2",
+		componentVersion: "1.8.0",
+		expectedError:    fmt.Errorf("The Knative Operator 1.7.2 is too old to install the Knative version 1.8.0, it needs to be version 1.8.0 or newer."),
+	}, {
+		name:             "Operator too new",
+		operatorVersion:  "1.12.0",
+		componentVersion: "1.8.0",
+		expectedError:    fmt.Errorf("The Knative Operator 1.12.0 is too new to install the Knative version 1.8.0, it needs to be version 1.8.0 up to 1.11.x."),
+	}, {
+		name:             "Operator of another major version",
+		operatorVersion:  "1.0.0",
+		componentVersion: "0.26.0",
+		expectedError:    fmt.Errorf("The Knative Operator 1.0.0 is too new to install the Knative version 0.26.0, it needs to be version 0.26.0 up to 0.29.x."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOperatorVersion(tt.operatorVersion, tt.componentVersion)
+			if tt.expectedError == nil {
+				testingUtil.AssertEqual(t, err, nil)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// parseComponents returns the Knative components to install, in the order serving and eventing. The flag --all
+// selects both components, and the flag --component accepts a comma-separated list of components.
+func parseComponents(installFlags *InstallFlags) ([]string, error) {
+	if installFlags.All {
+		if installFlags.Component != "" {
+			return nil, fmt.Errorf("You can specify either --all or the components.")
+		}
+		return []string{common.ServingComponent, common.EventingComponent}, nil
+	}
+
+	selected := map[string]bool{}
+	for _, component := range strings.Split(installFlags.Component, ",") {
+		component = strings.ToLower(strings.TrimSpace(component))
+		if component == "" {
+			continue
+		}
+		if component != common.ServingComponent && component != common.EventingComponent {
+			return nil, fmt.Errorf("The component '%s' is not known, please use serving, eventing or both separated by a comma.", component)
+		}
+		selected[component] = true
+	}
+
+	components := []string{}
+	for _, component := range []string{common.ServingComponent, common.EventingComponent} {
+		if selected[component] {
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
+
+func validateOperatorFlags(installFlags *InstallFlags, components []string) error {
+	if len(components) == 0 && (installFlags.OperatorNamespace != "" || installFlags.OperatorVersion != "") {
+		return fmt.Errorf("The operator namespace and version can only be specified with the components, " +
+			"please use --namespace and --version to install the Knative Operator alone.")
+	}
+	if len(components) > 1 && installFlags.Namespace != "" {
+		return fmt.Errorf("You cannot specify the namespace for several components, they are installed in the namespaces %s and %s.",
+			common.DefaultKnativeServingNamespace, common.DefaultKnativeEventingNamespace)
+	}
+	if installFlags.OperatorVersion != "" {
+		if _, err := getOperatorURL(installFlags.OperatorVersion); err != nil {
+			return err
+		}
+		return checkOperatorVersion(installFlags.OperatorVersion, installFlags.Version)
+	}
+	return nil
+}
+
+// operatorInstallFlags returns the options to install the Knative Operator, when it is missing for the Knative components.
+// The release compatible with the version of the components is picked, if the operator version is not specified.
+func operatorInstallFlags(ctx context.Context, installFlags *InstallFlags) (*InstallFlags, error) {
+	operatorFlags := &InstallFlags{
+		Namespace: installFlags.OperatorNamespace,
+		Version:   installFlags.OperatorVersion,
+	}
+	if operatorFlags.Namespace == "" {
+		operatorFlags.Namespace = common.DefaultNamespace
+	}
+	if operatorFlags.Version == "" {
+		version, err := compatibleOperatorVersion(ctx, installFlags.Version)
+		if err != nil {
+			return nil, err
+		}
+		operatorFlags.Version = version
+	}
+	return operatorFlags, nil
+}
+
+// componentsInstallFlags returns the options to install each of the Knative components, with the default values filled
+func componentsInstallFlags(installFlags *InstallFlags, components []string) []*InstallFlags {
+	componentsFlags := make([]*InstallFlags, 0, len(components))
+	for _, component := range components {
+		componentFlags := *installFlags
+		componentFlags.All = false
+		componentFlags.Component = component
+		if component != common.ServingComponent {
+			// The ingress only applies to Knative Serving
+			componentFlags.Istio, componentFlags.Kourier, componentFlags.Contour = false, false, false
+			componentFlags.IstioNamespace = ""
+		}
+		componentFlags.fill_defaults()
+		componentsFlags = append(componentsFlags, &componentFlags)
+	}
+	return componentsFlags
+}
+
+// componentResult is the result of the installation of a Knative component
+type componentResult struct {
+	component string
+	namespace string
+	// version is the version the component is installed with, after the default is filled
+	version string
+	err     error
+}
+
+// runComponentsInstallation installs the Knative Operator if it is missing, then installs the Knative components
+// in parallel, or the only Knative component. All the components are installed even if one of them fails. The results are returned per component,
+// and the error combines the errors of all the components.
+func runComponentsInstallation(installFlags *InstallFlags, components []string, p *pkg.OperatorParams,
+	showProgress func(text string)) ([]componentResult, error) {
+	// The operator is installed once, before the components are installed concurrently
+	err := installOperatorIfMissing(installFlags, p, func(text string) {
+		p.SetStep(text)
+		showProgress(text)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	progress := newComponentsProgress(components, func(text string) {
+		p.SetStep(text)
+		showProgress(text)
+	})
+	results := installComponentsInParallel(componentsInstallFlags(installFlags, components), p, progress, installComponent)
+	return results, componentsError(results)
+}
+
+// installComponentsInParallel installs each Knative component with its own copy of the flags and the params, whose
+// steps are reported to the progress prefixed with the component
+func installComponentsInParallel(componentsFlags []*InstallFlags, p *pkg.OperatorParams, progress *componentsProgress,
+	install func(componentFlags *InstallFlags, p *pkg.OperatorParams) error) []componentResult {
+	results := make([]componentResult, len(componentsFlags))
+	var wg sync.WaitGroup
+	for i := range componentsFlags {
+		componentFlags := componentsFlags[i]
+		componentParams := p.WithContext(p.Context())
+		componentParams.StepReporter = progress.reporter(componentFlags.Component)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := install(componentFlags, componentParams)
+			results[i] = componentResult{
+				component: componentFlags.Component,
+				namespace: componentFlags.Namespace,
+				version:   componentFlags.Version,
+				err:       err,
+			}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// componentsProgress combines the latest steps of the Knative components installed in parallel into one text
+type componentsProgress struct {
+	lock       sync.Mutex
+	components []string
+	steps      map[string]string
+	report     func(text string)
+}
+
+func newComponentsProgress(components []string, report func(text string)) *componentsProgress {
+	return &componentsProgress{
+		components: components,
+		steps:      map[string]string{},
+		report:     report,
+	}
+}
+
+// reporter returns the function receiving the steps of the component
+func (progress *componentsProgress) reporter(component string) func(step string) {
+	return func(step string) {
+		progress.lock.Lock()
+		defer progress.lock.Unlock()
+		progress.steps[component] = step
+		progress.report(progress.text())
+	}
+}
+
+// text returns the steps of the components prefixed with the component, or the step of the only component
+func (progress *componentsProgress) text() string {
+	if len(progress.components) == 1 {
+		return progress.steps[progress.components[0]]
+	}
+	parts := []string{}
+	for _, component := range progress.components {
+		if step := progress.steps[component]; step != "" {
+			parts = append(parts, fmt.Sprintf("[%s] %s", component, step))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// componentsError combines the errors of the installation of the Knative components into one error, which reports
+// the component of each error
+func componentsError(results []componentResult) error {
+	messages := []string{}
+	for _, result := range results {
+		if result.err != nil {
+			messages = append(messages, fmt.Sprintf("Knative %s failed to be installed: %v", result.component, result.err))
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "\n"))
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestParseComponents(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		installFlags       InstallFlags
+		expectedComponents []string
+		expectedError      error
+	}{{
+		name:               "Knative Operator",
+		installFlags:       InstallFlags{},
+		expectedComponents: []string{},
+	}, {
+		name:               "Single component",
+		installFlags:       InstallFlags{Component: "Serving"},
+		expectedComponents: []string{"serving"},
+	}, {
+		name:               "Several components in any order",
+		installFlags:       InstallFlags{Component: "eventing, serving,eventing"},
+		expectedComponents: []string{"serving", "eventing"},
+	}, {
+		name:               "All the components",
+		installFlags:       InstallFlags{All: true},
+		expectedComponents: []string{"serving", "eventing"},
+	}, {
+		name:          "All and the components",
+		installFlags:  InstallFlags{All: true, Component: "serving"},
+		expectedError: fmt.Errorf("You can specify either --all or the components."),
+	}, {
+		name:          "Unknown component",
+		installFlags:  InstallFlags{Component: "serving,functions"},
+		expectedError: fmt.Errorf("The component 'functions' is not known, please use serving, eventing or both separated by a comma."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			components, err := parseComponents(&tt.installFlags)
+			if tt.expectedError == nil {
+				testingUtil.AssertEqual(t, err, nil)
+				testingUtil.AssertDeepEqual(t, components, tt.expectedComponents)
+			} else {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError.Error())
+			}
+		})
+	}
+}
+
+func TestValidateOperatorFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		installFlags   InstallFlags
+		components     []string
+		expectedResult error
+	}{{
+		name:         "Operator namespace for a component",
+		installFlags: InstallFlags{OperatorNamespace: "knative-operator"},
+		components:   []string{"serving"},
+	}, {
+		name:         "Namespace for a component",
+		installFlags: InstallFlags{Namespace: "serving"},
+		components:   []string{"serving"},
+	}, {
+		name:         "Operator version without component",
+		installFlags: InstallFlags{OperatorVersion: "1.8.0"},
+		components:   []string{},
+		expectedResult: fmt.Errorf("The operator namespace and version can only be specified with the components, " +
+			"please use --namespace and --version to install the Knative Operator alone."),
+	}, {
+		name:         "Compatible operator version",
+		installFlags: InstallFlags{OperatorVersion: "1.9.2", Version: "1.8.0"},
+		components:   []string{"serving"},
+	}, {
+		name:           "Incompatible operator version",
+		installFlags:   InstallFlags{OperatorVersion: "1.7.0", Version: "1.8.0"},
+		components:     []string{"serving", "eventing"},
+		expectedResult: fmt.Errorf("The Knative Operator 1.7.0 is too old to install the Knative version 1.8.0, it needs to be version 1.8.0 or newer."),
+	}, {
+		name:           "Invalid operator version",
+		installFlags:   InstallFlags{OperatorVersion: "stable"},
+		components:     []string{"serving"},
+		expectedResult: fmt.Errorf("stable is not a semantic version"),
+	}, {
+		name:           "Namespace for several components",
+		installFlags:   InstallFlags{Namespace: "knative"},
+		components:     []string{"serving", "eventing"},
+		expectedResult: fmt.Errorf("You cannot specify the namespace for several components, they are installed in the namespaces knative-serving and knative-eventing."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateOperatorFlags(&tt.installFlags, tt.components)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
+
+func TestOperatorInstallFlags(t *testing.T) {
+	defer fakeOperatorReleases("1.8.0", "1.8.1")()
+
+	for _, tt := range []struct {
+		name          string
+		installFlags  InstallFlags
+		expected      InstallFlags
+		expectedError string
+	}{{
+		name:         "Compatible release",
+		installFlags: InstallFlags{Component: "serving", Version: "1.8.0"},
+		expected:     InstallFlags{Namespace: common.DefaultNamespace, Version: "1.8.1"},
+	}, {
+		name:         "Latest release",
+		installFlags: InstallFlags{Component: "eventing", Version: common.Latest},
+		expected:     InstallFlags{Namespace: common.DefaultNamespace, Version: common.Latest},
+	}, {
+		name:         "Operator flags",
+		installFlags: InstallFlags{OperatorNamespace: "knative-operator", OperatorVersion: "1.8.0"},
+		expected:     InstallFlags{Namespace: "knative-operator", Version: "1.8.0"},
+	}, {
+		name:         "No compatible release",
+		installFlags: InstallFlags{Component: "serving", Version: "1.9.0"},
+		expectedError: "No release of the Knative Operator 1.9.x is found to install the Knative version 1.9.0, " +
+			"please use --operator-version to specify it.",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := operatorInstallFlags(context.Background(), &tt.installFlags)
+			if tt.expectedError != "" {
+				testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+				return
+			}
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, *result, tt.expected)
+		})
+	}
+}
+
+func TestComponentsInstallFlags(t *testing.T) {
+	installFlags := &InstallFlags{
+		All:               true,
+		Version:           "1.8.0",
+		Kourier:           true,
+		OperatorNamespace: "knative-operator",
+		Wait:              true,
+	}
+	componentsFlags := componentsInstallFlags(installFlags, []string{"serving", "eventing"})
+	testingUtil.AssertEqual(t, len(componentsFlags), 2)
+	testingUtil.AssertEqual(t, *componentsFlags[0], InstallFlags{
+		Component:         "serving",
+		IstioNamespace:    common.DefaultIstioNamespace,
+		Namespace:         common.DefaultKnativeServingNamespace,
+		Version:           "1.8.0",
+		Kourier:           true,
+		OperatorNamespace: "knative-operator",
+		Wait:              true,
+	})
+	testingUtil.AssertEqual(t, *componentsFlags[1], InstallFlags{
+		Component:         "eventing",
+		Namespace:         common.DefaultKnativeEventingNamespace,
+		Version:           "1.8.0",
+		OperatorNamespace: "knative-operator",
+		Wait:              true,
+	})
+}
+
+func TestComponentsError(t *testing.T) {
+	testingUtil.AssertEqual(t, componentsError([]componentResult{{component: "serving"}, {component: "eventing"}}), nil)
+	testingUtil.AssertEqual(t, componentsError([]componentResult{
+		{component: "serving"},
+		{component: "eventing", err: fmt.Errorf("timeout")},
+	}).Error(), "Knative eventing failed to be installed: timeout")
+	testingUtil.AssertEqual(t, componentsError([]componentResult{
+		{component: "serving", err: fmt.Errorf("conflict")},
+		{component: "eventing", err: fmt.Errorf("timeout")},
+	}).Error(), "Knative serving failed to be installed: conflict\nKnative eventing failed to be installed: timeout")
+}
+
+func TestInstallComponentsInParallel(t *testing.T) {
+	installFlags := &InstallFlags{All: true, Wait: true}
+	componentsFlags := componentsInstallFlags(installFlags, []string{"serving", "eventing"})
+	p := &pkg.OperatorParams{}
+	var reported []string
+	progress := newComponentsProgress([]string{"serving", "eventing"}, func(text string) {
+		reported = append(reported, text)
+	})
+
+	// Both components wait for each other, so that the test fails with a deadlock if they are installed one by one
+	var started sync.WaitGroup
+	started.Add(2)
+	results := installComponentsInParallel(componentsFlags, p, progress, func(componentFlags *InstallFlags, p *pkg.OperatorParams) error {
+		p.SetStep(fmt.Sprintf("Installing Knative %s...", componentFlags.Component))
+		started.Done()
+		started.Wait()
+		if componentFlags.Component == "eventing" {
+			return fmt.Errorf("timeout")
+		}
+		componentFlags.Version = "1.8.1"
+		return nil
+	})
+
+	testingUtil.AssertDeepEqual(t, results, []componentResult{{
+		component: "serving",
+		namespace: common.DefaultKnativeServingNamespace,
+		version:   "1.8.1",
+	}, {
+		component: "eventing",
+		namespace: common.DefaultKnativeEventingNamespace,
+		version:   common.Latest,
+		err:       fmt.Errorf("timeout"),
+	}})
+	testingUtil.AssertEqual(t, componentsError(results).Error(), "Knative eventing failed to be installed: timeout")
+	testingUtil.AssertEqual(t, installFlags.Version, "")
+	testingUtil.AssertEqual(t, len(reported), 2)
+	testingUtil.AssertEqual(t, reported[1], "[serving] Installing Knative serving... [eventing] Installing Knative eventing...")
+	testingUtil.AssertEqual(t, p.Step(), "")
+}
+
+func TestComponentsProgress(t *testing.T) {
+	var reported string
+	progress := newComponentsProgress([]string{"serving", "eventing"}, func(text string) {
+		reported = text
+	})
+	progress.reporter("eventing")("Waiting for Knative Eventing...")
+	testingUtil.AssertEqual(t, reported, "[eventing] Waiting for Knative Eventing...")
+	progress.reporter("serving")("Installing Knative Serving...")
+	testingUtil.AssertEqual(t, reported, "[serving] Installing Knative Serving... [eventing] Waiting for Knative Eventing...")
+}
+
+func TestComponentsProgressSingleComponent(t *testing.T) {
+	var reported string
+	progress := newComponentsProgress([]string{"serving"}, func(text string) {
+		reported = text
+	})
+	progress.reporter("serving")("Installing Knative Serving...")
+	testingUtil.AssertEqual(t, reported, "Installing Knative Serving...")
+}
+
+func TestRunInstallationInvalidComponent(t *testing.T) {
+	results, err := runInstallation(&InstallFlags{Component: "servng"}, &pkg.OperatorParams{}, func(string) {})
+	testingUtil.AssertEqual(t, len(results), 0)
+	testingUtil.AssertEqual(t, err.Error(),
+		"The component 'servng' is not known, please use serving, eventing or both separated by a comma.")
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/client/clientset/versioned"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// maxPodEvents is the maximum number of the latest events reported for each pod
+const maxPodEvents = 3
+
+// versionLabels are the labels of the deployments, which carry the version of the Knative component
+var versionLabels = []string{"app.kubernetes.io/version", "serving.knative.dev/release", "eventing.knative.dev/release"}
+
+// withDiagnostics appends the diagnostics of the Knative component to the error, if the wait for the
+// component timed out. Any other error is returned as it is.
+func withDiagnostics(ctx context.Context, err error, client kubernetes.Interface, operatorClient versioned.Interface,
+	installFlags *InstallFlags, expectedDeployments []string) error {
+	if !errors.Is(err, common.ErrWaitTimeout) {
+		return err
+	}
+
+	component := common.EventingComponent
+	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
+		component = common.ServingComponent
+	}
+	lines := []string{fmt.Sprintf("Knative %s, Version %s is not ready in the namespace '%s': %v",
+		component, installFlags.Version, installFlags.Namespace, err)}
+	lines = append(lines, diagnoseDeployments(ctx, client, installFlags.Namespace, installFlags.Version, expectedDeployments)...)
+	lines = append(lines, diagnoseKnativeCR(ctx, operatorClient, component, installFlags.Namespace)...)
+	return &diagnosedError{err: err, message: strings.Join(lines, common.LineWrapper)}
+}
+
+// diagnosedError keeps the original error of the wait, so that it can still be checked with errors.Is
+type diagnosedError struct {
+	err     error
+	message string
+}
+
+func (e *diagnosedError) Error() string {
+	return e.message
+}
+
+func (e *diagnosedError) Unwrap() error {
+	return e.err
+}
+
+// diagnoseDeployments reports the state of each expected deployment, together with the pods not running
+// and their latest events
+func diagnoseDeployments(ctx context.Context, client kubernetes.Interface, namespace, version string,
+	expectedDeployments []string) []string {
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		events = &corev1.EventList{}
+	}
+
+	lines := []string{}
+	for _, name := range expectedDeployments {
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			lines = append(lines, describeDeployment(name, nil, version))
+			continue
+		} else if err != nil {
+			lines = append(lines, fmt.Sprintf("  deployment %s: cannot be retrieved: %v", name, err))
+			continue
+		}
+		lines = append(lines, describeDeployment(name, deployment, version))
+
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			continue
+		}
+		for i := range pods.Items {
+			lines = append(lines, describePod(&pods.Items[i], events.Items)...)
+		}
+	}
+	return lines
+}
+
+// describeDeployment reports if the deployment is found, its version label and its available replicas
+func describeDeployment(name string, deployment *v1.Deployment, version string) string {
+	if deployment == nil {
+		return fmt.Sprintf("  deployment %s: missing", name)
+	}
+
+	found := "none"
+	for _, label := range versionLabels {
+		if value, ok := deployment.Labels[label]; ok {
+			found = value
+			break
+		}
+	}
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return fmt.Sprintf("  deployment %s: found, version %s (expected %s), %d/%d replicas available",
+		name, found, version, deployment.Status.AvailableReplicas, desired)
+}
+
+// describePod reports why the pod is not running, and its latest events. Nothing is reported for the
+// pods running with all the containers ready.
+func describePod(pod *corev1.Pod, events []corev1.Event) []string {
+	problems := []string{}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			problems = append(problems, fmt.Sprintf("%s: %s", condition.Reason, condition.Message))
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			problems = append(problems, fmt.Sprintf("container %s is waiting: %s: %s", status.Name,
+				status.State.Waiting.Reason, status.State.Waiting.Message))
+		} else if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			problems = append(problems, fmt.Sprintf("container %s terminated: %s: exit code %d", status.Name,
+				status.State.Terminated.Reason, status.State.Terminated.ExitCode))
+		}
+	}
+	if len(problems) == 0 && pod.Status.Phase == corev1.PodRunning {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("    pod %s: %s", pod.Name, pod.Status.Phase)}
+	for _, problem := range problems {
+		lines = append(lines, fmt.Sprintf("      %s", problem))
+	}
+	for _, event := range latestPodEvents(pod.Name, events) {
+		lines = append(lines, fmt.Sprintf("      event %s %s: %s", event.Type, event.Reason, event.Message))
+	}
+	return lines
+}
+
+// latestPodEvents returns the latest events of the pod, in the order they happened
+func latestPodEvents(podName string, events []corev1.Event) []corev1.Event {
+	podEvents := []corev1.Event{}
+	for _, event := range events {
+		if event.InvolvedObject.Kind == "Pod" && event.InvolvedObject.Name == podName {
+			podEvents = append(podEvents, event)
+		}
+	}
+	sort.SliceStable(podEvents, func(i, j int) bool {
+		return podEvents[i].LastTimestamp.Before(&podEvents[j].LastTimestamp)
+	})
+	if len(podEvents) > maxPodEvents {
+		podEvents = podEvents[len(podEvents)-maxPodEvents:]
+	}
+	return podEvents
+}
+
+// diagnoseKnativeCR reports the failing conditions of the Knative custom resource
+func diagnoseKnativeCR(ctx context.Context, operatorClient versioned.Interface, component, namespace string) []string {
+	var kind, name string
+	var conditions duckv1.Conditions
+	var err error
+	if component == common.ServingComponent {
+		kind, name = "KnativeServing", common.KnativeServingName
+		ks, getErr := operatorClient.OperatorV1beta1().KnativeServings(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			conditions = ks.Status.Conditions
+		}
+	} else {
+		kind, name = "KnativeEventing", common.KnativeEventingName
+		ke, getErr := operatorClient.OperatorV1beta1().KnativeEventings(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err = getErr; err == nil {
+			conditions = ke.Status.Conditions
+		}
+	}
+
+	if apierrs.IsNotFound(err) {
+		return []string{fmt.Sprintf("  %s '%s': missing", kind, name)}
+	} else if err != nil {
+		return []string{fmt.Sprintf("  %s '%s': cannot be retrieved: %v", kind, name, err)}
+	}
+	return describeConditions(kind, name, conditions)
+}
+
+// describeConditions reports the conditions of the Knative custom resource, which are not true
+func describeConditions(kind, name string, conditions duckv1.Conditions) []string {
+	lines := []string{}
+	for _, condition := range conditions {
+		if condition.IsTrue() {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %s '%s': condition %s is %s: %s: %s", kind, name,
+			condition.Type, condition.Status, condition.Reason, condition.Message))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("  %s '%s': no failing condition", kind, name))
+	}
+	return lines
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestDescribeDeployment(t *testing.T) {
+	replicas := int32(2)
+	deployment := &v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "activator",
+			Labels: map[string]string{"app.kubernetes.io/version": "1.7.0"},
+		},
+		Spec:   v1.DeploymentSpec{Replicas: &replicas},
+		Status: v1.DeploymentStatus{AvailableReplicas: 1},
+	}
+
+	for _, tt := range []struct {
+		name       string
+		deployment *v1.Deployment
+		expected   string
+	}{{
+		name:       "Missing deployment",
+		deployment: nil,
+		expected:   "  deployment activator: missing",
+	}, {
+		name:       "Found deployment",
+		deployment: deployment,
+		expected:   "  deployment activator: found, version 1.7.0 (expected 1.8.0), 1/2 replicas available",
+	}, {
+		name:       "Deployment without the version label",
+		deployment: &v1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "activator"}},
+		expected:   "  deployment activator: found, version none (expected 1.8.0), 0/1 replicas available",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			testingUtil.AssertEqual(t, describeDeployment("activator", tt.deployment, "1.8.0"), tt.expected)
+		})
+	}
+}
+
+func TestDescribePod(t *testing.T) {
+	now := time.Now()
+	event := func(pod, reason string, age time.Duration) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " message",
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+	events := []corev1.Event{
+		event("activator-1", "BackOff", time.Minute),
+		event("activator-1", "Failed", 2*time.Minute),
+		event("activator-2", "FailedScheduling", time.Minute),
+		event("activator-1", "Pulling", 4*time.Minute),
+		event("activator-1", "Scheduled", 5*time.Minute),
+	}
+
+	for _, tt := range []struct {
+		name     string
+		pod      *corev1.Pod
+		expected []string
+	}{{
+		name: "Running pod",
+		pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "activator-0"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		expected: nil,
+	}, {
+		name: "Pod with an image pull failure",
+		pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "activator-1"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "activator",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: "Back-off pulling image",
+					}},
+				}},
+			},
+		},
+		expected: []string{
+			"    pod activator-1: Pending",
+			"      container activator is waiting: ImagePullBackOff: Back-off pulling image",
+			"      event Warning Pulling: Pulling message",
+			"      event Warning Failed: Failed message",
+			"      event Warning BackOff: BackOff message",
+		},
+	}, {
+		name: "Unschedulable pod",
+		pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "activator-2"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: "0/3 nodes are available",
+				}},
+			},
+		},
+		expected: []string{
+			"    pod activator-2: Pending",
+			"      Unschedulable: 0/3 nodes are available",
+			"      event Warning FailedScheduling: FailedScheduling message",
+		},
+	}, {
+		name: "Crashing pod",
+		pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "activator-3"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "activator",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason: "CrashLoopBackOff",
+					}},
+				}},
+			},
+		},
+		expected: []string{
+			"    pod activator-3: Running",
+			"      container activator is waiting: CrashLoopBackOff: ",
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			testingUtil.AssertDeepEqual(t, describePod(tt.pod, events), tt.expected)
+		})
+	}
+}
+
+func TestDescribeConditions(t *testing.T) {
+	conditions := duckv1.Conditions{{
+		Type:   apis.ConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: "NotReady",
+	}, {
+		Type:    "DeploymentsAvailable",
+		Status:  corev1.ConditionFalse,
+		Reason:  "NotReady",
+		Message: "Waiting on deployments: activator",
+	}, {
+		Type:   "InstallSucceeded",
+		Status: corev1.ConditionTrue,
+	}}
+
+	testingUtil.AssertDeepEqual(t, describeConditions("KnativeServing", "knative-serving", conditions), []string{
+		"  KnativeServing 'knative-serving': condition Ready is False: NotReady: ",
+		"  KnativeServing 'knative-serving': condition DeploymentsAvailable is False: NotReady: Waiting on deployments: activator",
+	})
+	testingUtil.AssertDeepEqual(t, describeConditions("KnativeServing", "knative-serving", conditions[2:]), []string{
+		"  KnativeServing 'knative-serving': no failing condition",
+	})
+}
+
+func TestWithDiagnosticsKeepsOtherErrors(t *testing.T) {
+	err := errors.New("failed")
+	testingUtil.AssertEqual(t, withDiagnostics(context.Background(), err, nil, nil, &InstallFlags{}, nil), err)
+}
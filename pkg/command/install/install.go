@@ -29,10 +29,10 @@ import (
 	"golang.org/x/mod/semver"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+	"k8s.io/client-go/util/retry"
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
@@ -59,28 +59,28 @@ var operatorCRDsOverlay string
 //go:embed overlay/ks_ingress.yaml
 var servingWithIngressOverlay string
 
-type installCmdFlags struct {
+// InstallFlags are the options to install the Knative Operator or a Knative component
+type InstallFlags struct {
 	Component      string
 	IstioNamespace string
 	Namespace      string
-	KubeConfig     string
 	Version        string
 	Istio          bool
 	Kourier        bool
 	Contour        bool
+	// All installs both Knative Serving and Knative Eventing
+	All bool
+	// OperatorNamespace and OperatorVersion are used to install the Knative Operator, if it is missing
+	// for the Knative components
+	OperatorNamespace string
+	OperatorVersion   string
+	// Wait waits until the Knative component is ready
+	Wait bool
+	// Timeout is the maximum time to wait for the Knative component to be ready
+	Timeout time.Duration
 }
 
-var (
-	ServingKeyDeployments  = []string{"activator", "autoscaler", "autoscaler-hpa", "controller", "webhook"}
-	EventingKeyDeployments = []string{"eventing-controller", "eventing-webhook", "imc-controller", "imc-dispatcher",
-		"mt-broker-controller", "mt-broker-filter", "mt-broker-ingress", "pingsource-mt-adapter"}
-	// Interval specifies the time between two polls.
-	Interval = 10 * time.Second
-	// Timeout specifies the timeout for the function PollImmediate to reach a certain status.
-	Timeout = 5 * time.Minute
-)
-
-func (flags *installCmdFlags) fill_defaults() {
+func (flags *InstallFlags) fill_defaults() {
 	if flags.Version == "" {
 		flags.Version = common.Latest
 	}
@@ -107,66 +107,104 @@ func (flags *installCmdFlags) fill_defaults() {
 	}
 }
 
-var (
-	installFlags installCmdFlags
-)
-
 // NewInstallCommand represents the install commands for the operation
 func NewInstallCommand(p *pkg.OperatorParams) *cobra.Command {
+	var installFlags InstallFlags
 	var installCmd = &cobra.Command{
 		Use:   "install",
 		Short: "Install Knative Operator or Knative components",
 		Example: `
   # Install Knative Serving under the namespace knative-serving
-  kn-operator install -c serving --namespace knative-serving`,
+  kn-operator install -c serving --namespace knative-serving
+  # Install Knative Serving and Eventing in parallel, with the Knative Operator under the namespace knative-operator
+  kn-operator install -c serving,eventing --operator-namespace knative-operator
+  # Install Knative Eventing 1.8, with the Knative Operator 1.9 if the operator is missing
+  kn-operator install -c eventing --version 1.8.0 --operator-version 1.9.0`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Fill in the default values for the empty fields
-			err := RunInstallationCommand(&installFlags, p)
-			if err != nil {
-				return err
+			results, err := runInstallationCommand(&installFlags, p)
+			if results == nil && err == nil {
+				fmt.Fprintf(cmd.OutOrStdout(), "Knative Operator of the '%s' version was created in the namespace '%s'.\n",
+					installFlags.Version, installFlags.Namespace)
 			}
-
-			component := "Operator"
-			if strings.EqualFold(installFlags.Component, common.ServingComponent) {
-				component = common.ServingComponent
-			} else if strings.EqualFold(installFlags.Component, common.EventingComponent) {
-				component = common.EventingComponent
+			for _, result := range results {
+				if result.err == nil {
+					fmt.Fprintf(cmd.OutOrStdout(), "Knative %s of the '%s' version was created in the namespace '%s'.\n",
+						result.component, result.version, result.namespace)
+				}
 			}
-
-			fmt.Fprintf(cmd.OutOrStdout(), "Knative %s of the '%s' version was created in the namespace '%s'.\n",
-				component, installFlags.Version, installFlags.Namespace)
-			return nil
+			return err
 		},
 	}
 
-	installCmd.Flags().StringVar(&installFlags.KubeConfig, "kubeconfig", "", "The kubeconfig of the Knative resources (default is KUBECONFIG from environment variable)")
 	installCmd.Flags().StringVarP(&installFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
-	installCmd.Flags().StringVarP(&installFlags.Component, "component", "c", "", "The name of the Knative Component to install")
+	installCmd.Flags().StringVarP(&installFlags.Component, "component", "c", "", "The name of the Knative Component to install, or the comma-separated names to install several components")
+	installCmd.Flags().BoolVar(&installFlags.All, "all", false, "The flag to install both Knative Serving and Knative Eventing")
+	installCmd.Flags().StringVar(&installFlags.OperatorNamespace, "operator-namespace", "", "The namespace of the Knative Operator, if it needs to be installed for the Knative component (default \"default\")")
+	installCmd.Flags().StringVar(&installFlags.OperatorVersion, "operator-version", "", "The version of the Knative Operator, if it needs to be installed for the Knative component (default is the release compatible with the component version)")
 	installCmd.Flags().StringVarP(&installFlags.Version, "version", "v", common.Latest, "The version of the the Knative Operator or the Knative component")
 	installCmd.Flags().StringVar(&installFlags.IstioNamespace, "istio-namespace", "", "The namespace of istio")
 	installCmd.Flags().BoolVar(&installFlags.Istio, "istio", false, "The flag to enable the ingress istio")
 	installCmd.Flags().BoolVar(&installFlags.Kourier, "kourier", false, "The flag to enable the ingress kourier")
 	installCmd.Flags().BoolVar(&installFlags.Contour, "contour", false, "The flag to enable the ingress contour")
+	installCmd.Flags().BoolVar(&installFlags.Wait, "wait", true, "The flag to wait until the Knative component is ready")
+	installCmd.Flags().DurationVar(&installFlags.Timeout, "timeout", common.DefaultTimeout, "The maximum time to wait for the Knative component to be ready")
 
 	return installCmd
 }
 
-func RunInstallationCommand(installFlags *installCmdFlags, p *pkg.OperatorParams) error {
+// runInstallationCommand installs the Knative Operator or the Knative components, showing the progress
+// in the terminal
+func runInstallationCommand(installFlags *InstallFlags, p *pkg.OperatorParams) ([]componentResult, error) {
 	pi := progressindicator.New().SetText("Installing...")
 	pi.Start()
 	defer pi.Stop()
 
-	err := validateIngressFlags(installFlags)
+	return runInstallation(installFlags, p, func(text string) {
+		pi.SetText(text)
+	})
+}
+
+// Install installs the Knative Operator, or the Knative components if the components are set in the flags.
+// The default values are filled in the flags of the Knative Operator.
+func Install(installFlags *InstallFlags, p *pkg.OperatorParams) error {
+	_, err := runInstallation(installFlags, p, func(string) {})
+	return err
+}
+
+// runInstallation installs the Knative components set in the flags, or the Knative Operator if none is set.
+// The results are returned per component, and are nil for the Knative Operator.
+func runInstallation(installFlags *InstallFlags, p *pkg.OperatorParams, showProgress func(text string)) ([]componentResult, error) {
+	components, err := validateInstallation(installFlags)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if len(components) > 0 {
+		return runComponentsInstallation(installFlags, components, p, showProgress)
 	}
 
 	// Fill in the default values for the empty fields
 	installFlags.fill_defaults()
+	if exists, ns, _, err := checkIfOperatorInstalled(p); err != nil {
+		return nil, err
+	} else if exists {
+		// Check if the namespace is consistent
+		if !strings.EqualFold(ns, installFlags.Namespace) {
+			return nil, fmt.Errorf("The namespace %s you specified is not consistent with the existing namespace for Knative Operator %s",
+				installFlags.Namespace, ns)
+		}
+	}
 
-	p.KubeCfgPath = installFlags.KubeConfig
+	// Install the Knative Operator
+	text := fmt.Sprintf("Installing Knative Operator, Version %s...", installFlags.Version)
+	p.SetStep(text)
+	showProgress(text)
+	return nil, installOperator(installFlags, p)
+}
 
+// installComponent installs the Knative component of the flags with the default values filled, or migrates it
+// through the intermediate versions if it is already installed
+func installComponent(installFlags *InstallFlags, p *pkg.OperatorParams) error {
 	client, err := p.NewKubeClient()
 	if err != nil {
 		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
@@ -175,68 +213,75 @@ func RunInstallationCommand(installFlags *installCmdFlags, p *pkg.OperatorParams
 		Client: client,
 	}
 
-	if installFlags.Component != "" {
-		component := common.EventingComponent
-		if strings.EqualFold(installFlags.Component, common.ServingComponent) {
-			component = common.ServingComponent
-		}
+	component := common.EventingComponent
+	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
+		component = common.ServingComponent
+	}
 
-		currentVersion := ""
-		if exists, ns, version, err := deploy.CheckIfKnativeInstalled(installFlags.Component); err != nil {
-			return err
-		} else if exists {
-			// Check if the namespace is consistent
-			if !strings.EqualFold(ns, installFlags.Namespace) {
-				return fmt.Errorf("The namespace %s you specified is not consistent with the existing namespace for Knative Component %s",
-					installFlags.Namespace, ns)
-			}
-			currentVersion = version
+	currentVersion := ""
+	if exists, ns, version, err := deploy.CheckIfKnativeInstalled(p.Context(), installFlags.Component); err != nil {
+		return err
+	} else if exists {
+		// Check if the namespace is consistent
+		if !strings.EqualFold(ns, installFlags.Namespace) {
+			return fmt.Errorf("The namespace %s you specified is not consistent with the existing namespace for Knative Component %s",
+				installFlags.Namespace, ns)
 		}
-		// Install serving or eventing
-		versions, err := generateVersionStages(currentVersion, installFlags.Version)
-		if err != nil {
+		currentVersion = version
+
+		// Record the existing version and spec, so that the migration can be rolled back
+		if err = savePreviousState(installFlags.Component, installFlags.Namespace, p); err != nil {
 			return err
 		}
+	}
+	// Install serving or eventing
+	versions, err := generateVersionStages(currentVersion, installFlags.Version)
+	if err != nil {
+		return err
+	}
 
-		for _, v := range versions {
-			text := fmt.Sprintf("Installing Knative %s, Version %s...", component, v)
-			if currentVersion != "" {
-				text = fmt.Sprintf("Migrating Knative %s to Version %s...", component, v)
-			}
-			pi.SetText(text)
-
-			installFlags.Version = v
-			err = installKnativeComponent(installFlags, p)
-			if err != nil {
-				return err
-			}
+	for i, v := range versions {
+		text := fmt.Sprintf("Installing Knative %s, Version %s...", component, v)
+		if currentVersion != "" {
+			text = fmt.Sprintf("Migrating Knative %s to Version %s...", component, v)
 		}
+		p.SetStep(text)
 
-	} else {
-		if exists, ns, _, err := checkIfOperatorInstalled(p); err != nil {
+		// The intermediate versions have to be ready before migrating to the next version
+		stageFlags := *installFlags
+		stageFlags.Version = v
+		stageFlags.Wait = installFlags.Wait || i < len(versions)-1
+		if err = installKnativeComponent(&stageFlags, p); err != nil {
 			return err
-		} else if exists {
-			// Check if the namespace is consistent
-			if !strings.EqualFold(ns, installFlags.Namespace) {
-				return fmt.Errorf("The namespace %s you specified is not consistent with the existing namespace for Knative Operator %s",
-					installFlags.Namespace, ns)
-			}
 		}
+	}
+	return nil
+}
 
-		// Install the Knative Operator
-		text := fmt.Sprintf("Installing Knative Operator, Version %s...", installFlags.Version)
-		pi.SetText(text)
-		err = installOperator(installFlags, p)
-		if err != nil {
-			return err
-		}
+// validateInstallation validates the flags, and returns the Knative components to install
+func validateInstallation(installFlags *InstallFlags) ([]string, error) {
+	components, err := parseComponents(installFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateOperatorFlags(installFlags, components); err != nil {
+		return nil, err
 	}
+	if err = validateIngressFlags(installFlags); err != nil {
+		return nil, err
+	}
+	return components, nil
+}
 
-	pi.Stop()
-	return nil
+// InstallOperator installs the Knative Operator of a certain version under a certain namespace
+func InstallOperator(namespace, version string, p *pkg.OperatorParams) error {
+	return Install(&InstallFlags{
+		Namespace: namespace,
+		Version:   version,
+	}, p)
 }
 
-func validateIngressFlags(installFlags *installCmdFlags) error {
+func validateIngressFlags(installFlags *InstallFlags) error {
 	count := 0
 
 	if installFlags.Istio {
@@ -251,7 +296,8 @@ func validateIngressFlags(installFlags *installCmdFlags) error {
 		count++
 	}
 
-	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
+	components, _ := parseComponents(installFlags)
+	if common.Contains(components, common.ServingComponent) {
 		if count > 1 {
 			return fmt.Errorf("You can specify only one ingress for Knative Serving.")
 		}
@@ -292,7 +338,7 @@ func getOperatorURL(version string) (string, error) {
 	return getBaseURL(version, "operator.yaml")
 }
 
-func getOverlayYamlContent(installFlags *installCmdFlags) string {
+func getOverlayYamlContent(installFlags *InstallFlags) string {
 	overlayContent := ""
 	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
 		if installFlags.Istio {
@@ -328,7 +374,7 @@ func versionWebhook(version string) bool {
 	return semver.Compare(targetVersion, "v1.3") >= 0
 }
 
-func getYamlValuesContent(installFlags *installCmdFlags) string {
+func getYamlValuesContent(installFlags *InstallFlags) string {
 	content := ""
 	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
 		content = fmt.Sprintf("#@data/values\n---\nname: %s\nnamespace: %s\nversion: '%s'",
@@ -364,6 +410,7 @@ func getYamlValuesContent(installFlags *installCmdFlags) string {
 }
 
 func checkIfOperatorInstalled(p *pkg.OperatorParams) (bool, string, string, error) {
+	ctx := p.Context()
 	client, err := p.NewKubeClient()
 	if err != nil {
 		return false, "", "", fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
@@ -371,25 +418,17 @@ func checkIfOperatorInstalled(p *pkg.OperatorParams) (bool, string, string, erro
 	deploy := common.Deployment{
 		Client: client,
 	}
-	return deploy.CheckIfOperatorInstalled()
+	return deploy.CheckIfOperatorInstalled(ctx)
 }
 
-func installKnativeComponent(installFlags *installCmdFlags, p *pkg.OperatorParams) error {
+func installKnativeComponent(installFlags *InstallFlags, p *pkg.OperatorParams) error {
 	// Check if the knative operator is installed
-	if exists, _, _, err := checkIfOperatorInstalled(p); err != nil {
+	err := installOperatorIfMissing(installFlags, p, p.SetStep)
+	if err != nil {
 		return err
-	} else if !exists {
-		operatorInstallFlags := installCmdFlags{
-			Namespace: "default",
-			Version:   common.Latest,
-		}
-		err = installOperator(&operatorInstallFlags, p)
-		if err != nil {
-			return err
-		}
 	}
 
-	err := createNamspaceIfNecessary(installFlags.Namespace, p)
+	err = createNamspaceIfNecessary(installFlags.Namespace, p)
 	if err != nil {
 		return err
 	}
@@ -405,16 +444,19 @@ func installKnativeComponent(installFlags *installCmdFlags, p *pkg.OperatorParam
 		return err
 	}
 
-	// Make sure all the deployment resources are up and running
-	err = ensureKnativeComponentReady(installFlags, p)
-	if err != nil {
-		return err
+	if !installFlags.Wait {
+		return nil
 	}
-
-	return nil
+	// Make sure all the deployment resources are up and running
+	return ensureKnativeComponentReady(installFlags, p)
 }
 
-func ensureKnativeComponentReady(installFlags *installCmdFlags, p *pkg.OperatorParams) error {
+func ensureKnativeComponentReady(installFlags *InstallFlags, p *pkg.OperatorParams) error {
+	ctx := p.Context()
+	timeout := installFlags.Timeout
+	if timeout == 0 {
+		timeout = common.DefaultTimeout
+	}
 	client, err := p.NewKubeClient()
 	if err != nil {
 		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
@@ -425,55 +467,87 @@ func ensureKnativeComponentReady(installFlags *installCmdFlags, p *pkg.OperatorP
 		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
 	}
 
+	component := "Eventing"
 	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
-		err := WaitForKnativeDeploymentState(client, installFlags.Namespace, installFlags.Version, ServingKeyDeployments,
-			IsKnativeDeploymentReady)
-		if err != nil {
-			return err
-		}
-		_, err = WaitForKnativeServingState(operatorClient.OperatorV1beta1().KnativeServings(installFlags.Namespace), common.KnativeServingName,
-			installFlags.Version, IsKnativeServingReady)
-
-		if err != nil {
-			return err
-		}
-	} else if strings.EqualFold(installFlags.Component, common.EventingComponent) {
-		err := WaitForKnativeDeploymentState(client, installFlags.Namespace, installFlags.Version, EventingKeyDeployments,
-			IsKnativeDeploymentReady)
-		if err != nil {
-			return err
-		}
-		_, err = WaitForKnativeEventingState(operatorClient.OperatorV1beta1().KnativeEventings(installFlags.Namespace), common.KnativeEventingName,
-			installFlags.Version, IsKnativeEventingReady)
+		component = "Serving"
+	}
+	p.SetStep(fmt.Sprintf("Waiting for Knative %s, Version %s to be ready...", component, installFlags.Version))
 
-		if err != nil {
-			return err
-		}
+	// The custom resource reports the version, once the Knative Operator has applied all the manifests of the release
+	if strings.EqualFold(installFlags.Component, common.ServingComponent) {
+		_, err = WaitForKnativeServingState(ctx, operatorClient.OperatorV1beta1().KnativeServings(installFlags.Namespace), common.KnativeServingName,
+			installFlags.Version, timeout, IsKnativeServingReady)
+	} else {
+		_, err = WaitForKnativeEventingState(ctx, operatorClient.OperatorV1beta1().KnativeEventings(installFlags.Namespace), common.KnativeEventingName,
+			installFlags.Version, timeout, IsKnativeEventingReady)
+	}
+	if err != nil {
+		deployments, _ := common.DiscoverDeployments(ctx, client, installFlags.Component, installFlags.Namespace)
+		return withDiagnostics(ctx, err, client, operatorClient, installFlags, deployments.Names())
 	}
 
+	// The deployments of the release, including the ones of the ingresses and the eventing sources, are found
+	// by the labels of the component
+	deployments, err := common.DiscoverDeployments(ctx, client, installFlags.Component, installFlags.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(deployments) == 0 {
+		return fmt.Errorf("No deployment of Knative %s is found in the namespace '%s'.", component, installFlags.Namespace)
+	}
+	err = WaitForKnativeDeploymentState(ctx, client, installFlags.Namespace, common.ComponentSelector(installFlags.Component),
+		installFlags.Version, deployments.Names(), timeout, IsKnativeDeploymentAvailable)
+	if err != nil {
+		return withDiagnostics(ctx, err, client, operatorClient, installFlags, deployments.Names())
+	}
 	return nil
 }
 
-func installOperator(installFlags *installCmdFlags, p *pkg.OperatorParams) error {
+func installOperator(installFlags *InstallFlags, p *pkg.OperatorParams) error {
 	err := createNamspaceIfNecessary(installFlags.Namespace, p)
 	if err != nil {
 		return err
 	}
 
-	URL, err := getOperatorURL(installFlags.Version)
+	yamlTemplateString, err := downloadOperatorTemplate(installFlags.Version)
 	if err != nil {
 		return err
 	}
 
-	postInstallURL, err := getPostInstallURL(installFlags.Version)
+	return applyOverlayValuesOnTemplate(yamlTemplateString, installFlags, p)
+}
+
+// UninstallOperator deletes all the resources of the Knative Operator of a certain version, installed under
+// a certain namespace, in the reverse order of the installation.
+func UninstallOperator(namespace, version string, p *pkg.OperatorParams) error {
+	operatorFlags := &InstallFlags{
+		Namespace: namespace,
+		Version:   version,
+	}
+	yamlTemplateString, err := downloadOperatorTemplate(version)
 	if err != nil {
 		return err
 	}
 
+	return common.DeleteManifests(yamlTemplateString, getOverlayYamlContent(operatorFlags),
+		getYamlValuesContent(operatorFlags), p)
+}
+
+func downloadOperatorTemplate(version string) (string, error) {
+	URL, err := getOperatorURL(version)
+	if err != nil {
+		return "", err
+	}
+
+	postInstallURL, err := getPostInstallURL(version)
+	if err != nil {
+		return "", err
+	}
+
 	// Generate the CR template by downloading the operator yaml
 	yamlTemplateString, err := common.DownloadFile(URL)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	yamlTemplateStringPostInstall, err := common.DownloadFile(postInstallURL)
@@ -481,11 +555,11 @@ func installOperator(installFlags *installCmdFlags, p *pkg.OperatorParams) error
 		// If operator-post-install.yaml exists, append the content to the template content
 		yamlTemplateString = fmt.Sprintf("%s\n%s", yamlTemplateString, yamlTemplateStringPostInstall)
 	}
-
-	return applyOverlayValuesOnTemplate(yamlTemplateString, installFlags, p)
+	return yamlTemplateString, nil
 }
 
 func createNamspaceIfNecessary(namespace string, p *pkg.OperatorParams) error {
+	ctx := p.Context()
 	client, err := p.NewKubeClient()
 	if err != nil {
 		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
@@ -495,13 +569,13 @@ func createNamspaceIfNecessary(namespace string, p *pkg.OperatorParams) error {
 		Client:    client,
 		Component: namespace,
 	}
-	if err = ns.CreateNamespace(namespace); err != nil {
+	if err = ns.CreateNamespace(ctx, namespace); err != nil {
 		return err
 	}
 	return nil
 }
 
-func applyOverlayValuesOnTemplate(yamlTemplateString string, installFlags *installCmdFlags, p *pkg.OperatorParams) error {
+func applyOverlayValuesOnTemplate(yamlTemplateString string, installFlags *InstallFlags, p *pkg.OperatorParams) error {
 	overlayContent := getOverlayYamlContent(installFlags)
 	yamlValuesContent := getYamlValuesContent(installFlags)
 
@@ -511,6 +585,18 @@ func applyOverlayValuesOnTemplate(yamlTemplateString string, installFlags *insta
 	return nil
 }
 
+func savePreviousState(component, namespace string, p *pkg.OperatorParams) error {
+	ctx := p.Context()
+	ksCR, err := common.GetKnativeOperatorCR(p)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return ksCR.SavePreviousState(ctx, component, namespace)
+	})
+}
+
 func generateVersionStages(source, target string) ([]string, error) {
 	stringArray := ""
 
@@ -590,110 +676,93 @@ func generateVersionStages(source, target string) ([]string, error) {
 	}
 }
 
-// WaitForKnativeDeploymentState polls the status of the Knative deployments every `interval`
-// until `inState` returns `true` indicating the deployments match the desired deployments.
-func WaitForKnativeDeploymentState(client kubernetes.Interface, namespace string, version string, expectedDeployments []string,
-	inState func(deps *v1.DeploymentList, expectedDeployments []string, version string, err error) (bool, error)) error {
-	span := logging.GetEmitableSpan(context.Background(), fmt.Sprintf("WaitForKnativeDeploymentState/%s/%s", expectedDeployments, "KnativeDeploymentIsReady"))
+// WaitForKnativeDeploymentState watches the Knative deployments matching the label selector until `inState` returns
+// `true` indicating the deployments match the desired deployments, or the timeout expires.
+func WaitForKnativeDeploymentState(ctx context.Context, client kubernetes.Interface, namespace, selector string, version string, expectedDeployments []string,
+	timeout time.Duration, inState func(deps *v1.DeploymentList, expectedDeployments []string, version string, err error) (bool, error)) error {
+	span := logging.GetEmitableSpan(ctx, fmt.Sprintf("WaitForKnativeDeploymentState/%s/%s", expectedDeployments, "KnativeDeploymentIsReady"))
 	defer span.End()
 
-	waitErr := wait.PollImmediate(Interval, Timeout, func() (bool, error) {
-		dpList, err := client.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
-		return inState(dpList, expectedDeployments, version, err)
+	lw := common.DeploymentListWatch(client, namespace, selector)
+	return common.WatchUntil(ctx, timeout, lw, func(objects map[string]runtime.Object) (bool, error) {
+		dpList := &v1.DeploymentList{}
+		for _, obj := range objects {
+			if deployment, ok := obj.(*v1.Deployment); ok {
+				dpList.Items = append(dpList.Items, *deployment)
+			}
+		}
+		return inState(dpList, expectedDeployments, version, nil)
 	})
-
-	return waitErr
 }
 
-// IsKnativeDeploymentReady will check the status conditions of the deployments and return true if the deployments meet the desired status.
-func IsKnativeDeploymentReady(dpList *v1.DeploymentList, expectedDeployments []string, version string, err error) (bool, error) {
+// deploymentVersionLabels are the labels, which carry the version of the Knative component of a deployment
+var deploymentVersionLabels = []string{"app.kubernetes.io/version", "serving.knative.dev/release", "eventing.knative.dev/release"}
+
+// IsKnativeDeploymentAvailable checks if all the expected deployments are available at the version of the Knative
+// component. The deployments are not ready, if none is expected.
+func IsKnativeDeploymentAvailable(dpList *v1.DeploymentList, expectedDeployments []string, version string, err error) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-
-	findDeployment := func(name string, deployments []v1.Deployment) *v1.Deployment {
-		for _, deployment := range deployments {
-			if deployment.Name == name {
-				return &deployment
-			}
-		}
-		return nil
+	if len(expectedDeployments) == 0 {
+		return false, nil
 	}
 
-	isStatusReady := func(status v1.DeploymentStatus) bool {
-		for _, c := range status.Conditions {
-			if c.Type == v1.DeploymentAvailable && c.Status == corev1.ConditionTrue {
-				return true
+	for _, name := range expectedDeployments {
+		found := false
+		for i := range dpList.Items {
+			if deployment := &dpList.Items[i]; deployment.Name == name {
+				found = isDeploymentAvailable(deployment.Status) && hasDeploymentVersion(deployment, version)
+				break
 			}
 		}
-		return false
+		if !found {
+			return false, nil
+		}
 	}
+	return true, nil
+}
 
-	isReady := func(d *v1.Deployment) bool {
-		for key, val := range d.GetObjectMeta().GetLabels() {
-			// Check if the version matches. As long as we find a value equals to the version, we can determine
-			// the deployment is for the specific version. The key "networking.knative.dev/ingress-provider" is
-			// used to indicate the network ingress resource.
-			// Currently, the network ingress resource is still specified together with the knative serving.
-			// It is possible that network ingress resource is not using the same version as knative serving.
-			// This is the reason why we skip the version checking for network ingress resource.
-
-			// The parameter version means the target version of Knative component to be installed.
-			// The parameter existingVersion means the installed version of Knative component. It is set to empty, if
-			// there is no Knative installation.
-
-			// If the deployment resource is for ingress, we will check the status of the deployment.
-			if key == "networking.knative.dev/ingress-provider" {
-				return isStatusReady(d.Status)
-			}
-
-			if key == "app.kubernetes.io/version" || key == "serving.knative.dev/release" || key == "eventing.knative.dev/release" {
-				if val == fmt.Sprintf("v%s", version) || val == version {
-					// When on of the following conditions is met:
-					// * spec.version is set to latest, but operator returns an actual semantic version
-					// * spec.version is set to a valid semantic version
-					// we need to verify the value of the key serving.knative.dev/release or eventing.knative.dev/release
-					// matches the version.
-					return isStatusReady(d.Status)
-				}
-			}
-
-			// If spec.version is set to latest and operator bundles a directory called latest, it is possible that both
-			// the version and the existing version are latest. In this case, the knative component to be installed is the
-			// same as the existing one, and we will check the status of the deployment.
-			if version == common.Latest {
-				return isStatusReady(d.Status)
-			}
+// hasDeploymentVersion checks if the labels of the deployment carry the version. The version latest or nightly is not
+// checked, neither are the deployments of the network ingress, which are not released together with Knative Serving.
+func hasDeploymentVersion(deployment *v1.Deployment, version string) bool {
+	labels := deployment.GetLabels()
+	if version == common.Latest || version == common.Nightly || labels["networking.knative.dev/ingress-provider"] != "" {
+		return true
+	}
+	for _, key := range deploymentVersionLabels {
+		if value := labels[key]; value != "" && (value == version || value == "v"+version) {
+			return true
 		}
-		return false
 	}
+	return false
+}
 
-	for _, name := range expectedDeployments {
-		dep := findDeployment(name, dpList.Items)
-		if dep == nil {
-			return false, nil
-		}
-		if !isReady(dep) {
-			return false, nil
+func isDeploymentAvailable(status v1.DeploymentStatus) bool {
+	for _, c := range status.Conditions {
+		if c.Type == v1.DeploymentAvailable && c.Status == corev1.ConditionTrue {
+			return true
 		}
 	}
-
-	return true, nil
+	return false
 }
 
-// WaitForKnativeServingState polls the status of the KnativeServing called name
-// from client every `interval` until `inState` returns `true` indicating it
-// is done, returns an error or timeout.
-func WaitForKnativeServingState(clients operatorv1beta1.KnativeServingInterface, name string, version string,
-	inState func(s *v1beta1.KnativeServing, version string, err error) (bool, error)) (*v1beta1.KnativeServing, error) {
-	span := logging.GetEmitableSpan(context.Background(), fmt.Sprintf("WaitForKnativeServingState/%s/%s", name, "KnativeServingIsReady"))
+// WaitForKnativeServingState watches the KnativeServing called name until `inState` returns `true`
+// indicating it is done, returns an error or the timeout expires.
+func WaitForKnativeServingState(ctx context.Context, clients operatorv1beta1.KnativeServingInterface, name string, version string,
+	timeout time.Duration, inState func(s *v1beta1.KnativeServing, version string, err error) (bool, error)) (*v1beta1.KnativeServing, error) {
+	span := logging.GetEmitableSpan(ctx, fmt.Sprintf("WaitForKnativeServingState/%s/%s", name, "KnativeServingIsReady"))
 	defer span.End()
 
 	var lastState *v1beta1.KnativeServing
-	waitErr := wait.PollImmediate(Interval, Timeout, func() (bool, error) {
-		state, err := clients.Get(context.TODO(), name, metav1.GetOptions{})
+	lw := common.KnativeServingListWatch(clients, name)
+	waitErr := common.WatchUntil(ctx, timeout, lw, func(objects map[string]runtime.Object) (bool, error) {
+		state, ok := objects[name].(*v1beta1.KnativeServing)
+		if !ok {
+			return false, nil
+		}
 		lastState = state
-		return inState(lastState, version, err)
+		return inState(lastState, version, nil)
 	})
 
 	if waitErr != nil {
@@ -710,19 +779,22 @@ func IsKnativeServingReady(s *v1beta1.KnativeServing, version string, err error)
 	return s.Status.IsReady() && version == s.Status.Version, err
 }
 
-// WaitForKnativeEventingState polls the status of the KnativeEventing called name
-// from client every `interval` until `inState` returns `true` indicating it
-// is done, returns an error or timeout.
-func WaitForKnativeEventingState(clients operatorv1beta1.KnativeEventingInterface, name string, version string,
-	inState func(s *v1beta1.KnativeEventing, version string, err error) (bool, error)) (*v1beta1.KnativeEventing, error) {
-	span := logging.GetEmitableSpan(context.Background(), fmt.Sprintf("WaitForKnativeEventingState/%s/%s", name, "KnativeEventingIsReady"))
+// WaitForKnativeEventingState watches the KnativeEventing called name until `inState` returns `true`
+// indicating it is done, returns an error or the timeout expires.
+func WaitForKnativeEventingState(ctx context.Context, clients operatorv1beta1.KnativeEventingInterface, name string, version string,
+	timeout time.Duration, inState func(s *v1beta1.KnativeEventing, version string, err error) (bool, error)) (*v1beta1.KnativeEventing, error) {
+	span := logging.GetEmitableSpan(ctx, fmt.Sprintf("WaitForKnativeEventingState/%s/%s", name, "KnativeEventingIsReady"))
 	defer span.End()
 
 	var lastState *v1beta1.KnativeEventing
-	waitErr := wait.PollImmediate(Interval, Timeout, func() (bool, error) {
-		state, err := clients.Get(context.TODO(), name, metav1.GetOptions{})
+	lw := common.KnativeEventingListWatch(clients, name)
+	waitErr := common.WatchUntil(ctx, timeout, lw, func(objects map[string]runtime.Object) (bool, error) {
+		state, ok := objects[name].(*v1beta1.KnativeEventing)
+		if !ok {
+			return false, nil
+		}
 		lastState = state
-		return inState(lastState, version, err)
+		return inState(lastState, version, nil)
 	})
 
 	if waitErr != nil {
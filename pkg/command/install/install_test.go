@@ -20,6 +20,10 @@ import (
 	"fmt"
 	"testing"
 
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
 )
@@ -80,21 +84,21 @@ func TestGetOperatorURLInvalidVersion(t *testing.T) {
 func TestFillDefaultsForInstallCmdFlags(t *testing.T) {
 	for _, tt := range []struct {
 		name          string
-		inputFlags    installCmdFlags
-		expectedFlags installCmdFlags
+		inputFlags    InstallFlags
+		expectedFlags InstallFlags
 	}{{
 		name:       "Empty namespace and version for operator",
-		inputFlags: installCmdFlags{},
-		expectedFlags: installCmdFlags{
+		inputFlags: InstallFlags{},
+		expectedFlags: InstallFlags{
 			Namespace: common.DefaultNamespace,
 			Version:   common.Latest,
 		},
 	}, {
 		name: "Empty istio namespace, namespace and version for serving",
-		inputFlags: installCmdFlags{
+		inputFlags: InstallFlags{
 			Component: "serving",
 		},
-		expectedFlags: installCmdFlags{
+		expectedFlags: InstallFlags{
 			Component:      "serving",
 			IstioNamespace: common.DefaultIstioNamespace,
 			Namespace:      common.DefaultKnativeServingNamespace,
@@ -103,10 +107,10 @@ func TestFillDefaultsForInstallCmdFlags(t *testing.T) {
 		},
 	}, {
 		name: "Empty namespace and version for eventing",
-		inputFlags: installCmdFlags{
+		inputFlags: InstallFlags{
 			Component: "eventing",
 		},
-		expectedFlags: installCmdFlags{
+		expectedFlags: InstallFlags{
 			Component: "eventing",
 			Namespace: common.DefaultKnativeEventingNamespace,
 			Version:   common.Latest,
@@ -122,50 +126,50 @@ func TestFillDefaultsForInstallCmdFlags(t *testing.T) {
 func TestGetOverlayYamlContent(t *testing.T) {
 	for _, tt := range []struct {
 		name         string
-		installFlags installCmdFlags
+		installFlags InstallFlags
 		expectedFile string
 	}{{
 		name: "Knative Serving",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Component: "serving",
 		},
 		expectedFile: "testdata/overlay/ks.yaml",
 	}, {
 		name: "Knative Serving with Kourier",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Component: "serving",
 			Kourier:   true,
 		},
 		expectedFile: "testdata/overlay/ks_ingress.yaml",
 	}, {
 		name: "Knative Serving with Istio",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Component: "serving",
 			Istio:     true,
 		},
 		expectedFile: "testdata/overlay/ks.yaml",
 	}, {
 		name: "Knative Serving with istio namespace",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Component:      "serving",
 			IstioNamespace: "test",
 		},
 		expectedFile: "testdata/overlay/ks_istio_ns.yaml",
 	}, {
 		name: "Knative Eventing",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Component: "eventing",
 		},
 		expectedFile: "testdata/overlay/ke.yaml",
 	}, {
 		name: "Knative Operator",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version: "1.2.0",
 		},
 		expectedFile: "testdata/overlay/operator.yaml",
 	}, {
 		name: "Knative Operator of 1.3",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version: "1.3.0",
 		},
 		expectedFile: "testdata/overlay/full_operator.yaml",
@@ -183,11 +187,11 @@ func TestGetOverlayYamlContent(t *testing.T) {
 func TestGetYamlValuesContent(t *testing.T) {
 	for _, tt := range []struct {
 		name           string
-		installFlags   installCmdFlags
+		installFlags   InstallFlags
 		expectedResult string
 	}{{
 		name: "Knative Serving with all parameters",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace:      "test-serving",
 			Component:      "serving",
 			Version:        "1.0",
@@ -201,7 +205,7 @@ version: '1.0'
 local_gateway_value: knative-local-gateway.istio-namespace.svc.cluster.local`,
 	}, {
 		name: "Knative Serving with namespace and version",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace: "test-serving-1",
 			Component: "serving",
 			Version:   "1.0.0",
@@ -213,7 +217,7 @@ namespace: test-serving-1
 version: '1.0.0'`,
 	}, {
 		name: "Knative Serving with namespace only",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace: "test-serving-1",
 			Component: "serving",
 		},
@@ -224,7 +228,7 @@ namespace: test-serving-1
 version: 'latest'`,
 	}, {
 		name: "Knative Serving with version only",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version:   "1.0",
 			Component: "serving",
 		},
@@ -235,7 +239,7 @@ namespace: knative-serving
 version: '1.0'`,
 	}, {
 		name: "Knative Serving with ingress and version",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version:   "1.0",
 			Component: "serving",
 			Kourier:   true,
@@ -251,7 +255,7 @@ contour: false
 ingressClass: kourier.ingress.networking.knative.dev`,
 	}, {
 		name: "Knative Serving with istio and version",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version:   "1.0",
 			Component: "serving",
 			Istio:     true,
@@ -263,7 +267,7 @@ namespace: knative-serving
 version: '1.0'`,
 	}, {
 		name: "Knative Eventing with namespace and version",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace: "test-eventing",
 			Component: "eventing",
 			Version:   "1.0.0",
@@ -275,7 +279,7 @@ namespace: test-eventing
 version: '1.0.0'`,
 	}, {
 		name: "Knative Eventing with namespace only",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace: "test-eventing-1",
 			Component: "eventing",
 		},
@@ -286,7 +290,7 @@ namespace: test-eventing-1
 version: 'latest'`,
 	}, {
 		name: "Knative Eventing with version only",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version:   "1.0",
 			Component: "eventing",
 		},
@@ -297,14 +301,14 @@ namespace: knative-eventing
 version: '1.0'`,
 	}, {
 		name: "Knative unknown component",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace: "1.0",
 			Component: "unknown",
 		},
 		expectedResult: "",
 	}, {
 		name: "Knative Operator",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Version: "1.0",
 		},
 		expectedResult: `#@data/values
@@ -312,7 +316,7 @@ version: '1.0'`,
 namespace: default`,
 	}, {
 		name: "Knative Operator with a namespace",
-		installFlags: installCmdFlags{
+		installFlags: InstallFlags{
 			Namespace: "test",
 		},
 		expectedResult: `#@data/values
@@ -456,3 +460,66 @@ func TestGenerateVersionStages(t *testing.T) {
 		})
 	}
 }
+
+func TestIsKnativeDeploymentAvailable(t *testing.T) {
+	available := v1.DeploymentStatus{Conditions: []v1.DeploymentCondition{{
+		Type:   v1.DeploymentAvailable,
+		Status: corev1.ConditionTrue,
+	}}}
+	dpList := &v1.DeploymentList{Items: []v1.Deployment{{
+		ObjectMeta: metav1.ObjectMeta{Name: "activator", Labels: map[string]string{"app.kubernetes.io/version": "1.8.0"}},
+		Status:     available,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Labels: map[string]string{"serving.knative.dev/release": "v1.8.0"}},
+		Status:     available,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "net-kourier-controller",
+			Labels: map[string]string{"networking.knative.dev/ingress-provider": "kourier"}},
+		Status: available,
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "controller", Labels: map[string]string{"app.kubernetes.io/version": "1.8.0"}},
+	}}}
+
+	for _, tt := range []struct {
+		name                string
+		expectedDeployments []string
+		version             string
+		expectedResult      bool
+	}{{
+		name:                "Available at the version",
+		expectedDeployments: []string{"activator", "webhook", "net-kourier-controller"},
+		version:             "1.8.0",
+		expectedResult:      true,
+	}, {
+		name:                "Available at the previous version",
+		expectedDeployments: []string{"activator", "webhook"},
+		version:             "1.9.0",
+		expectedResult:      false,
+	}, {
+		name:                "Available at any version",
+		expectedDeployments: []string{"activator", "webhook"},
+		version:             common.Latest,
+		expectedResult:      true,
+	}, {
+		name:                "Not available",
+		expectedDeployments: []string{"activator", "controller"},
+		version:             "1.8.0",
+		expectedResult:      false,
+	}, {
+		name:                "Missing",
+		expectedDeployments: []string{"activator", "autoscaler"},
+		version:             "1.8.0",
+		expectedResult:      false,
+	}, {
+		name:                "None expected",
+		expectedDeployments: nil,
+		version:             "1.8.0",
+		expectedResult:      false,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, err := IsKnativeDeploymentAvailable(dpList, tt.expectedDeployments, tt.version, nil)
+			testingUtil.AssertEqual(t, err, nil)
+			testingUtil.AssertEqual(t, ready, tt.expectedResult)
+		})
+	}
+}
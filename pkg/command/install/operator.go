@@ -0,0 +1,166 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/apps/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+)
+
+// OperatorPollInterval specifies the time between two checks, when waiting for the Knative Operator to be ready
+var OperatorPollInterval = 2 * time.Second
+
+// operatorCRDs are the custom resource definitions, which the Knative components are created with
+var operatorCRDs = []string{"knativeservings.operator.knative.dev", "knativeeventings.operator.knative.dev"}
+
+var crdResource = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// WaitForOperatorReady waits until the Knative Operator under the namespace accepts the Knative custom resources:
+// its CRDs are established, its deployment is rolled out, and the conversion webhook of the CRDs has ready endpoints.
+func WaitForOperatorReady(namespace string, timeout time.Duration, p *pkg.OperatorParams) error {
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	restConfig, err := p.RestConfig()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	p.SetStep(fmt.Sprintf("Waiting for the Knative Operator in the namespace '%s' to be ready...", namespace))
+	var pending string
+	waitErr := wait.PollUntilContextTimeout(p.Context(), OperatorPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		pending, err = pendingOperatorResource(ctx, kubeClient, dynamicClient, namespace)
+		return pending == "", err
+	})
+	if wait.Interrupted(waitErr) && p.Context().Err() == nil {
+		return fmt.Errorf("The Knative Operator in the namespace '%s' is not ready within %s: %s", namespace, timeout, pending)
+	}
+	return waitErr
+}
+
+// pendingOperatorResource describes the first resource of the Knative Operator, which is not ready yet. It returns
+// an empty string, if the Knative Operator is ready.
+func pendingOperatorResource(ctx context.Context, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
+	namespace string) (string, error) {
+	deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(ctx, common.KnativeOperatorName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		return fmt.Sprintf("the deployment %s is missing", common.KnativeOperatorName), nil
+	} else if err != nil {
+		return "", err
+	}
+	if !isDeploymentRolledOut(deployment) {
+		return fmt.Sprintf("the deployment %s is not rolled out", common.KnativeOperatorName), nil
+	}
+
+	for _, name := range operatorCRDs {
+		crd, err := dynamicClient.Resource(crdResource).Get(ctx, name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			return fmt.Sprintf("the CRD %s is missing", name), nil
+		} else if err != nil {
+			return "", err
+		}
+		if !isCRDEstablished(crd) {
+			return fmt.Sprintf("the CRD %s is not established", name), nil
+		}
+
+		serviceNamespace, serviceName, ok := conversionWebhookService(crd)
+		if !ok {
+			continue
+		}
+		slices, err := kubeClient.DiscoveryV1().EndpointSlices(serviceNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: discoveryv1.LabelServiceName + "=" + serviceName,
+		})
+		if err != nil {
+			return "", err
+		}
+		if !hasReadyEndpoint(slices.Items) {
+			return fmt.Sprintf("the conversion webhook %s/%s of the CRD %s has no ready endpoint", serviceNamespace, serviceName, name), nil
+		}
+	}
+	return "", nil
+}
+
+// isDeploymentRolledOut checks if all the replicas of the deployment run its latest spec and are available
+func isDeploymentRolledOut(deployment *v1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation && status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas == replicas && status.Replicas == replicas
+}
+
+// isCRDEstablished checks the condition Established of the custom resource definition
+func isCRDEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if ok && condition["type"] == "Established" && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// conversionWebhookService returns the namespace and the name of the service of the conversion webhook, if the
+// custom resource definition converts the versions with a webhook
+func conversionWebhookService(crd *unstructured.Unstructured) (string, string, bool) {
+	strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+	if !strings.EqualFold(strategy, "Webhook") {
+		return "", "", false
+	}
+	service, found, _ := unstructured.NestedStringMap(crd.Object, "spec", "conversion", "webhook", "clientConfig", "service")
+	if !found || service["name"] == "" {
+		return "", "", false
+	}
+	return service["namespace"], service["name"], true
+}
+
+// hasReadyEndpoint checks if any endpoint in the slices is ready to receive the requests
+func hasReadyEndpoint(slices []discoveryv1.EndpointSlice) bool {
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				return true
+			}
+		}
+	}
+	return false
+}
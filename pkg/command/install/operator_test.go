@@ -0,0 +1,126 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/apps/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestIsDeploymentRolledOut(t *testing.T) {
+	replicas := int32(2)
+	for _, tt := range []struct {
+		name           string
+		generation     int64
+		status         v1.DeploymentStatus
+		expectedResult bool
+	}{{
+		name:       "All the replicas are updated and available",
+		generation: 2,
+		status: v1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2,
+			AvailableReplicas: 2},
+		expectedResult: true,
+	}, {
+		name:       "The latest spec is not observed",
+		generation: 3,
+		status: v1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2,
+			AvailableReplicas: 2},
+		expectedResult: false,
+	}, {
+		name:       "The old replica is still running",
+		generation: 2,
+		status: v1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2,
+			AvailableReplicas: 3},
+		expectedResult: false,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &v1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+				Spec:       v1.DeploymentSpec{Replicas: &replicas},
+				Status:     tt.status,
+			}
+			testingUtil.AssertEqual(t, isDeploymentRolledOut(deployment), tt.expectedResult)
+		})
+	}
+}
+
+func TestIsCRDEstablished(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": "False"},
+			},
+		},
+	}}
+	testingUtil.AssertEqual(t, isCRDEstablished(crd), false)
+
+	crd.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Established", "status": "True"},
+		},
+	}
+	testingUtil.AssertEqual(t, isCRDEstablished(crd), true)
+	testingUtil.AssertEqual(t, isCRDEstablished(&unstructured.Unstructured{Object: map[string]interface{}{}}), false)
+}
+
+func TestConversionWebhookService(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"conversion": map[string]interface{}{
+				"strategy": "Webhook",
+				"webhook": map[string]interface{}{
+					"clientConfig": map[string]interface{}{
+						"service": map[string]interface{}{
+							"name":      "operator-webhook",
+							"namespace": "knative-operator",
+							"path":      "/resource-conversion",
+						},
+					},
+				},
+			},
+		},
+	}}
+	namespace, name, ok := conversionWebhookService(crd)
+	testingUtil.AssertEqual(t, ok, true)
+	testingUtil.AssertEqual(t, namespace, "knative-operator")
+	testingUtil.AssertEqual(t, name, "operator-webhook")
+
+	crd.Object["spec"] = map[string]interface{}{
+		"conversion": map[string]interface{}{"strategy": "None"},
+	}
+	_, _, ok = conversionWebhookService(crd)
+	testingUtil.AssertEqual(t, ok, false)
+}
+
+func TestHasReadyEndpoint(t *testing.T) {
+	ready, notReady := true, false
+	testingUtil.AssertEqual(t, hasReadyEndpoint(nil), false)
+	testingUtil.AssertEqual(t, hasReadyEndpoint([]discoveryv1.EndpointSlice{{
+		Endpoints: []discoveryv1.Endpoint{{Conditions: discoveryv1.EndpointConditions{Ready: &notReady}}},
+	}}), false)
+	testingUtil.AssertEqual(t, hasReadyEndpoint([]discoveryv1.EndpointSlice{{
+		Endpoints: []discoveryv1.Endpoint{
+			{Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			{Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+		},
+	}}), true)
+}
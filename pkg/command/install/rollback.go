@@ -0,0 +1,196 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package install
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/ui/progressindicator"
+)
+
+type rollbackCmdFlags struct {
+	Component string
+	Namespace string
+	Version   string
+	// Wait waits until the Knative component is ready at the version rolled back to
+	Wait bool
+	// Timeout is the maximum time to wait for the Knative component to be ready
+	Timeout time.Duration
+}
+
+// NewRollbackCommand represents the rollback commands for Knative Serving or Eventing
+func NewRollbackCommand(p *pkg.OperatorParams) *cobra.Command {
+	var rollbackFlags rollbackCmdFlags
+	var rollbackCmd = &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back Knative Serving or Eventing to the previously installed version",
+		Example: `
+  # Roll back Knative Serving to the version installed before the last upgrade
+  kn operator rollback -c serving --namespace knative-serving
+  # Roll back Knative Eventing to the version 1.4.0
+  kn operator rollback -c eventing --namespace knative-eventing --version 1.4.0`,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateRollbackFlags(rollbackFlags); err != nil {
+				return err
+			}
+			rollbackFlags.fill_defaults()
+
+			version, err := runRollbackCommand(rollbackFlags, p)
+			if err != nil {
+				return err
+			}
+
+			component := common.ServingComponent
+			if strings.EqualFold(rollbackFlags.Component, common.EventingComponent) {
+				component = common.EventingComponent
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Knative %s was rolled back to the '%s' version in the namespace '%s'.\n",
+				component, version, rollbackFlags.Namespace)
+			return nil
+		},
+	}
+
+	rollbackCmd.Flags().StringVarP(&rollbackFlags.Namespace, "namespace", "n", "", "The namespace of the Knative component")
+	rollbackCmd.Flags().StringVarP(&rollbackFlags.Component, "component", "c", "", "The name of the Knative Component to roll back")
+	rollbackCmd.Flags().StringVarP(&rollbackFlags.Version, "version", "v", "", "The version to roll back to (default is the version installed before the last upgrade)")
+	rollbackCmd.Flags().BoolVar(&rollbackFlags.Wait, "wait", true, "The flag to wait until the Knative component is ready")
+	rollbackCmd.Flags().DurationVar(&rollbackFlags.Timeout, "timeout", common.DefaultTimeout, "The maximum time to wait for the Knative component to be ready")
+
+	return rollbackCmd
+}
+
+func validateRollbackFlags(rollbackFlags rollbackCmdFlags) error {
+	if !strings.EqualFold(rollbackFlags.Component, common.ServingComponent) && !strings.EqualFold(rollbackFlags.Component, common.EventingComponent) {
+		return fmt.Errorf("You need to specify the component for Knative: serving or eventing.")
+	}
+	if strings.EqualFold(rollbackFlags.Version, common.Latest) || strings.EqualFold(rollbackFlags.Version, common.Nightly) {
+		return fmt.Errorf("You need to specify a semantic version to roll back to.")
+	}
+	return nil
+}
+
+func (flags *rollbackCmdFlags) fill_defaults() {
+	if flags.Namespace == "" {
+		if strings.EqualFold(flags.Component, common.ServingComponent) {
+			flags.Namespace = common.DefaultKnativeServingNamespace
+		} else if strings.EqualFold(flags.Component, common.EventingComponent) {
+			flags.Namespace = common.DefaultKnativeEventingNamespace
+		}
+	}
+}
+
+func runRollbackCommand(rollbackFlags rollbackCmdFlags, p *pkg.OperatorParams) (string, error) {
+	ctx := p.Context()
+	pi := progressindicator.New().SetText("Rolling back...")
+	pi.Start()
+	defer pi.Stop()
+
+	client, err := p.NewKubeClient()
+	if err != nil {
+		return "", fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	deploy := common.Deployment{
+		Client: client,
+	}
+
+	exists, ns, currentVersion, err := deploy.CheckIfKnativeInstalled(ctx, rollbackFlags.Component)
+	if err != nil {
+		return "", err
+	}
+	if !exists || !strings.EqualFold(ns, rollbackFlags.Namespace) {
+		return "", fmt.Errorf("Knative %s is not installed in the namespace '%s'.", rollbackFlags.Component, rollbackFlags.Namespace)
+	}
+
+	ksCR, err := common.GetKnativeOperatorCR(p)
+	if err != nil {
+		return "", err
+	}
+	previousVersion, previousSpec, err := ksCR.GetPreviousState(ctx, rollbackFlags.Component, rollbackFlags.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	targetVersion := rollbackFlags.Version
+	if targetVersion == "" {
+		targetVersion = previousVersion
+	}
+	if targetVersion == "" {
+		return "", fmt.Errorf("No previous version of Knative %s is recorded. Please specify the version with --version.",
+			rollbackFlags.Component)
+	}
+	// The recorded spec is only restored if we roll back to the recorded version
+	if !isSameVersion(targetVersion, previousVersion) {
+		previousSpec = ""
+	}
+
+	versions, err := generateVersionStages(currentVersion, targetVersion)
+	if err != nil {
+		return "", err
+	}
+
+	for i, v := range versions {
+		pi.SetText(fmt.Sprintf("Rolling back Knative %s to Version %s...", rollbackFlags.Component, v))
+
+		spec := ""
+		if i == len(versions)-1 {
+			spec = previousSpec
+		}
+		if err = rollbackKnativeComponent(rollbackFlags.Component, rollbackFlags.Namespace, v, spec, p); err != nil {
+			return "", err
+		}
+
+		// The intermediate versions have to be ready before moving to the next version
+		if !rollbackFlags.Wait && i == len(versions)-1 {
+			break
+		}
+		err = ensureKnativeComponentReady(&InstallFlags{
+			Component: rollbackFlags.Component,
+			Namespace: rollbackFlags.Namespace,
+			Version:   v,
+			Timeout:   rollbackFlags.Timeout,
+		}, p)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	pi.Stop()
+	return versions[len(versions)-1], nil
+}
+
+// rollbackKnativeComponent sets the version of the Knative custom resource. If spec is not empty,
+// the spec of the custom resource is replaced by it before the version is set. The rollback is recorded in the
+// history and in the previous state, like any other change, so that it can be undone.
+func rollbackKnativeComponent(component, namespace, version, spec string, p *pkg.OperatorParams) error {
+	mutators := []common.SpecMutator{}
+	if spec != "" {
+		mutators = append(mutators, common.ReplaceSpec([]byte(spec)))
+	}
+	mutators = append(mutators, common.SetVersion(version))
+	return common.UpdateKnativeCR(component, namespace, p, mutators...)
+}
+
+// isSameVersion checks if two versions are identical, regardless of the prefix v
+func isSameVersion(version, otherVersion string) bool {
+	return strings.TrimPrefix(version, "v") == strings.TrimPrefix(otherVersion, "v")
+}
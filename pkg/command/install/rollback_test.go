@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"fmt"
+	"testing"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+)
+
+func TestValidateRollbackFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		rollbackFlags  rollbackCmdFlags
+		expectedResult error
+	}{{
+		name: "Knative Serving",
+		rollbackFlags: rollbackCmdFlags{
+			Component: "serving",
+		},
+		expectedResult: nil,
+	}, {
+		name: "Knative Eventing with version",
+		rollbackFlags: rollbackCmdFlags{
+			Component: "eventing",
+			Version:   "1.4.0",
+		},
+		expectedResult: nil,
+	}, {
+		name:           "Knative with no component",
+		rollbackFlags:  rollbackCmdFlags{},
+		expectedResult: fmt.Errorf("You need to specify the component for Knative: serving or eventing."),
+	}, {
+		name: "Knative Serving with the latest version",
+		rollbackFlags: rollbackCmdFlags{
+			Component: "serving",
+			Version:   "latest",
+		},
+		expectedResult: fmt.Errorf("You need to specify a semantic version to roll back to."),
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validateRollbackFlags(tt.rollbackFlags)
+			if tt.expectedResult == nil {
+				testingUtil.AssertEqual(t, result, nil)
+			} else {
+				testingUtil.AssertEqual(t, result.Error(), tt.expectedResult.Error())
+			}
+		})
+	}
+}
+
+func TestFillDefaultsForRollbackCmdFlags(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		inputFlags    rollbackCmdFlags
+		expectedFlags rollbackCmdFlags
+	}{{
+		name: "Empty namespace for serving",
+		inputFlags: rollbackCmdFlags{
+			Component: "serving",
+		},
+		expectedFlags: rollbackCmdFlags{
+			Component: "serving",
+			Namespace: common.DefaultKnativeServingNamespace,
+		},
+	}, {
+		name: "Empty namespace for eventing",
+		inputFlags: rollbackCmdFlags{
+			Component: "eventing",
+		},
+		expectedFlags: rollbackCmdFlags{
+			Component: "eventing",
+			Namespace: common.DefaultKnativeEventingNamespace,
+		},
+	}, {
+		name: "Namespace is kept",
+		inputFlags: rollbackCmdFlags{
+			Component: "eventing",
+			Namespace: "test-eventing",
+		},
+		expectedFlags: rollbackCmdFlags{
+			Component: "eventing",
+			Namespace: "test-eventing",
+		},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.inputFlags.fill_defaults()
+			testingUtil.AssertEqual(t, tt.inputFlags, tt.expectedFlags)
+		})
+	}
+}
+
+func TestIsSameVersion(t *testing.T) {
+	testingUtil.AssertEqual(t, isSameVersion("1.4.0", "v1.4.0"), true)
+	testingUtil.AssertEqual(t, isSameVersion("v1.4.0", "v1.4.0"), true)
+	testingUtil.AssertEqual(t, isSameVersion("1.4.0", "1.5.0"), false)
+	testingUtil.AssertEqual(t, isSameVersion("1.4.0", ""), false)
+}
@@ -19,18 +19,15 @@ import (
 
 	"knative.dev/operator/pkg/apis/operator/base"
 
-	"k8s.io/client-go/util/retry"
-
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
 
-var annotationCMDFlags common.KeyValueFlags
-
 // removeAnnotationCommand represents the configure commands to delete the annotations for Knative deployments or services
 func removeAnnotationCommand(p *pkg.OperatorParams) *cobra.Command {
+	var annotationCMDFlags common.KeyValueFlags
 	var removeAnnotationsCmd = &cobra.Command{
 		Use:   "annotations",
 		Short: "Remove the annotations for Knative Serving and Eventing deployments or services",
@@ -65,37 +62,14 @@ func removeAnnotationCommand(p *pkg.OperatorParams) *cobra.Command {
 }
 
 func deleteAnnotations(annotationCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	if annotationCMDFlags.DeployName != "" {
-		wordloads, err := ksCR.GetDeployments(annotationCMDFlags.Component, annotationCMDFlags.Namespace)
-		if err != nil {
-			return err
-		}
-
-		wordloads = removeAnnotationsDeployFields(wordloads, annotationCMDFlags)
-		if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			return ksCR.UpdateDeployments(annotationCMDFlags.Component, annotationCMDFlags.Namespace, wordloads)
-		}); err != nil {
-			return err
-		}
-	} else if annotationCMDFlags.ServiceName != "" {
-		serviceOverrides, err := ksCR.GetServices(annotationCMDFlags.Component, annotationCMDFlags.Namespace)
-		if err != nil {
-			return err
+	return common.UpdateKnativeCR(annotationCMDFlags.Component, annotationCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		if annotationCMDFlags.DeployName != "" {
+			spec.DeploymentOverride = removeAnnotationsDeployFields(spec.DeploymentOverride, annotationCMDFlags)
+		} else if annotationCMDFlags.ServiceName != "" {
+			spec.ServiceOverride = removeAnnotationsServiceFields(spec.ServiceOverride, annotationCMDFlags)
 		}
-
-		serviceOverrides = removeAnnotationsServiceFields(serviceOverrides, annotationCMDFlags)
-		if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			return ksCR.UpdateServices(annotationCMDFlags.Component, annotationCMDFlags.Namespace, serviceOverrides)
-		}); err != nil {
-			return err
-		}
-	}
-	return nil
+		return nil
+	})
 }
 
 func removeAnnotationsDeployFields(workloadOverrides []base.WorkloadOverride, annotationCMDFlags common.KeyValueFlags) []base.WorkloadOverride {
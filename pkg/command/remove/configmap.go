@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"strings"
 
-	"k8s.io/client-go/util/retry"
 	"knative.dev/operator/pkg/apis/operator/base"
 
 	"github.com/spf13/cobra"
@@ -27,10 +26,9 @@ import (
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
 
-var cmsCMDFlags common.CMsFlags
-
 // removeConfigMapsCommand represents the remove commands to delete the ConfigMaps in Knative Serving or Eventing
 func removeConfigMapsCommand(p *pkg.OperatorParams) *cobra.Command {
+	var cmsCMDFlags common.CMsFlags
 	var configureCMsCmd = &cobra.Command{
 		Use:   "configmaps",
 		Short: "Delete the configmap configurations for Knative Serving and Eventing deployments",
@@ -76,26 +74,10 @@ func validateCMsFlags(cmsCMDFlags common.CMsFlags) error {
 }
 
 func removeCMs(cmsCMDFlags common.CMsFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	cmData, err := ksCR.GetConfigMaps(cmsCMDFlags.Component, cmsCMDFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	cmData = removeCMsFields(cmData, cmsCMDFlags)
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateConfigMaps(cmsCMDFlags.Component, cmsCMDFlags.Namespace, cmData)
+	return common.UpdateKnativeCR(cmsCMDFlags.Component, cmsCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.Config = removeCMsFields(spec.Config, cmsCMDFlags)
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func removeCMsFields(cmData base.ConfigMapData, cmsCMDFlags common.CMsFlags) base.ConfigMapData {
@@ -23,7 +23,6 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
@@ -37,10 +36,9 @@ type EnvVarFlags struct {
 	ContainerName string
 }
 
-var envVarFlags EnvVarFlags
-
 // removeEnvVarCommand represents the configure commands to delete the env vars configuration for Knative Deployment resources
 func removeEnvVarCommand(p *pkg.OperatorParams) *cobra.Command {
+	var envVarFlags EnvVarFlags
 	var removeEnvVarsCmd = &cobra.Command{
 		Use:   "envvars",
 		Short: "Delete the env vars for Knative",
@@ -96,27 +94,10 @@ func validateEnvVarsFlags(envVarFlags EnvVarFlags) error {
 }
 
 func removeEnvVars(envVarFlags EnvVarFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides, err := ksCR.GetDeployments(envVarFlags.Component, envVarFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides = removeEnvVarsFields(workloadOverrides, envVarFlags)
-
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateDeployments(envVarFlags.Component, envVarFlags.Namespace, workloadOverrides)
+	return common.UpdateKnativeCR(envVarFlags.Component, envVarFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.DeploymentOverride = removeEnvVarsFields(spec.DeploymentOverride, envVarFlags)
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func removeEnvVarsFields(workloadOverrides []base.WorkloadOverride, envVarFlags EnvVarFlags) []base.WorkloadOverride {
@@ -21,7 +21,6 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 )
@@ -33,10 +32,9 @@ type HAFlags struct {
 	DeployName string
 }
 
-var haCMDFlags HAFlags
-
 // removeHACommand represents the HA deletion commands for Serving or Eventing
 func removeHACommand(p *pkg.OperatorParams) *cobra.Command {
+	var haCMDFlags HAFlags
 	var removeHAsCmd = &cobra.Command{
 		Use:   "replicas",
 		Short: "Remove the replica configuration for Knative Serving and Eventing deployments",
@@ -77,26 +75,10 @@ func validateHAsFlags(haCMDFlags HAFlags) error {
 }
 
 func removeHAs(haCMDFlags HAFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	commonSpec, err := ksCR.GetCommonSpec(haCMDFlags.Component, haCMDFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	commonSpec = removeReplicasFields(commonSpec, haCMDFlags)
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateCommonSpec(haCMDFlags.Component, haCMDFlags.Namespace, commonSpec)
+	return common.UpdateKnativeCR(haCMDFlags.Component, haCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		removeReplicasFields(spec.CommonSpec, haCMDFlags)
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func removeReplicasFields(commonSpec *base.CommonSpec, haCMDFlags HAFlags) *base.CommonSpec {
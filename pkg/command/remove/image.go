@@ -18,7 +18,6 @@ import (
 	"fmt"
 	"strings"
 
-	"k8s.io/client-go/util/retry"
 	"knative.dev/operator/pkg/apis/operator/base"
 
 	"github.com/spf13/cobra"
@@ -35,10 +34,9 @@ type ImageFlags struct {
 	ImageKey   string
 }
 
-var imageCMDFlags ImageFlags
-
 // removeImageCommand represents the configure commands to delete the image configuration for Knative
 func removeImageCommand(p *pkg.OperatorParams) *cobra.Command {
+	var imageCMDFlags ImageFlags
 	var removeImagesCmd = &cobra.Command{
 		Use:   "images",
 		Short: "Remove the images for Knative",
@@ -82,25 +80,10 @@ func validateImagesFlags(imageCMDFlags ImageFlags) error {
 }
 
 func removeImages(imageCMDFlags ImageFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	registry, err := ksCR.GetRegistry(imageCMDFlags.Component, imageCMDFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	registry = removeImagesFields(registry, imageCMDFlags)
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateRegistry(imageCMDFlags.Component, imageCMDFlags.Namespace, registry)
+	return common.UpdateKnativeCR(imageCMDFlags.Component, imageCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.Registry = removeImagesFields(spec.Registry, imageCMDFlags)
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
 func removeImagesFields(registry base.Registry, imageCMDFlags ImageFlags) base.Registry {
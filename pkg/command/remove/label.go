@@ -20,17 +20,15 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-var deploymentLabelCMDFlags common.KeyValueFlags
-
 // removeLabelCommand represents the configure commands to delete the labels for Knative deployments or services
 func removeLabelCommand(p *pkg.OperatorParams) *cobra.Command {
+	var deploymentLabelCMDFlags common.KeyValueFlags
 	var removeLabelsCmd = &cobra.Command{
 		Use:   "labels",
 		Short: "Remove the labels for Knative Serving and Eventing deployments or services",
@@ -87,37 +85,14 @@ func validateLabelAnnotationsFlags(deploymentLabelCMDFlags common.KeyValueFlags)
 }
 
 func deleteLabels(labelCMDFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	if labelCMDFlags.DeployName != "" {
-		workloadOverrides, err := ksCR.GetDeployments(labelCMDFlags.Component, labelCMDFlags.Namespace)
-		if err != nil {
-			return err
-		}
-
-		workloadOverrides = removeLabelsDeployFields(workloadOverrides, labelCMDFlags)
-		if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			return ksCR.UpdateDeployments(labelCMDFlags.Component, labelCMDFlags.Namespace, workloadOverrides)
-		}); err != nil {
-			return err
+	return common.UpdateKnativeCR(labelCMDFlags.Component, labelCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		if labelCMDFlags.DeployName != "" {
+			spec.DeploymentOverride = removeLabelsDeployFields(spec.DeploymentOverride, labelCMDFlags)
+		} else if labelCMDFlags.ServiceName != "" {
+			spec.ServiceOverride = removeLabelsServiceFields(spec.ServiceOverride, labelCMDFlags)
 		}
-	} else if labelCMDFlags.ServiceName != "" {
-		serviceOverrides, err := ksCR.GetServices(labelCMDFlags.Component, labelCMDFlags.Namespace)
-		if err != nil {
-			return err
-		}
-
-		serviceOverrides = removeLabelsServiceFields(serviceOverrides, labelCMDFlags)
-		if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			return ksCR.UpdateServices(labelCMDFlags.Component, labelCMDFlags.Namespace, serviceOverrides)
-		}); err != nil {
-			return err
-		}
-	}
-	return nil
+		return nil
+	})
 }
 
 func removeLabelsDeployFields(workOverrides []base.WorkloadOverride, labelCMDFlags common.KeyValueFlags) []base.WorkloadOverride {
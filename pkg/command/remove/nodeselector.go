@@ -20,17 +20,15 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-var nodeSelectorFlags common.KeyValueFlags
-
 // removeNodeSelectorCommand represents the configure commands to delete the node selector for Knative deployments
 func removeNodeSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
+	var nodeSelectorFlags common.KeyValueFlags
 	var removeNodeSelectorsCmd = &cobra.Command{
 		Use:   "nodeSelectors",
 		Short: "Remove the node selectors for Knative Serving and Eventing deployments",
@@ -80,23 +78,10 @@ func validateNodeSelectorFlags(keyValuesCMDFlags common.KeyValueFlags) error {
 }
 
 func deleteNodeSelectors(nodeSelectorFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides, err := ksCR.GetDeployments(nodeSelectorFlags.Component, nodeSelectorFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides = removeNodeSelectorsDeployFields(workloadOverrides, nodeSelectorFlags)
-	if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateDeployments(nodeSelectorFlags.Component, nodeSelectorFlags.Namespace, workloadOverrides)
-	}); err != nil {
-		return err
-	}
-	return nil
+	return common.UpdateKnativeCR(nodeSelectorFlags.Component, nodeSelectorFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.DeploymentOverride = removeNodeSelectorsDeployFields(spec.DeploymentOverride, nodeSelectorFlags)
+		return nil
+	})
 }
 
 func removeNodeSelectorsDeployFields(workloadOverrides []base.WorkloadOverride, nodeSelectorFlags common.KeyValueFlags) []base.WorkloadOverride {
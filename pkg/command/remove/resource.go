@@ -19,7 +19,6 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
@@ -33,10 +32,9 @@ type ResourcesFlags struct {
 	DeployName string
 }
 
-var resourcesCMDFlags ResourcesFlags
-
 // removeResourcesCommand represents the remove commands for the resources in Knative Serving or Eventing
 func removeResourcesCommand(p *pkg.OperatorParams) *cobra.Command {
+	var resourcesCMDFlags ResourcesFlags
 	var deleteResourcesCmd = &cobra.Command{
 		Use:   "resources",
 		Short: "Remove the resource for Knative Serving and Eventing deployments",
@@ -86,26 +84,10 @@ func validateResourcesFlags(resourcesCMDFlags ResourcesFlags) error {
 }
 
 func removeResources(resourcesCMDFlags ResourcesFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides, err := ksCR.GetDeployments(resourcesCMDFlags.Component, resourcesCMDFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides = removeResourcesFields(workloadOverrides, resourcesCMDFlags)
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateDeployments(resourcesCMDFlags.Component, resourcesCMDFlags.Namespace, workloadOverrides)
+	return common.UpdateKnativeCR(resourcesCMDFlags.Component, resourcesCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.DeploymentOverride = removeResourcesFields(spec.DeploymentOverride, resourcesCMDFlags)
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func removeResourcesFields(workloadOverrides []base.WorkloadOverride, resourcesCMDFlags ResourcesFlags) []base.WorkloadOverride {
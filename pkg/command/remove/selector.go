@@ -20,17 +20,15 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/operator/pkg/apis/operator/base"
 )
 
-var selectorFlags common.KeyValueFlags
-
 // removeSelectorCommand represents the configure commands to delete the selector for Knative services
 func removeSelectorCommand(p *pkg.OperatorParams) *cobra.Command {
+	var selectorFlags common.KeyValueFlags
 	var removeNodeSelectorsCmd = &cobra.Command{
 		Use:   "selectors",
 		Short: "Remove the selectors for Knative Serving and Eventing service",
@@ -80,23 +78,10 @@ func validateSelectorFlags(keyValuesCMDFlags common.KeyValueFlags) error {
 }
 
 func deleteSelectors(selectorFlags common.KeyValueFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	serviceOverrides, err := ksCR.GetServices(selectorFlags.Component, selectorFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	serviceOverrides = removeSelectorsServiceFields(serviceOverrides, selectorFlags)
-	if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateServices(selectorFlags.Component, selectorFlags.Namespace, serviceOverrides)
-	}); err != nil {
-		return err
-	}
-	return nil
+	return common.UpdateKnativeCR(selectorFlags.Component, selectorFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.ServiceOverride = removeSelectorsServiceFields(spec.ServiceOverride, selectorFlags)
+		return nil
+	})
 }
 
 func removeSelectorsServiceFields(serviceOverrides []base.ServiceOverride, selectorFlags common.KeyValueFlags) []base.ServiceOverride {
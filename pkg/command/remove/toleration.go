@@ -21,7 +21,6 @@ import (
 
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
-	"k8s.io/client-go/util/retry"
 	"knative.dev/kn-plugin-operator/pkg"
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 	"knative.dev/operator/pkg/apis/operator/base"
@@ -37,10 +36,9 @@ type TolerationsFlags struct {
 	DeployName string
 }
 
-var tolerationsCMDFlags TolerationsFlags
-
 // removeTolerationsCommand represents the remove commands for the tolerations in Knative Serving or Eventing
 func removeTolerationsCommand(p *pkg.OperatorParams) *cobra.Command {
+	var tolerationsCMDFlags TolerationsFlags
 	var configureTolerationsCmd = &cobra.Command{
 		Use:   "tolerations",
 		Short: "Remove the tolerations for Knative Serving and Eventing deployments",
@@ -86,26 +84,10 @@ func validateTolerationsFlags(tolerationsCMDFlags TolerationsFlags) error {
 }
 
 func deleteTolerations(tolerationsCMDFlags TolerationsFlags, p *pkg.OperatorParams) error {
-	ksCR, err := common.GetKnativeOperatorCR(p)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides, err := ksCR.GetDeployments(tolerationsCMDFlags.Component, tolerationsCMDFlags.Namespace)
-	if err != nil {
-		return err
-	}
-
-	workloadOverrides = removeTolerationsFields(workloadOverrides, tolerationsCMDFlags)
-	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		return ksCR.UpdateDeployments(tolerationsCMDFlags.Component, tolerationsCMDFlags.Namespace, workloadOverrides)
+	return common.UpdateKnativeCR(tolerationsCMDFlags.Component, tolerationsCMDFlags.Namespace, p, func(spec *common.KnativeSpec) error {
+		spec.DeploymentOverride = removeTolerationsFields(spec.DeploymentOverride, tolerationsCMDFlags)
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func removeTolerationsFields(workloadOverrides []base.WorkloadOverride, tolerationsCMDFlags TolerationsFlags) []base.WorkloadOverride {
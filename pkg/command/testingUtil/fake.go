@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testingUtil
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/gentype"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+	"knative.dev/operator/pkg/client/clientset/versioned"
+	"knative.dev/operator/pkg/client/clientset/versioned/scheme"
+	operatorv1beta1 "knative.dev/operator/pkg/client/clientset/versioned/typed/operator/v1beta1"
+)
+
+// NewFakeOperatorParams returns the parameters, which create the Kubernetes client and the client of the
+// Knative Operator from the fake clients
+func NewFakeOperatorParams(kubeClient kubernetes.Interface, operatorClient versioned.Interface) *pkg.OperatorParams {
+	return &pkg.OperatorParams{
+		NewKubeClient: func() (kubernetes.Interface, error) {
+			return kubeClient, nil
+		},
+		NewOperatorClient: func() (versioned.Interface, error) {
+			return operatorClient, nil
+		},
+	}
+}
+
+// FakeOperatorClient is a fake client of the Knative Operator, which serves the custom resources from memory
+type FakeOperatorClient struct {
+	k8stesting.Fake
+	tracker k8stesting.ObjectTracker
+}
+
+var _ versioned.Interface = &FakeOperatorClient{}
+
+// NewFakeOperatorClient returns a fake client of the Knative Operator, which serves the objects
+func NewFakeOperatorClient(objects ...runtime.Object) *FakeOperatorClient {
+	tracker := k8stesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, obj := range objects {
+		if err := tracker.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+
+	client := &FakeOperatorClient{tracker: tracker}
+	client.AddReactor("*", "*", k8stesting.ObjectReaction(tracker))
+	client.AddWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		var opts metav1.ListOptions
+		if watchAction, ok := action.(k8stesting.WatchActionImpl); ok {
+			opts = watchAction.ListOptions
+		}
+		watcher, err := tracker.Watch(action.GetResource(), action.GetNamespace(), opts)
+		if err != nil {
+			return false, nil, err
+		}
+		return true, watcher, nil
+	})
+	return client
+}
+
+// Tracker returns the tracker of the objects served by the client
+func (c *FakeOperatorClient) Tracker() k8stesting.ObjectTracker {
+	return c.tracker
+}
+
+// Discovery returns a fake discovery client
+func (c *FakeOperatorClient) Discovery() discovery.DiscoveryInterface {
+	return &fakediscovery.FakeDiscovery{Fake: &c.Fake}
+}
+
+// OperatorV1beta1 returns a fake client of the Knative custom resources of the version v1beta1
+func (c *FakeOperatorClient) OperatorV1beta1() operatorv1beta1.OperatorV1beta1Interface {
+	return &fakeOperatorV1beta1{Fake: &c.Fake}
+}
+
+type fakeOperatorV1beta1 struct {
+	*k8stesting.Fake
+}
+
+func (c *fakeOperatorV1beta1) KnativeServings(namespace string) operatorv1beta1.KnativeServingInterface {
+	return gentype.NewFakeClientWithList[*v1beta1.KnativeServing, *v1beta1.KnativeServingList](
+		c.Fake, namespace,
+		v1beta1.SchemeGroupVersion.WithResource("knativeservings"),
+		v1beta1.SchemeGroupVersion.WithKind("KnativeServing"),
+		func() *v1beta1.KnativeServing { return &v1beta1.KnativeServing{} },
+		func() *v1beta1.KnativeServingList { return &v1beta1.KnativeServingList{} },
+		func(dst, src *v1beta1.KnativeServingList) { dst.ListMeta = src.ListMeta },
+		func(list *v1beta1.KnativeServingList) []*v1beta1.KnativeServing {
+			return gentype.ToPointerSlice(list.Items)
+		},
+		func(list *v1beta1.KnativeServingList, items []*v1beta1.KnativeServing) {
+			list.Items = gentype.FromPointerSlice(items)
+		},
+	)
+}
+
+func (c *fakeOperatorV1beta1) KnativeEventings(namespace string) operatorv1beta1.KnativeEventingInterface {
+	return gentype.NewFakeClientWithList[*v1beta1.KnativeEventing, *v1beta1.KnativeEventingList](
+		c.Fake, namespace,
+		v1beta1.SchemeGroupVersion.WithResource("knativeeventings"),
+		v1beta1.SchemeGroupVersion.WithKind("KnativeEventing"),
+		func() *v1beta1.KnativeEventing { return &v1beta1.KnativeEventing{} },
+		func() *v1beta1.KnativeEventingList { return &v1beta1.KnativeEventingList{} },
+		func(dst, src *v1beta1.KnativeEventingList) { dst.ListMeta = src.ListMeta },
+		func(list *v1beta1.KnativeEventingList) []*v1beta1.KnativeEventing {
+			return gentype.ToPointerSlice(list.Items)
+		},
+		func(list *v1beta1.KnativeEventingList, items []*v1beta1.KnativeEventing) {
+			list.Items = gentype.FromPointerSlice(items)
+		},
+	)
+}
+
+// RESTClient returns nil, because the fake client does not send any request
+func (c *fakeOperatorV1beta1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
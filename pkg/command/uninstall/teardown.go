@@ -0,0 +1,153 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uninstall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/operator/pkg/client/clientset/versioned"
+)
+
+// teardownComponent waits for the Knative component to be removed from the namespaces, and deletes the
+// namespaces if required. The custom resources need to be gone before their namespace is deleted, so that
+// the Knative Operator can finalize them.
+func teardownComponent(component string, namespaces []string, uninstallFlags uninstallCmdFlags, p *pkg.OperatorParams) error {
+	ctx := p.Context()
+	if !uninstallFlags.Wait && !uninstallFlags.DeleteNamespace {
+		return nil
+	}
+
+	client, err := p.NewKubeClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	operatorClient, err := p.NewOperatorClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+
+	if len(namespaces) == 0 && uninstallFlags.Namespace != "" {
+		namespaces = []string{uninstallFlags.Namespace}
+	}
+	namespaces = uniqueNamespaces(namespaces)
+
+	for _, namespace := range namespaces {
+		p.SetStep(fmt.Sprintf("Waiting for Knative %s to be removed from the namespace '%s'...", component, namespace))
+		if err := waitForComponentRemoval(ctx, client, operatorClient, component, namespace, uninstallFlags.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if !uninstallFlags.DeleteNamespace {
+		return nil
+	}
+	for _, namespace := range namespaces {
+		p.SetStep(fmt.Sprintf("Deleting the namespace '%s'...", namespace))
+		err := client.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if !uninstallFlags.Wait {
+		return nil
+	}
+	for _, namespace := range namespaces {
+		p.SetStep(fmt.Sprintf("Waiting for the namespace '%s' to be removed...", namespace))
+		if err := waitForNamespaceRemoval(ctx, client, namespace, uninstallFlags.Timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForComponentRemoval waits until the custom resources of the Knative component and its deployments
+// are removed from the namespace.
+func waitForComponentRemoval(ctx context.Context, client kubernetes.Interface, operatorClient versioned.Interface, component, namespace string,
+	timeout time.Duration) error {
+	// The custom resources and the deployments are watched one after the other within the same timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	kind := "custom resources"
+	lw := common.KnativeEventingListWatch(operatorClient.OperatorV1beta1().KnativeEventings(namespace), "")
+	if strings.EqualFold(component, common.ServingComponent) {
+		lw = common.KnativeServingListWatch(operatorClient.OperatorV1beta1().KnativeServings(namespace), "")
+	}
+	var remaining []string
+	untilRemoved := func(objects map[string]runtime.Object) (bool, error) {
+		remaining = objectNames(objects)
+		return len(remaining) == 0, nil
+	}
+	err := common.WatchUntil(ctx, timeout, lw, untilRemoved)
+	if err == nil {
+		kind = "deployments"
+		err = common.WatchUntil(ctx, timeout, common.DeploymentListWatch(client, namespace, common.ComponentSelector(component)),
+			untilRemoved)
+	}
+
+	if errors.Is(err, common.ErrWaitTimeout) {
+		return fmt.Errorf("Knative %s was not removed from the namespace '%s' within %s, remaining %s: %s",
+			component, namespace, timeout, kind, strings.Join(remaining, ", "))
+	}
+	return err
+}
+
+// waitForNamespaceRemoval waits until the namespace is removed.
+func waitForNamespaceRemoval(ctx context.Context, client kubernetes.Interface, namespace string, timeout time.Duration) error {
+	err := common.WatchUntil(ctx, timeout, common.NamespaceListWatch(client, namespace), func(objects map[string]runtime.Object) (bool, error) {
+		_, exists := objects[namespace]
+		return !exists, nil
+	})
+
+	if errors.Is(err, common.ErrWaitTimeout) {
+		return fmt.Errorf("The namespace '%s' was not removed within %s", namespace, timeout)
+	}
+	return err
+}
+
+func objectNames(objects map[string]runtime.Object) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func uniqueNamespaces(namespaces []string) []string {
+	set := make(map[string]bool, len(namespaces))
+	var result []string
+	for _, namespace := range namespaces {
+		if namespace == "" || set[namespace] {
+			continue
+		}
+		set[namespace] = true
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+	return result
+}
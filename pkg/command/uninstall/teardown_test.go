@@ -0,0 +1,119 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uninstall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestUniqueNamespaces(t *testing.T) {
+	result := uniqueNamespaces([]string{"knative-serving", "", "test-ns", "knative-serving"})
+	testingUtil.AssertDeepEqual(t, result, []string{"knative-serving", "test-ns"})
+	testingUtil.AssertDeepEqual(t, uniqueNamespaces(nil), []string(nil))
+}
+
+func TestTeardownComponentWaitTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		kubeObjects   []runtime.Object
+		operatorCRs   []runtime.Object
+		expectedError string
+	}{{
+		name:        "The custom resource is not finalized",
+		kubeObjects: []runtime.Object{servingDeployment("activator")},
+		operatorCRs: []runtime.Object{&v1beta1.KnativeServing{
+			ObjectMeta: metav1.ObjectMeta{Name: "knative-serving", Namespace: "knative-serving"},
+		}},
+		expectedError: "Knative serving was not removed from the namespace 'knative-serving' within 100ms, " +
+			"remaining custom resources: knative-serving",
+	}, {
+		name:        "The deployments are not removed",
+		kubeObjects: []runtime.Object{servingDeployment("controller"), servingDeployment("activator")},
+		expectedError: "Knative serving was not removed from the namespace 'knative-serving' within 100ms, " +
+			"remaining deployments: activator, controller",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testingUtil.NewFakeOperatorParams(fake.NewClientset(tt.kubeObjects...),
+				testingUtil.NewFakeOperatorClient(tt.operatorCRs...))
+			flags := uninstallCmdFlags{Namespace: "knative-serving", Wait: true, Timeout: 100 * time.Millisecond}
+			err := teardownComponent(common.ServingComponent, nil, flags, p)
+			if err == nil {
+				t.Fatalf("expected the error %q", tt.expectedError)
+			}
+			testingUtil.AssertEqual(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestTeardownComponentWaitRemoved(t *testing.T) {
+	kubeClient := fake.NewClientset(servingDeployment("activator"))
+	p := testingUtil.NewFakeOperatorParams(kubeClient, testingUtil.NewFakeOperatorClient())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		kubeClient.AppsV1().Deployments("knative-serving").Delete(context.Background(), "activator", metav1.DeleteOptions{})
+	}()
+
+	flags := uninstallCmdFlags{Wait: true, Timeout: time.Minute}
+	err := teardownComponent(common.ServingComponent, []string{"knative-serving"}, flags, p)
+	testingUtil.AssertEqual(t, err, nil)
+}
+
+func TestTeardownComponentDeleteNamespace(t *testing.T) {
+	for _, wait := range []bool{false, true} {
+		kubeClient := fake.NewClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "knative-serving"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		)
+		p := testingUtil.NewFakeOperatorParams(kubeClient, testingUtil.NewFakeOperatorClient())
+		flags := uninstallCmdFlags{DeleteNamespace: true, Wait: wait, Timeout: time.Minute}
+		err := teardownComponent(common.ServingComponent, []string{"knative-serving", "knative-serving"}, flags, p)
+		testingUtil.AssertEqual(t, err, nil)
+
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.Background(), "knative-serving", metav1.GetOptions{})
+		testingUtil.AssertEqual(t, apierrors.IsNotFound(err), true)
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.Background(), "default", metav1.GetOptions{})
+		testingUtil.AssertEqual(t, err, nil)
+	}
+}
+
+func TestTeardownComponentWithoutWait(t *testing.T) {
+	p := testingUtil.NewFakeOperatorParams(fake.NewClientset(servingDeployment("activator")),
+		testingUtil.NewFakeOperatorClient())
+	err := teardownComponent(common.ServingComponent, []string{"knative-serving"}, uninstallCmdFlags{}, p)
+	testingUtil.AssertEqual(t, err, nil)
+}
+
+func servingDeployment(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "knative-serving",
+			Labels:    map[string]string{"app.kubernetes.io/name": "knative-serving"},
+		},
+	}
+}
@@ -18,44 +18,63 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"knative.dev/kn-plugin-operator/pkg/command/common"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc" // from https://github.com/kubernetes/client-go/issues/345
 	"knative.dev/kn-plugin-operator/pkg"
+	"knative.dev/kn-plugin-operator/pkg/command/install"
+	"knative.dev/operator/pkg/client/clientset/versioned"
 )
 
 type uninstallCmdFlags struct {
-	Component  string
-	Namespace  string
-	KubeConfig string
+	Component       string
+	Namespace       string
+	Version         string
+	Force           bool
+	Wait            bool
+	DeleteNamespace bool
+	Timeout         time.Duration
 }
 
-var (
-	uninstallFlags uninstallCmdFlags
-)
-
 // installCmd represents the install commands for the operation
 func NewUninstallCommand(p *pkg.OperatorParams) *cobra.Command {
+	var uninstallFlags uninstallCmdFlags
 	var uninstallCmd = &cobra.Command{
 		Use:   "uninstall",
 		Short: "Uninstall Knative Operator or Knative components",
 		Example: `
   # Uninstall Knative Serving under the namespace knative-serving
-  kn operation uninstall -c serving --namespace knative-serving`,
+  kn operation uninstall -c serving --namespace knative-serving
+
+  # Uninstall Knative Eventing, delete its namespace and wait until all the resources are removed
+  kn operation uninstall -c eventing --namespace knative-eventing --delete-namespace --wait --timeout 10m
+
+  # Uninstall the Knative Operator together with the existing Knative Serving and Knative Eventing
+  kn operation uninstall --force`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if strings.ToLower(uninstallFlags.Component) == common.ServingComponent {
 				// Uninstall the serving
-				if err := uninstallKnativeServing(uninstallFlags, p); err != nil {
+				namespaces, err := uninstallKnativeServing(uninstallFlags, p)
+				if err != nil {
+					return err
+				}
+				if err = teardownComponent(common.ServingComponent, namespaces, uninstallFlags, p); err != nil {
 					return err
 				}
 				fmt.Fprintf(cmd.OutOrStdout(), "Knative Serving was removed in the namespace '%s'.\n", uninstallFlags.Namespace)
 			} else if strings.ToLower(uninstallFlags.Component) == common.EventingComponent {
 				// Uninstall the eventing
-				if err := uninstallKnativeEventing(uninstallFlags, p); err != nil {
+				namespaces, err := uninstallKnativeEventing(uninstallFlags, p)
+				if err != nil {
+					return err
+				}
+				if err = teardownComponent(common.EventingComponent, namespaces, uninstallFlags, p); err != nil {
 					return err
 				}
 				fmt.Fprintf(cmd.OutOrStdout(), "Knative Eventing was removed in the namespace '%s'.\n", uninstallFlags.Namespace)
@@ -63,7 +82,7 @@ func NewUninstallCommand(p *pkg.OperatorParams) *cobra.Command {
 				return fmt.Errorf("Unknown component name: you need to set component name to serving or eventing.")
 			} else {
 				// Uninstall the Knative Operator
-				if err := uninstallOperator(uninstallFlags, p); err != nil {
+				if err := uninstallOperator(&uninstallFlags, p); err != nil {
 					return err
 				}
 				fmt.Fprintf(cmd.OutOrStdout(), "Knative operator was removed in the namespace '%s'.\n", uninstallFlags.Namespace)
@@ -75,65 +94,166 @@ func NewUninstallCommand(p *pkg.OperatorParams) *cobra.Command {
 
 	uninstallCmd.Flags().StringVarP(&uninstallFlags.Namespace, "namespace", "n", "", "The namespace of the Knative Operator or the Knative component")
 	uninstallCmd.Flags().StringVarP(&uninstallFlags.Component, "component", "c", "", "The name of the Knative Component to install")
+	uninstallCmd.Flags().StringVar(&uninstallFlags.Version, "version", "", "The version of the Knative Operator, if it cannot be determined from its deployment")
+	uninstallCmd.Flags().BoolVar(&uninstallFlags.Wait, "wait", false, "The flag to wait until the Knative component and its resources are removed")
+	uninstallCmd.Flags().DurationVar(&uninstallFlags.Timeout, "timeout", 5*time.Minute, "The maximum time to wait for the Knative component to be removed")
+	uninstallCmd.Flags().BoolVar(&uninstallFlags.DeleteNamespace, "delete-namespace", false, "The flag to delete the namespace of the Knative component")
+	uninstallCmd.Flags().BoolVar(&uninstallFlags.Force, "force", false, "The flag to uninstall the Knative Operator, even if Knative Serving or Knative Eventing still exists")
 
 	return uninstallCmd
 }
 
-func uninstallKnativeServing(uninstallFlags uninstallCmdFlags, p *pkg.OperatorParams) error {
+func uninstallKnativeServing(uninstallFlags uninstallCmdFlags, p *pkg.OperatorParams) ([]string, error) {
+	ctx := p.Context()
 	operatorClient, err := p.NewOperatorClient()
 	if err != nil {
-		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+		return nil, fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
 	}
 
-	list, err := operatorClient.OperatorV1beta1().KnativeServings(uninstallFlags.Namespace).List(context.TODO(), metav1.ListOptions{})
+	list, err := operatorClient.OperatorV1beta1().KnativeServings(uninstallFlags.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var errstrings []string
+	var namespaces []string
 	for _, ks := range list.Items {
-		if err = operatorClient.OperatorV1beta1().KnativeServings(uninstallFlags.Namespace).Delete(context.TODO(),
+		namespaces = append(namespaces, ks.Namespace)
+		if err = operatorClient.OperatorV1beta1().KnativeServings(ks.Namespace).Delete(ctx,
 			ks.Name, metav1.DeleteOptions{}); err != nil {
 			errstrings = append(errstrings, err.Error())
 		}
 	}
 
 	if len(errstrings) != 0 {
-		return fmt.Errorf("%s", strings.Join(errstrings, "\n"))
+		return nil, fmt.Errorf("%s", strings.Join(errstrings, "\n"))
 	}
-	return nil
+	return namespaces, nil
 }
 
-func uninstallKnativeEventing(uninstallFlags uninstallCmdFlags, p *pkg.OperatorParams) error {
+func uninstallKnativeEventing(uninstallFlags uninstallCmdFlags, p *pkg.OperatorParams) ([]string, error) {
+	ctx := p.Context()
 	operatorClient, err := p.NewOperatorClient()
 	if err != nil {
-		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+		return nil, fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
 	}
 
-	list, err := operatorClient.OperatorV1beta1().KnativeEventings(uninstallFlags.Namespace).List(context.TODO(), metav1.ListOptions{})
+	list, err := operatorClient.OperatorV1beta1().KnativeEventings(uninstallFlags.Namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var errstrings []string
+	var namespaces []string
 	for _, ke := range list.Items {
-		if err = operatorClient.OperatorV1beta1().KnativeEventings(uninstallFlags.Namespace).Delete(context.TODO(),
+		namespaces = append(namespaces, ke.Namespace)
+		if err = operatorClient.OperatorV1beta1().KnativeEventings(ke.Namespace).Delete(ctx,
 			ke.Name, metav1.DeleteOptions{}); err != nil {
 			errstrings = append(errstrings, err.Error())
 		}
 	}
 
 	if len(errstrings) != 0 {
-		return fmt.Errorf("%s", strings.Join(errstrings, "\n"))
+		return nil, fmt.Errorf("%s", strings.Join(errstrings, "\n"))
 	}
-	return nil
+	return namespaces, nil
 }
 
-func uninstallOperator(uninstallFlags uninstallCmdFlags, p *pkg.OperatorParams) error {
+func uninstallOperator(uninstallFlags *uninstallCmdFlags, p *pkg.OperatorParams) error {
+	ctx := p.Context()
 	client, err := p.NewKubeClient()
 	if err != nil {
 		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
 	}
 
-	return client.AppsV1().Deployments(uninstallFlags.Namespace).Delete(context.TODO(), common.KnativeOperatorName, metav1.DeleteOptions{})
+	deploy := common.Deployment{
+		Client: client,
+	}
+	exists, namespace, version, err := deploy.CheckIfOperatorInstalled(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("The Knative Operator is not installed.")
+	}
+	if uninstallFlags.Namespace != "" && !strings.EqualFold(uninstallFlags.Namespace, namespace) {
+		return fmt.Errorf("The namespace %s you specified is not consistent with the existing namespace %s for the Knative Operator",
+			uninstallFlags.Namespace, namespace)
+	}
+	if uninstallFlags.Version != "" && version != "" &&
+		common.SemanticVersion(uninstallFlags.Version) != common.SemanticVersion(version) {
+		return fmt.Errorf("The version %s you specified is not consistent with the existing version %s of the Knative Operator",
+			uninstallFlags.Version, version)
+	}
+	if version == "" {
+		version = uninstallFlags.Version
+	}
+	if version == "" {
+		return fmt.Errorf("The version of the Knative Operator in the namespace '%s' cannot be determined, "+
+			"please use --version to specify it.", namespace)
+	}
+
+	operatorClient, err := p.NewOperatorClient()
+	if err != nil {
+		return fmt.Errorf("cannot get source cluster kube config, please use --kubeconfig or export environment variable KUBECONFIG to set\n")
+	}
+	components, err := listKnativeComponents(ctx, operatorClient)
+	if err != nil {
+		return err
+	}
+	if len(components) != 0 {
+		if !uninstallFlags.Force {
+			return fmt.Errorf("The Knative Operator cannot be removed, because the following Knative components still exist:\n%s\n"+
+				"Uninstall them first, or use --force to remove them together with the Knative Operator.", strings.Join(components, "\n"))
+		}
+		// The Knative Operator needs to finalize the custom resources, before it is removed
+		teardownFlags := uninstallCmdFlags{Wait: true, Timeout: uninstallFlags.Timeout}
+		namespaces, err := uninstallKnativeServing(uninstallCmdFlags{}, p)
+		if err != nil {
+			return err
+		}
+		if err = teardownComponent(common.ServingComponent, namespaces, teardownFlags, p); err != nil {
+			return err
+		}
+		namespaces, err = uninstallKnativeEventing(uninstallCmdFlags{}, p)
+		if err != nil {
+			return err
+		}
+		if err = teardownComponent(common.EventingComponent, namespaces, teardownFlags, p); err != nil {
+			return err
+		}
+	}
+
+	uninstallFlags.Namespace = namespace
+	p.SetStep(fmt.Sprintf("Removing the resources of the Knative Operator in the namespace '%s'...", namespace))
+	return install.UninstallOperator(namespace, version, p)
+}
+
+// listKnativeComponents lists the Knative Serving and Knative Eventing custom resources in all namespaces
+func listKnativeComponents(ctx context.Context, operatorClient versioned.Interface) ([]string, error) {
+	var components []string
+	servings, err := operatorClient.OperatorV1beta1().KnativeServings("").List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, ks := range servings.Items {
+			components = append(components, formatComponent("KnativeServing", ks.Namespace, ks.Name))
+		}
+	}
+
+	eventings, err := operatorClient.OperatorV1beta1().KnativeEventings("").List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, ke := range eventings.Items {
+			components = append(components, formatComponent("KnativeEventing", ke.Namespace, ke.Name))
+		}
+	}
+	return components, nil
+}
+
+func formatComponent(kind, namespace, name string) string {
+	return fmt.Sprintf("  %s '%s' in the namespace '%s'", kind, name, namespace)
 }
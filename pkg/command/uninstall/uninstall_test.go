@@ -0,0 +1,109 @@
+// Copyright 2022 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uninstall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"knative.dev/kn-plugin-operator/pkg/command/common"
+	"knative.dev/kn-plugin-operator/pkg/command/testingUtil"
+	"knative.dev/operator/pkg/apis/operator/v1beta1"
+)
+
+func TestUninstallOperatorForceGuard(t *testing.T) {
+	kubeClient := fake.NewClientset(operatorObjects("1.18.0")...)
+	operatorClient := testingUtil.NewFakeOperatorClient(
+		&v1beta1.KnativeServing{ObjectMeta: metav1.ObjectMeta{Name: "knative-serving", Namespace: "knative-serving"}},
+		&v1beta1.KnativeEventing{ObjectMeta: metav1.ObjectMeta{Name: "knative-eventing", Namespace: "knative-eventing"}},
+	)
+	p := testingUtil.NewFakeOperatorParams(kubeClient, operatorClient)
+
+	err := uninstallOperator(&uninstallCmdFlags{Timeout: time.Minute}, p)
+	if err == nil {
+		t.Fatal("expected the Knative Operator not to be removed without --force")
+	}
+	testingUtil.AssertEqual(t, err.Error(), "The Knative Operator cannot be removed, because the following Knative components still exist:\n"+
+		"  KnativeServing 'knative-serving' in the namespace 'knative-serving'\n"+
+		"  KnativeEventing 'knative-eventing' in the namespace 'knative-eventing'\n"+
+		"Uninstall them first, or use --force to remove them together with the Knative Operator.")
+	_, err = operatorClient.OperatorV1beta1().KnativeServings("knative-serving").Get(context.Background(), "knative-serving", metav1.GetOptions{})
+	testingUtil.AssertEqual(t, err, nil)
+}
+
+func TestUninstallOperatorForce(t *testing.T) {
+	// The deployment of Knative Serving is not removed, because no Knative Operator finalizes the custom resource
+	kubeClient := fake.NewClientset(append(operatorObjects("1.18.0"), servingDeployment("activator"))...)
+	operatorClient := testingUtil.NewFakeOperatorClient(
+		&v1beta1.KnativeServing{ObjectMeta: metav1.ObjectMeta{Name: "knative-serving", Namespace: "knative-serving"}},
+	)
+	p := testingUtil.NewFakeOperatorParams(kubeClient, operatorClient)
+
+	err := uninstallOperator(&uninstallCmdFlags{Force: true, Timeout: 100 * time.Millisecond}, p)
+	if err == nil {
+		t.Fatal("expected the wait for the removal of Knative Serving to time out")
+	}
+	testingUtil.AssertEqual(t, err.Error(), "Knative serving was not removed from the namespace 'knative-serving' within 100ms, "+
+		"remaining deployments: activator")
+	_, err = operatorClient.OperatorV1beta1().KnativeServings("knative-serving").Get(context.Background(), "knative-serving", metav1.GetOptions{})
+	testingUtil.AssertEqual(t, apierrors.IsNotFound(err), true)
+}
+
+func TestUninstallOperatorNotInstalled(t *testing.T) {
+	p := testingUtil.NewFakeOperatorParams(fake.NewClientset(), testingUtil.NewFakeOperatorClient())
+	err := uninstallOperator(&uninstallCmdFlags{}, p)
+	if err == nil {
+		t.Fatal("expected the error of the missing Knative Operator")
+	}
+	testingUtil.AssertEqual(t, err.Error(), "The Knative Operator is not installed.")
+}
+
+func TestUninstallOperatorUnknownVersion(t *testing.T) {
+	p := testingUtil.NewFakeOperatorParams(fake.NewClientset(operatorObjects("")...), testingUtil.NewFakeOperatorClient())
+	err := uninstallOperator(&uninstallCmdFlags{}, p)
+	if err == nil {
+		t.Fatal("expected the error of the unknown version of the Knative Operator")
+	}
+	testingUtil.AssertEqual(t, err.Error(), "The version of the Knative Operator in the namespace 'knative-operator' cannot be determined, "+
+		"please use --version to specify it.")
+}
+
+func TestUninstallOperatorInconsistentVersion(t *testing.T) {
+	p := testingUtil.NewFakeOperatorParams(fake.NewClientset(operatorObjects("1.18.0")...), testingUtil.NewFakeOperatorClient())
+	err := uninstallOperator(&uninstallCmdFlags{Version: "1.17"}, p)
+	if err == nil {
+		t.Fatal("expected the error of the inconsistent version of the Knative Operator")
+	}
+	testingUtil.AssertEqual(t, err.Error(), "The version 1.17 you specified is not consistent with the existing version 1.18.0 of the Knative Operator")
+}
+
+func operatorObjects(version string) []runtime.Object {
+	return []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "knative-operator"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      common.KnativeOperatorName,
+			Namespace: "knative-operator",
+			Labels:    map[string]string{"app.kubernetes.io/version": version},
+		}},
+	}
+}
@@ -15,9 +15,12 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -30,7 +33,69 @@ type OperatorParams struct {
 	KubeCfgPath       string
 	ClientConfig      clientcmd.ClientConfig
 	NewKubeClient     func() (kubernetes.Interface, error)
-	NewOperatorClient func() (*versioned.Clientset, error)
+	NewOperatorClient func() (versioned.Interface, error)
+	// ConfigOverrides overrides the context, the cluster, the user and the impersonation loaded from the kubeconfig
+	ConfigOverrides clientcmd.ConfigOverrides
+	// ForceConflicts takes over the fields managed by other field managers, e.g. Argo CD or Flux,
+	// when the plugin applies the resources with the server-side apply
+	ForceConflicts bool
+	// Ctx is the context of the operations, which is canceled when the plugin is interrupted
+	Ctx context.Context
+	// ErrOut receives the warnings of the operations. The warnings are dropped if it is not set.
+	ErrOut io.Writer
+	// StepReporter receives every step set on the params, e.g. to combine the steps of the operations run
+	// in parallel with their own params
+	StepReporter func(step string)
+
+	stepLock sync.Mutex
+	step     string
+}
+
+// Context returns the context of the operations. The background context is used if none is set.
+func (params *OperatorParams) Context() context.Context {
+	if params.Ctx == nil {
+		return context.Background()
+	}
+	return params.Ctx
+}
+
+// WithContext returns a copy of the params using the context for the operations
+func (params *OperatorParams) WithContext(ctx context.Context) *OperatorParams {
+	return &OperatorParams{
+		KubeCfgPath:       params.KubeCfgPath,
+		ConfigOverrides:   params.ConfigOverrides,
+		ClientConfig:      params.ClientConfig,
+		NewKubeClient:     params.NewKubeClient,
+		NewOperatorClient: params.NewOperatorClient,
+		ForceConflicts:    params.ForceConflicts,
+		Ctx:               ctx,
+		ErrOut:            params.ErrOut,
+		StepReporter:      params.StepReporter,
+	}
+}
+
+// Warn reports a warning, which does not stop the operation
+func (params *OperatorParams) Warn(format string, a ...interface{}) {
+	if params.ErrOut != nil {
+		fmt.Fprintf(params.ErrOut, "Warning: "+format+"\n", a...)
+	}
+}
+
+// SetStep records the step being run, so that it can be reported when the operation is interrupted
+func (params *OperatorParams) SetStep(step string) {
+	params.stepLock.Lock()
+	params.step = step
+	params.stepLock.Unlock()
+	if params.StepReporter != nil {
+		params.StepReporter(step)
+	}
+}
+
+// Step returns the step being run
+func (params *OperatorParams) Step() string {
+	params.stepLock.Lock()
+	defer params.stepLock.Unlock()
+	return params.step
 }
 
 // Initialize generate the clientset for params
@@ -63,17 +128,17 @@ func (params *OperatorParams) RestConfig() (*rest.Config, error) {
 	return config, nil
 }
 
-// GetClientConfig gets ClientConfig from KubeCfgPath
+// GetClientConfig gets ClientConfig from KubeCfgPath, applying the ConfigOverrides
 func (params *OperatorParams) GetClientConfig() (clientcmd.ClientConfig, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if len(params.KubeCfgPath) == 0 {
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}), nil
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &params.ConfigOverrides), nil
 	}
 
 	_, err := os.Stat(params.KubeCfgPath)
 	if err == nil {
 		loadingRules.ExplicitPath = params.KubeCfgPath
-		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}), nil
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &params.ConfigOverrides), nil
 	}
 
 	if !os.IsNotExist(err) {
@@ -89,13 +154,17 @@ func (params *OperatorParams) GetClientConfig() (clientcmd.ClientConfig, error)
 }
 
 // newOperatorClient creates an operator clientset from kubenetes config
-func (params *OperatorParams) newOperatorClient() (*versioned.Clientset, error) {
+func (params *OperatorParams) newOperatorClient() (versioned.Interface, error) {
 	restConfig, err := params.RestConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return versioned.NewForConfig(restConfig)
+	client, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
 }
 
 // newKubeClient creates a kubenetes clientset from kubenetes config